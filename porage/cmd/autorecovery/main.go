@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"porage/pkg"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	metadataDir string
+	poraAddrs   []string
+	lostAddr    string
+	isDryRun    bool
+)
+
+func main() {
+	var rootCmd = &cobra.Command{
+		Use:   "porage-autorecovery",
+		Short: "Re-replicate the entries of the distributed ledgers which lost a Pora",
+		Long: "Re-replicate the entries of the distributed ledgers which lost a Pora. The metadata of the ledgers is read " +
+			"from the JSON files in the metadata directory, the lost Pora is replaced in their ensembles by another Pora " +
+			"of the pool, and the entries it held are copied onto the replacement before the metadata files are updated.",
+		RunE: runAutoRecovery,
+	}
+
+	rootCmd.PersistentFlags().StringVarP(&metadataDir, "metadata-dir", "m", "", "Directory of the ledger metadata files")
+	rootCmd.PersistentFlags().StringSliceVarP(&poraAddrs, "poras", "p", nil, "Addresses of the Poras to choose the replacements from")
+	rootCmd.PersistentFlags().StringVarP(&lostAddr, "lost", "l", "", "Address of the lost Pora")
+	rootCmd.PersistentFlags().BoolVarP(&isDryRun, "dry-run", "n", false, "Only list the ledgers to recover")
+
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func runAutoRecovery(cmd *cobra.Command, args []string) error {
+	if metadataDir == "" || lostAddr == "" {
+		return errors.New("the metadata directory and the lost Pora are required but not provided")
+	}
+	paths, err := filepath.Glob(filepath.Join(metadataDir, "*.json"))
+	if err != nil {
+		return err
+	}
+	pool := pkg.NewPoraPool(poraAddrs)
+	defer pool.Close()
+	autoRecovery := pkg.NewAutoRecovery(pool)
+	ctx := context.Background()
+
+	nRecovered := 0
+	nFailed := 0
+	for _, path := range paths {
+		metadata, err := loadMetadata(path)
+		if err != nil {
+			fmt.Printf("Skipped %s: %v\n", path, err)
+			nFailed++
+			continue
+		}
+		if !includesPora(metadata, lostAddr) {
+			continue
+		}
+		if isDryRun {
+			fmt.Printf("Ledger %d needs recovery\n", metadata.LedgerID)
+			continue
+		}
+		recovered, nCopied, err := autoRecovery.RecoverLedger(ctx, metadata, lostAddr)
+		if err == nil {
+			err = saveMetadata(path, recovered)
+		}
+		if err != nil {
+			fmt.Printf("Failed to recover ledger %d after copying %d entries: %v\n", metadata.LedgerID, nCopied, err)
+			nFailed++
+			continue
+		}
+		fmt.Printf("Recovered ledger %d, copied %d entries\n", metadata.LedgerID, nCopied)
+		nRecovered++
+	}
+	fmt.Printf("Recovered %d ledgers, %d failed\n", nRecovered, nFailed)
+	if nFailed > 0 {
+		return fmt.Errorf("failed to recover %d ledgers", nFailed)
+	}
+	return nil
+}
+
+// includesPora returns whether addr is in an ensemble of the ledger.
+func includesPora(metadata pkg.DistributedLedgerMetadata, addr string) bool {
+	for _, segment := range metadata.Segments {
+		if slices.Contains(segment.Poras, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadMetadata reads the metadata of a ledger from a JSON file.
+func loadMetadata(path string) (pkg.DistributedLedgerMetadata, error) {
+	metadata := pkg.DistributedLedgerMetadata{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return metadata, err
+	}
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return metadata, err
+	}
+	if len(metadata.Segments) == 0 {
+		return metadata, errors.New("no ensemble segment")
+	}
+	return metadata, nil
+}
+
+// saveMetadata replaces the JSON file of the metadata of a ledger through a temporary file, so that a crash leaves
+// either the old or the new metadata. The temporary file is synced before it is renamed, and the directory is synced
+// after, so that the new metadata survives a power failure once saveMetadata returns.
+func saveMetadata(path string, metadata pkg.DistributedLedgerMetadata) error {
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
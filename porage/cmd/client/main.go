@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -16,19 +17,32 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+const (
+	// readEntriesMaxBytes is the max payload size of the entries read by read-entries.
+	readEntriesMaxBytes = 1 << 20
+)
+
 var (
 	porageClient *pkg.PorageClient
 
 	commandUsageMapping = map[string]string{
-		"create-ledger": "create-ledger <ledger_id>",
-		"append-entry":  "append-entry <ledger_id> <payload>",
-		"get-entry":     "get-entry <ledger_id> <entry_id>",
-		"close-ledger":  "close-ledger <ledger_id>",
-		"list-ledgers":  "list-ledgers",
-		"list-workers":  "list-workers",
-		"ledger-len":    "ledger-len <ledger_id>",
-		"help":          "show help information",
-		"quit":          "exit the client",
+		"create-ledger":                 "create-ledger <ledger_id>",
+		"create-ledger-with-retention":  "create-ledger-with-retention <ledger_id> <max_age_seconds> <max_bytes> <max_entries>",
+		"create-ledger-with-properties": "create-ledger-with-properties <ledger_id> <key=value> [<key=value> ...]",
+		"append-entry":                  "append-entry <ledger_id> <payload>",
+		"append-entry-with-id":          "append-entry-with-id <ledger_id> <entry_id> <payload>",
+		"get-entry":                     "get-entry <ledger_id> <entry_id>",
+		"read-entries":                  "read-entries <ledger_id> <from_entry_id> <to_entry_id>",
+		"close-ledger":                  "close-ledger <ledger_id>",
+		"delete-ledger":                 "delete-ledger <ledger_id>",
+		"list-ledgers":                  "list-ledgers",
+		"list-workers":                  "list-workers",
+		"ledger-len":                    "ledger-len <ledger_id>",
+		"fence-ledger":                  "fence-ledger <ledger_id>",
+		"trim-ledger":                   "trim-ledger <ledger_id> <until_entry_id>",
+		"ledger-info":                   "ledger-info <ledger_id>",
+		"help":                          "show help information",
+		"quit":                          "exit the client",
 	}
 )
 
@@ -104,18 +118,34 @@ func handleCommand(input string) {
 	switch parts[0] {
 	case "create-ledger":
 		handleCreateLedger(parts, ctx)
+	case "create-ledger-with-retention":
+		handleCreateLedgerWithRetention(parts, ctx)
+	case "create-ledger-with-properties":
+		handleCreateLedgerWithProperties(parts, ctx)
 	case "append-entry":
 		handleAppendEntry(parts, ctx)
+	case "append-entry-with-id":
+		handleAppendEntryWithID(parts, ctx)
 	case "get-entry":
 		handleGetEntry(parts, ctx)
+	case "read-entries":
+		handleReadEntries(parts, ctx)
 	case "close-ledger":
 		handleCloseLedger(parts, ctx)
+	case "delete-ledger":
+		handleDeleteLedger(parts, ctx)
 	case "list-ledgers":
 		handleListLedgers(parts, ctx)
 	case "list-workers":
 		handleListWorkers(parts, ctx)
 	case "ledger-len":
 		handleLedgerLength(parts, ctx)
+	case "fence-ledger":
+		handleFenceLedger(parts, ctx)
+	case "trim-ledger":
+		handleTrimLedger(parts, ctx)
+	case "ledger-info":
+		handleLedgerInfo(parts, ctx)
 	case "help":
 		showHelp(parts)
 	case "quit":
@@ -143,6 +173,64 @@ func handleCreateLedger(parts []string, ctx context.Context) {
 	}
 }
 
+func handleCreateLedgerWithRetention(parts []string, ctx context.Context) {
+	if !isValidCommandUsageLen(parts, 5) {
+		return
+	}
+	ledgerID, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		fmt.Printf("Invalid ledger ID: %v\n", err)
+		return
+	}
+	limits := make([]int64, 0, 3)
+	for _, part := range parts[2:] {
+		limit, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			fmt.Printf("Invalid retention limit: %v\n", err)
+			return
+		}
+		limits = append(limits, limit)
+	}
+	retentionPolicy := pkg.RetentionPolicy{
+		MaxAge:     time.Duration(limits[0]) * time.Second,
+		MaxBytes:   limits[1],
+		MaxEntries: limits[2],
+	}
+	err = porageClient.CreateLedgerWithOptions(ctx, ledgerID, pkg.CreateLedgerOptions{RetentionPolicy: retentionPolicy})
+	if err != nil {
+		fmt.Printf("Failed to create ledger: %v\n", status.Convert(err).Message())
+	} else {
+		fmt.Println("Ledger created successfully")
+	}
+}
+
+func handleCreateLedgerWithProperties(parts []string, ctx context.Context) {
+	if len(parts) < 3 {
+		fmt.Printf("Usage: %v\n", commandUsageMapping[parts[0]])
+		return
+	}
+	ledgerID, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		fmt.Printf("Invalid ledger ID: %v\n", err)
+		return
+	}
+	properties := make(map[string]string, len(parts)-2)
+	for _, part := range parts[2:] {
+		key, value, found := strings.Cut(part, "=")
+		if !found || key == "" {
+			fmt.Printf("Invalid property: %s\n", part)
+			return
+		}
+		properties[key] = value
+	}
+	err = porageClient.CreateLedgerWithOptions(ctx, ledgerID, pkg.CreateLedgerOptions{Properties: properties})
+	if err != nil {
+		fmt.Printf("Failed to create ledger: %v\n", status.Convert(err).Message())
+	} else {
+		fmt.Println("Ledger created successfully")
+	}
+}
+
 func handleAppendEntry(parts []string, ctx context.Context) {
 	if !isValidCommandUsageLen(parts, 3) {
 		return
@@ -161,6 +249,29 @@ func handleAppendEntry(parts []string, ctx context.Context) {
 	}
 }
 
+func handleAppendEntryWithID(parts []string, ctx context.Context) {
+	if !isValidCommandUsageLen(parts, 4) {
+		return
+	}
+	ledgerID, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		fmt.Printf("Invalid ledger ID: %v\n", err)
+		return
+	}
+	entryID, err := strconv.Atoi(parts[2])
+	if err != nil {
+		fmt.Printf("Invalid entry ID: %v\n", err)
+		return
+	}
+	payload := parts[3]
+	err = porageClient.AppendEntryWithID(ctx, ledgerID, entryID, []byte(payload))
+	if err != nil {
+		fmt.Printf("Failed to append entry: %v\n", status.Convert(err).Message())
+	} else {
+		fmt.Printf("Entry appended successfully, Entry ID: %d\n", entryID)
+	}
+}
+
 func handleGetEntry(parts []string, ctx context.Context) {
 	if !isValidCommandUsageLen(parts, 3) {
 		return
@@ -183,6 +294,43 @@ func handleGetEntry(parts []string, ctx context.Context) {
 	}
 }
 
+func handleReadEntries(parts []string, ctx context.Context) {
+	if !isValidCommandUsageLen(parts, 4) {
+		return
+	}
+	ledgerID, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		fmt.Printf("Invalid ledger ID: %v\n", err)
+		return
+	}
+	fromEntryID, err := strconv.Atoi(parts[2])
+	if err != nil {
+		fmt.Printf("Invalid entry ID: %v\n", err)
+		return
+	}
+	toEntryID, err := strconv.Atoi(parts[3])
+	if err != nil {
+		fmt.Printf("Invalid entry ID: %v\n", err)
+		return
+	}
+	entries, err := porageClient.ReadEntries(ctx, ledgerID, fromEntryID, toEntryID, readEntriesMaxBytes)
+	if err != nil {
+		fmt.Printf("Failed to read entries: %v\n", status.Convert(err).Message())
+		return
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No entries found")
+		return
+	}
+
+	tableContent := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		tableContent = append(tableContent, []string{strconv.Itoa(entry.EntryID), string(entry.Payload)})
+	}
+	renderTable([]string{"Entry ID", "Payload"}, tableContent)
+}
+
 func handleCloseLedger(parts []string, ctx context.Context) {
 	if !isValidCommandUsageLen(parts, 2) {
 		return
@@ -192,11 +340,28 @@ func handleCloseLedger(parts []string, ctx context.Context) {
 		fmt.Printf("Invalid ledger ID: %v\n", err)
 		return
 	}
-	err = porageClient.DeleteLedger(ctx, ledgerID)
+	lastEntryID, err := porageClient.CloseLedger(ctx, ledgerID)
 	if err != nil {
 		fmt.Printf("Failed to close ledger: %v\n", status.Convert(err).Message())
 	} else {
-		fmt.Println("Ledger closed successfully")
+		fmt.Printf("Ledger closed successfully, Last Entry ID: %d\n", lastEntryID)
+	}
+}
+
+func handleDeleteLedger(parts []string, ctx context.Context) {
+	if !isValidCommandUsageLen(parts, 2) {
+		return
+	}
+	ledgerID, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		fmt.Printf("Invalid ledger ID: %v\n", err)
+		return
+	}
+	err = porageClient.DeleteLedger(ctx, ledgerID)
+	if err != nil {
+		fmt.Printf("Failed to delete ledger: %v\n", status.Convert(err).Message())
+	} else {
+		fmt.Println("Ledger deleted successfully")
 	}
 }
 
@@ -226,24 +391,37 @@ func handleListWorkers(parts []string, ctx context.Context) {
 	if !isValidCommandUsageLen(parts, 1) {
 		return
 	}
-	workerDescriptions, err := porageClient.GetWorkerDescriptions(ctx)
+	workerStatuses, err := porageClient.ListWorkers(ctx)
 	if err != nil {
 		fmt.Printf("Failed to list workers: %v\n", status.Convert(err).Message())
 		return
 	}
 
-	if len(workerDescriptions) == 0 {
+	if len(workerStatuses) == 0 {
 		fmt.Println("No workers found")
 		return
 	}
 
-	tableContent := make([][]string, 0, len(workerDescriptions))
-	id := 1
-	for workerName, description := range workerDescriptions {
-		tableContent = append(tableContent, []string{strconv.Itoa(id), workerName, description})
-		id++
+	workerNames := make([]string, 0, len(workerStatuses))
+	for workerName := range workerStatuses {
+		workerNames = append(workerNames, workerName)
+	}
+	sort.Strings(workerNames)
+	tableContent := make([][]string, 0, len(workerStatuses))
+	for id, workerName := range workerNames {
+		workerStatus := workerStatuses[workerName]
+		tableContent = append(tableContent, []string{
+			strconv.Itoa(id + 1),
+			workerName,
+			workerStatus.Description,
+			workerStatus.LastHeartbeat.Local().Format(time.DateTime),
+			strconv.FormatUint(workerStatus.Processed, 10),
+			strconv.Itoa(workerStatus.QueueDepth),
+			strconv.FormatBool(workerStatus.Stuck),
+		})
 	}
-	renderTable([]string{"ID", "Worker Name", "Description"}, tableContent)
+	renderTable([]string{"ID", "Worker Name", "Description", "Last Heartbeat", "Processed", "Queue Depth", "Stuck"},
+		tableContent)
 }
 
 func handleLedgerLength(parts []string, ctx context.Context) {
@@ -263,6 +441,85 @@ func handleLedgerLength(parts []string, ctx context.Context) {
 	}
 }
 
+func handleFenceLedger(parts []string, ctx context.Context) {
+	if !isValidCommandUsageLen(parts, 2) {
+		return
+	}
+	ledgerID, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		fmt.Printf("Invalid ledger ID: %v\n", err)
+		return
+	}
+	lastEntryID, err := porageClient.FenceLedger(ctx, ledgerID)
+	if err != nil {
+		fmt.Printf("Failed to fence ledger: %v\n", status.Convert(err).Message())
+	} else {
+		fmt.Printf("Ledger fenced successfully, Last Entry ID: %d\n", lastEntryID)
+	}
+}
+
+func handleTrimLedger(parts []string, ctx context.Context) {
+	if !isValidCommandUsageLen(parts, 3) {
+		return
+	}
+	ledgerID, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		fmt.Printf("Invalid ledger ID: %v\n", err)
+		return
+	}
+	untilEntryID, err := strconv.Atoi(parts[2])
+	if err != nil {
+		fmt.Printf("Invalid entry ID: %v\n", err)
+		return
+	}
+	if err := porageClient.TrimLedger(ctx, ledgerID, untilEntryID); err != nil {
+		fmt.Printf("Failed to trim ledger: %v\n", status.Convert(err).Message())
+	} else {
+		fmt.Printf("Ledger trimmed successfully until entry %d\n", untilEntryID)
+	}
+}
+
+func handleLedgerInfo(parts []string, ctx context.Context) {
+	if !isValidCommandUsageLen(parts, 2) {
+		return
+	}
+	ledgerID, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		fmt.Printf("Invalid ledger ID: %v\n", err)
+		return
+	}
+	info, err := porageClient.GetLedgerInfo(ctx, ledgerID)
+	if err != nil {
+		fmt.Printf("Failed to get ledger info: %v\n", status.Convert(err).Message())
+		return
+	}
+
+	lastAppendTime := "-"
+	if !info.LastAppendTime.IsZero() {
+		lastAppendTime = info.LastAppendTime.Local().Format(time.DateTime)
+	}
+	tableContent := [][]string{
+		{"Creation Time", info.CreationTime.Local().Format(time.DateTime)},
+		{"State", info.State.String()},
+		{"Entry Count", strconv.FormatInt(info.EntryCount, 10)},
+		{"Byte Size", strconv.FormatInt(info.ByteSize, 10)},
+		{"First Entry ID", strconv.Itoa(info.FirstEntryID)},
+		{"Last Append Time", lastAppendTime},
+		{"Retention Max Age", info.RetentionPolicy.MaxAge.String()},
+		{"Retention Max Bytes", strconv.FormatInt(info.RetentionPolicy.MaxBytes, 10)},
+		{"Retention Max Entries", strconv.FormatInt(info.RetentionPolicy.MaxEntries, 10)},
+	}
+	keys := make([]string, 0, len(info.Properties))
+	for key := range info.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		tableContent = append(tableContent, []string{"Property " + key, info.Properties[key]})
+	}
+	renderTable([]string{"Field", "Value"}, tableContent)
+}
+
 func showHelp(parts []string) {
 	if !isValidCommandUsageLen(parts, 1) {
 		return
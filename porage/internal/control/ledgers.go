@@ -1,6 +1,7 @@
 package control
 
 import (
+	"errors"
 	"porage/internal/ledger"
 	porage "porage/pkg"
 	"sync"
@@ -28,18 +29,20 @@ func (lc *LedgerControl) AddLedger(l *ledger.Ledger) {
 	lc.ledgerRepo[l.LedgerID()] = l
 }
 
-// CreateLedger creates a new ledger with the given ledgerID. If the ledger already exists, it returns error.
+// CreateLedger creates a new ledger with the given ledgerID and options. If the ledger already exists, it returns
+// error.
 //
 // This function is thread-safe.
-func (lc *LedgerControl) CreateLedger(ledgerID uint64) error {
+func (lc *LedgerControl) CreateLedger(ledgerID uint64, options ledger.CreateOptions) error {
 	lc.ledgerRepoLock.Lock()
 	defer lc.ledgerRepoLock.Unlock()
 
-	if _, ok := lc.ledgerRepo[ledgerID]; ok {
+	// A ledger deleted by its retention policy is left in the repo until it is looked up, and replaced here.
+	if l, ok := lc.ledgerRepo[ledgerID]; ok && !l.IsDeleted() {
 		return porage.ErrLedgerExisted
 	}
 
-	l, err := ledger.NewLedger(ledgerID)
+	l, err := ledger.NewLedgerWithOptions(ledgerID, options)
 	if err != nil {
 		return err
 	}
@@ -47,18 +50,41 @@ func (lc *LedgerControl) CreateLedger(ledgerID uint64) error {
 	return nil
 }
 
-// GetLedger returns the ledger with the given ledgerID. If the ledger does not exist, it returns nil.
+// GetLedger returns the ledger with the given ledgerID. If the ledger does not exist or is deleted by its retention
+// policy, it returns nil. A ledger deleted by its retention policy is removed from the ledger control.
 //
 // This function is thread-safe.
 func (lc *LedgerControl) GetLedger(ledgerID uint64) *ledger.Ledger {
 	lc.ledgerRepoLock.RLock()
-	defer lc.ledgerRepoLock.RUnlock()
-
 	l := lc.ledgerRepo[ledgerID]
+	lc.ledgerRepoLock.RUnlock()
+
+	if l == nil {
+		return nil
+	}
+	if l.IsDeleted() {
+		lc.evictDeletedLedgers([]*ledger.Ledger{l})
+		return nil
+	}
 	return l
 }
 
-// RemoveLedger closes the ledger with the given ledgerID and removes it from the ledger control.
+// evictDeletedLedgers removes the deleted ledgers from the ledger control, unless they are replaced by new ledgers
+// with the same IDs already.
+//
+// This function is thread-safe.
+func (lc *LedgerControl) evictDeletedLedgers(deletedLedgers []*ledger.Ledger) {
+	lc.ledgerRepoLock.Lock()
+	defer lc.ledgerRepoLock.Unlock()
+
+	for _, l := range deletedLedgers {
+		if lc.ledgerRepo[l.LedgerID()] == l {
+			delete(lc.ledgerRepo, l.LedgerID())
+		}
+	}
+}
+
+// RemoveLedger deletes the ledger with the given ledgerID and removes it from the ledger control.
 // If the ledger does not exist, it returns error.
 //
 // This function is thread-safe.
@@ -70,24 +96,33 @@ func (lc *LedgerControl) RemoveLedger(ledgerID uint64) error {
 	if !ok {
 		return porage.ErrLedgerNotFound
 	}
-	err := ledger.Close()
-	if err != nil {
+	err := ledger.Delete()
+	if err != nil && !errors.Is(err, porage.ErrLedgerNotFound) {
 		return err
 	}
 	delete(lc.ledgerRepo, ledgerID)
-	return nil
+	return err
 }
 
-// ListLedgers returns a list of ledgerIDs in the ledger control.
+// ListLedgers returns a list of ledgerIDs in the ledger control. The ledgers deleted by their retention policies are
+// removed from the ledger control instead.
 //
 // This function is thread-safe.
 func (lc *LedgerControl) ListLedgers() []uint64 {
 	lc.ledgerRepoLock.RLock()
-	defer lc.ledgerRepoLock.RUnlock()
-
 	ledgerIDs := make([]uint64, 0, len(lc.ledgerRepo))
-	for ledgerID := range lc.ledgerRepo {
+	deletedLedgers := make([]*ledger.Ledger, 0)
+	for ledgerID, l := range lc.ledgerRepo {
+		if l.IsDeleted() {
+			deletedLedgers = append(deletedLedgers, l)
+			continue
+		}
 		ledgerIDs = append(ledgerIDs, ledgerID)
 	}
+	lc.ledgerRepoLock.RUnlock()
+
+	if len(deletedLedgers) > 0 {
+		lc.evictDeletedLedgers(deletedLedgers)
+	}
 	return ledgerIDs
 }
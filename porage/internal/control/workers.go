@@ -2,29 +2,57 @@ package control
 
 import (
 	"maps"
+	entrylogger "porage/internal/entry_logger"
 	"porage/internal/journal"
 	"porage/internal/ledger"
 	"porage/internal/pkg"
+	"porage/internal/registry"
+	"sort"
 	"sync"
+	"time"
+)
+
+const (
+	// DefaultWatchdogWindow is the watchdog window used when none is configured.
+	DefaultWatchdogWindow = 30 * time.Second
+	// watchdogInterval is the interval at which the watchdog checks the workers.
+	watchdogInterval = time.Second
 )
 
 type WorkerControl struct {
 	workerRepo     map[string]*pkg.WorkerDescription
 	workerRepoLock *sync.RWMutex
+
+	// watchdogWindow is the window within which a worker is expected to make progress.
+	watchdogWindow      time.Duration
+	watchdogStopChannel chan struct{}
 }
 
-func NewWorkerControl() *WorkerControl {
+// NewWorkerControl creates a WorkerControl whose watchdog flags the workers making no progress within watchdogWindow.
+// A non-positive watchdogWindow falls back to DefaultWatchdogWindow.
+func NewWorkerControl(watchdogWindow time.Duration) *WorkerControl {
+	if watchdogWindow <= 0 {
+		watchdogWindow = DefaultWatchdogWindow
+	}
 	return &WorkerControl{
-		workerRepo:     make(map[string]*pkg.WorkerDescription),
-		workerRepoLock: &sync.RWMutex{},
+		workerRepo:          make(map[string]*pkg.WorkerDescription),
+		workerRepoLock:      &sync.RWMutex{},
+		watchdogWindow:      watchdogWindow,
+		watchdogStopChannel: make(chan struct{}),
 	}
 }
 
-// List returns a list of worker descriptions.
+// WatchdogWindow returns the window within which a worker is expected to make progress.
+func (wc *WorkerControl) WatchdogWindow() time.Duration {
+	return wc.watchdogWindow
+}
+
+// List returns a list of worker descriptions. The stopped workers are not listed.
 func (wc *WorkerControl) List() map[string]*pkg.WorkerDescription {
-	wc.workerRepoLock.RLock()
-	defer wc.workerRepoLock.RUnlock()
+	wc.workerRepoLock.Lock()
+	defer wc.workerRepoLock.Unlock()
 
+	wc.workerRepo = make(map[string]*pkg.WorkerDescription)
 	journalWorkerDescriptions := journal.GetWorkerDescriptions()
 	for workerName, description := range journalWorkerDescriptions {
 		wc.workerRepo[workerName] = description
@@ -35,5 +63,62 @@ func (wc *WorkerControl) List() map[string]*pkg.WorkerDescription {
 		wc.workerRepo[workerName] = description
 	}
 
+	entryLoggerWorkerDescriptions := entrylogger.GetWorkerDescriptions()
+	for workerName, description := range entryLoggerWorkerDescriptions {
+		wc.workerRepo[workerName] = description
+	}
+
+	registryWorkerDescriptions := registry.GetWorkerDescriptions()
+	for workerName, description := range registryWorkerDescriptions {
+		wc.workerRepo[workerName] = description
+	}
+
 	return maps.Clone(wc.workerRepo)
 }
+
+// StuckWorkers returns the names of the workers which have made no progress within the watchdog window.
+func (wc *WorkerControl) StuckWorkers() []string {
+	stuckWorkers := make([]string, 0)
+	for workerName, description := range wc.List() {
+		if description.IsStuck(wc.watchdogWindow) {
+			stuckWorkers = append(stuckWorkers, workerName)
+		}
+	}
+	sort.Strings(stuckWorkers)
+	return stuckWorkers
+}
+
+// Watch runs the watchdog, which logs the workers when they get stuck and when they make progress again, until
+// StopWatch is called.
+//
+// This function blocks.
+func (wc *WorkerControl) Watch() {
+	ticker := time.NewTicker(watchdogInterval)
+	defer ticker.Stop()
+	flaggedWorkers := make(map[string]struct{})
+	for {
+		select {
+		case <-ticker.C:
+			stuckWorkers := make(map[string]struct{})
+			for _, workerName := range wc.StuckWorkers() {
+				stuckWorkers[workerName] = struct{}{}
+				if _, ok := flaggedWorkers[workerName]; !ok {
+					pkg.Logger.Warningf("Watchdog: worker %s has made no progress within %v", workerName, wc.watchdogWindow)
+				}
+			}
+			for workerName := range flaggedWorkers {
+				if _, ok := stuckWorkers[workerName]; !ok {
+					pkg.Logger.Infof("Watchdog: worker %s is making progress again", workerName)
+				}
+			}
+			flaggedWorkers = stuckWorkers
+		case <-wc.watchdogStopChannel:
+			return
+		}
+	}
+}
+
+// StopWatch stops the watchdog.
+func (wc *WorkerControl) StopWatch() {
+	close(wc.watchdogStopChannel)
+}
@@ -1,22 +1,43 @@
 package entrylogger
 
 import (
+	"cmp"
 	"os"
+	"porage/internal/metrics"
 	"porage/internal/pkg"
+	"slices"
+	"sync"
+	"time"
 )
 
 // EntryLogger is the entry logger for a ledger.
+//
+// In the per-ledger mode, the entries are written to the file of the ledger. In the interleaved mode, the entries are
+// kept in pendingEntries until Flush, which writes them to the interleaved entry logs shared by the ledgers, and the
+// offsets of the entries are their positions made by makePosition.
 type EntryLogger struct {
 	ledgerID uint64
 	file     *os.File
+	// version is the on-disk format version of the file.
+	version uint16
+
+	entryMetadata  []*EntryMetadata
+	pendingEntries []*pkg.LedgerEntry
 
-	entryMetadata []*EntryMetadata
+	// relocationRequests carries the entries relocated by the compaction to the owner of the index of the ledger.
+	// It is nil in the per-ledger mode.
+	relocationRequests chan *RelocationRequest
+	// closedChannel is closed when the entry logger is closed, so that the compaction stops waiting for it.
+	closedChannel chan struct{}
+	closeOnce     *sync.Once
 }
 
 type EntryMetadata struct {
 	EntryID int
 	Offset  int
 	Size    int
+	// AppendTime is the AppendTime of the entry, which is 0 if it is unknown.
+	AppendTime int64
 }
 
 func NewEntryMetadata(entryID int, offset int, size int) *EntryMetadata {
@@ -27,20 +48,62 @@ func NewEntryMetadata(entryID int, offset int, size int) *EntryMetadata {
 	}
 }
 
+// Relocation is an entry copied by the compaction from OldOffset to NewOffset. Skipped is set by the owner of the index
+// if the index no longer points at OldOffset, in which case the copy is dead.
+type Relocation struct {
+	EntryID   int
+	OldOffset int
+	NewOffset int
+	Size      int
+	Skipped   bool
+}
+
+// RelocationRequest asks the owner of the index of a ledger to point the index at the relocated entries. The entries
+// stay readable at both offsets until Done is called.
+type RelocationRequest struct {
+	Relocations []*Relocation
+	doneChannel chan error
+}
+
+// Done reports that the index is updated and synced, or the error which stopped it.
+func (rr *RelocationRequest) Done(err error) {
+	rr.doneChannel <- err
+}
+
 // NewEntryLogger creates a new entry logger with the given ledgerID.
 func NewEntryLogger(ledgerID uint64) (*EntryLogger, error) {
+	if isInterleaved() {
+		entryLogger := &EntryLogger{
+			ledgerID:           ledgerID,
+			version:            fileVersionInterleaved,
+			relocationRequests: make(chan *RelocationRequest),
+			closedChannel:      make(chan struct{}),
+			closeOnce:          &sync.Once{},
+		}
+		registerLedger(entryLogger)
+		return entryLogger, nil
+	}
+
 	filePath := makeFilePathByLedgerID(ledgerID)
 
 	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
 	if err != nil {
 		return nil, err
 	}
+	version, err := initFileHeader(file, fileVersionChecksummed)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
 
 	entryMetadata := make([]*EntryMetadata, 0)
 	entryLogger := &EntryLogger{
 		ledgerID:      ledgerID,
 		file:          file,
+		version:       version,
 		entryMetadata: entryMetadata,
+		closedChannel: make(chan struct{}),
+		closeOnce:     &sync.Once{},
 	}
 	return entryLogger, nil
 }
@@ -48,7 +111,11 @@ func NewEntryLogger(ledgerID uint64) (*EntryLogger, error) {
 // Write writes the entry to the entry logger. It is not guaranteed that the entry is written to the disk.
 func (el *EntryLogger) Write(entry *pkg.LedgerEntry) error {
 	pkg.Logger.Debugf("Write entry for ledger %d, entry %d", el.ledgerID, entry.EntryID)
-	data := entry.Serialize()
+	if el.version == fileVersionInterleaved {
+		el.pendingEntries = append(el.pendingEntries, entry)
+		return nil
+	}
+	data := encodeEntry(entry, el.version)
 
 	_, err := el.file.Write(data)
 	pkg.Logger.Debugf("Entry written for ledger %d, entry %d", el.ledgerID, entry.EntryID)
@@ -64,25 +131,88 @@ func (el *EntryLogger) Write(entry *pkg.LedgerEntry) error {
 	size := len(data)
 
 	entryMetadata := NewEntryMetadata(entry.EntryID, int(offset), size)
+	entryMetadata.AppendTime = entry.AppendTime
 	el.entryMetadata = append(el.entryMetadata, entryMetadata)
 	return nil
 }
 
-// Read reads the entry from the entry logger.
+// Read reads the entry from the entry logger. A *pkg.EntryCorruptionError is returned if the entry fails the
+// verification.
 func (el *EntryLogger) Read(offset int, size int) (*pkg.LedgerEntry, error) {
-	binLedgerEntry := make([]byte, size)
-	_, err := el.file.ReadAt(binLedgerEntry, int64(offset))
+	binLedgerEntry, err := el.readAt(offset, size)
 	if err != nil {
 		pkg.Logger.Errorf("Read entry failed for ledger %d, offset %d, size %d with err %v", el.ledgerID, offset, size, err)
 		return nil, err
 	}
-	entry := &pkg.LedgerEntry{}
-	entry.Deserialize(binLedgerEntry)
+	entry, ok := el.decode(binLedgerEntry)
+	if !ok {
+		pkg.Logger.Errorf("Read corrupted entry for ledger %d, offset %d, size %d", el.ledgerID, offset, size)
+		return nil, &pkg.EntryCorruptionError{LedgerID: el.ledgerID, Offset: int64(offset)}
+	}
 	return entry, nil
 }
 
-// Delete deletes the entry logger.
+// ReadBatch reads the consecutive entries starting from the offset with a single read. sizes are the sizes of the
+// entries in order. A *pkg.EntryCorruptionError is returned if any entry fails the verification.
+func (el *EntryLogger) ReadBatch(offset int, sizes []int) ([]*pkg.LedgerEntry, error) {
+	totalSize := 0
+	for _, size := range sizes {
+		totalSize += size
+	}
+	data, err := el.readAt(offset, totalSize)
+	if err != nil {
+		pkg.Logger.Errorf("Read entries failed for ledger %d, offset %d, size %d with err %v", el.ledgerID, offset, totalSize, err)
+		return nil, err
+	}
+
+	entries := make([]*pkg.LedgerEntry, 0, len(sizes))
+	position := 0
+	for _, size := range sizes {
+		entry, ok := el.decode(data[position : position+size])
+		if !ok {
+			pkg.Logger.Errorf("Read corrupted entry for ledger %d, offset %d, size %d", el.ledgerID, offset+position, size)
+			return nil, &pkg.EntryCorruptionError{LedgerID: el.ledgerID, Offset: int64(offset + position)}
+		}
+		entries = append(entries, entry)
+		position += size
+	}
+	return entries, nil
+}
+
+// readAt reads size bytes at the offset, which is a position in the interleaved mode.
+func (el *EntryLogger) readAt(offset int, size int) ([]byte, error) {
+	if el.version == fileVersionInterleaved {
+		return readEntryLog(offset, size)
+	}
+	data := make([]byte, size)
+	if _, err := el.file.ReadAt(data, int64(offset)); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// decode decodes an entry read by readAt. False is returned if the entry does not pass the verification, or if it
+// belongs to another ledger in the interleaved mode.
+func (el *EntryLogger) decode(data []byte) (*pkg.LedgerEntry, bool) {
+	if el.version == fileVersionInterleaved {
+		ledgerID, entry, ok := decodeInterleavedEntry(data)
+		return entry, ok && ledgerID == el.ledgerID
+	}
+	return decodeEntry(data, el.version)
+}
+
+// PayloadSize returns the size of the payload of an entry whose size in the entry logger is entrySize.
+func (el *EntryLogger) PayloadSize(entrySize int) int {
+	return entrySize - entryOverhead(el.version)
+}
+
+// Delete deletes the entry logger. In the interleaved mode, the entries are left to the garbage collection.
 func (el *EntryLogger) Delete() error {
+	if el.version == fileVersionInterleaved {
+		el.markClosed()
+		deregisterLedger(el.ledgerID)
+		return nil
+	}
 	if err := os.Remove(el.file.Name()); err != nil {
 		return err
 	}
@@ -90,29 +220,103 @@ func (el *EntryLogger) Delete() error {
 	return nil
 }
 
+// Reclaim frees the space of the trimmed entries, which are never read again. In the per-ledger mode, the entries are
+// punched out of the file where supported, except the ones which end after validOffset, from which the recovery scans
+// the file for valid entries. In the interleaved mode, the entries are excluded from the live sizes of the entry logs,
+// which are reclaimed by the garbage collection and the compaction.
+func (el *EntryLogger) Reclaim(entryMetadata []*EntryMetadata, validOffset int) error {
+	if el.version == fileVersionInterleaved {
+		reclaimEntryLog(el.ledgerID, entryMetadata)
+		return nil
+	}
+	sortedEntryMetadata := slices.SortedFunc(slices.Values(entryMetadata), func(a, b *EntryMetadata) int {
+		return cmp.Compare(a.Offset, b.Offset)
+	})
+	// The adjacent entries are punched together.
+	for begin := 0; begin < len(sortedEntryMetadata); {
+		offset := sortedEntryMetadata[begin].Offset
+		end := offset + sortedEntryMetadata[begin].Size
+		begin++
+		for ; begin < len(sortedEntryMetadata) && sortedEntryMetadata[begin].Offset == end; begin++ {
+			end += sortedEntryMetadata[begin].Size
+		}
+		if end > validOffset {
+			continue
+		}
+		if err := punchHole(el.file, int64(offset), int64(end-offset)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Flush flushes the entry logger to the file system.
 //
 // This method is not thread safe. Write and Flush should not be called concurrently.
 func (el *EntryLogger) Flush() ([]*EntryMetadata, error) {
+	if el.version == fileVersionInterleaved {
+		if len(el.pendingEntries) == 0 {
+			return nil, nil
+		}
+		entryMetadata, err := flushEntryLog(el.ledgerID, el.pendingEntries)
+		if err != nil {
+			return nil, err
+		}
+		el.pendingEntries = nil
+		return entryMetadata, nil
+	}
+	syncStartTime := time.Now()
 	if err := el.file.Sync(); err != nil {
 		return nil, err
 	}
+	metrics.ObserveSince(metrics.EntryLoggerFsyncDuration, syncStartTime)
 	entryMetadata := el.entryMetadata
 	el.entryMetadata = make([]*EntryMetadata, 0)
 	return entryMetadata, nil
 }
 
-// Truncate truncates the entry logger to the given size.
+// Truncate truncates the entry logger to the given size. The interleaved entry logs are truncated when they are
+// opened instead.
 //
 // This function is expected to be called in recovery.
 func (el *EntryLogger) Truncate(size int64) error {
+	if el.version == fileVersionInterleaved {
+		return nil
+	}
 	return el.file.Truncate(size)
 }
 
+// ValidSize returns the size of the file from the beginning to the end of the last valid entry, starting the scan
+// from the offset, which is expected to be the end of a valid entry. The file size is returned for the files of
+// the legacy version, whose entries can not be verified. The offset is returned in the interleaved mode, in which
+// there is nothing to truncate.
+//
+// This function is expected to be called in recovery.
+func (el *EntryLogger) ValidSize(offset int64) (int64, error) {
+	if el.version == fileVersionInterleaved {
+		return offset, nil
+	}
+	return scanValidSize(el.file, el.version, offset)
+}
+
+// RelocationRequests returns the channel of the entries relocated by the compaction, which the owner of the index of
+// the ledger is expected to serve. The channel is nil in the per-ledger mode, in which there is no compaction.
+func (el *EntryLogger) RelocationRequests() <-chan *RelocationRequest {
+	return el.relocationRequests
+}
+
 // Close closes the entry logger.
 func (el *EntryLogger) Close() error {
+	el.markClosed()
 	if el.file == nil {
 		return nil
 	}
 	return el.file.Close()
 }
+
+// markClosed closes closedChannel once.
+func (el *EntryLogger) markClosed() {
+	el.closeOnce.Do(func() {
+		close(el.closedChannel)
+	})
+}
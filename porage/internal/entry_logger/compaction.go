@@ -0,0 +1,409 @@
+package entrylogger
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"porage/internal/pkg"
+	"slices"
+	"sync"
+	"time"
+)
+
+const compactionWorkerName = "entry_log_compaction_worker"
+
+// compactionChunkSize is the size of the frames which the compaction reads from an entry log and relocates at a time.
+const compactionChunkSize = 4 << 20
+
+// errCompactionStopped is returned when the compaction worker is stopped in the middle of a compaction.
+var errCompactionStopped = errors.New("compaction stopped")
+
+var (
+	// readGeneration is advanced whenever an entry log is retired. A reader may hold positions in the entry logs
+	// retired in the generation it began in or later, but not in the ones retired before.
+	readGeneration uint64
+	// activeReaders is the number of the unfinished readers by the generation they began in.
+	activeReaders = make(map[uint64]int)
+	readersLock   = &sync.Mutex{}
+)
+
+// BeginRead keeps the entry logs retired from now on open until the returned function is called. A reader is expected
+// to call it before looking up the positions of the entries in the index, and to call the returned function once it
+// is done reading them. Nothing is kept in the per-ledger mode, in which there is no compaction.
+func BeginRead() (endRead func()) {
+	if !isInterleaved() {
+		return func() {}
+	}
+	readersLock.Lock()
+	defer readersLock.Unlock()
+	generation := readGeneration
+	activeReaders[generation]++
+	return func() {
+		readersLock.Lock()
+		defer readersLock.Unlock()
+		activeReaders[generation]--
+		if activeReaders[generation] == 0 {
+			delete(activeReaders, generation)
+		}
+	}
+}
+
+// advanceReadGeneration starts a new generation of the readers and returns the previous one.
+func advanceReadGeneration() uint64 {
+	readersLock.Lock()
+	defer readersLock.Unlock()
+	readGeneration++
+	return readGeneration - 1
+}
+
+// oldestReadGeneration returns the generation of the oldest unfinished reader, or the current generation if there is
+// none.
+func oldestReadGeneration() uint64 {
+	readersLock.Lock()
+	defer readersLock.Unlock()
+	oldest := readGeneration
+	for generation := range activeReaders {
+		oldest = min(oldest, generation)
+	}
+	return oldest
+}
+
+// compactionInterval returns the interval of the compaction.
+func compactionInterval() time.Duration {
+	if myConfig.CompactionInterval == 0 {
+		return pkg.DefaultCompactionInterval * time.Second
+	}
+	return time.Duration(myConfig.CompactionInterval) * time.Second
+}
+
+// compaction_worker is the worker that rewrites the live entries of the sealed entry logs with few live entries to
+// the active entry log, and removes the sealed ones.
+func compaction_worker(workerDescription *pkg.WorkerDescription) {
+	workerName := compactionWorkerName
+	rateLimiter := pkg.NewRateLimiter(myConfig.CompactionRateLimit)
+
+	for {
+		workerDescription.Heartbeat()
+		select {
+		case <-workerDescription.StopChannel():
+			pkg.Logger.Infof("%s: stopped", workerName)
+			localWorkerControl.UnregisterWorker(workerName)
+			workerDescription.StopResponseChannel() <- struct{}{}
+			return
+		case <-time.After(compactionInterval()):
+			// If in recovering, the ledgers are not opened to serve the relocations yet.
+			if !enableGC.Load() || myConfig.CompactionLiveRatio <= 0 {
+				continue
+			}
+			nCompactedLogs, err := compactEntryLogs(workerDescription, rateLimiter)
+			workerDescription.AddProcessed(nCompactedLogs)
+			if errors.Is(err, errCompactionStopped) {
+				// The stop signal is consumed by the compaction.
+				pkg.Logger.Infof("%s: stopped in the middle of a compaction", workerName)
+				localWorkerControl.UnregisterWorker(workerName)
+				workerDescription.StopResponseChannel() <- struct{}{}
+				return
+			}
+		}
+	}
+}
+
+// compactEntryLogs closes the retired entry logs which are no longer read, and compacts the sealed entry logs whose
+// live ratio is below CompactionLiveRatio, the least live first. The number of the compacted entry logs is returned.
+// errCompactionStopped is returned if the worker is stopped in the middle.
+func compactEntryLogs(workerDescription *pkg.WorkerDescription, rateLimiter *pkg.RateLimiter) (int, error) {
+	closeRetiredEntryLogs()
+	nCompactedLogs := 0
+	for _, log := range selectCompactionCandidates() {
+		err := compactEntryLog(log, workerDescription, rateLimiter)
+		finishCompaction(log)
+		if errors.Is(err, errCompactionStopped) {
+			return nCompactedLogs, err
+		}
+		if err != nil {
+			pkg.Logger.Errorf("Failed to compact entry log %d: %v", log.logID, err)
+			continue
+		}
+		nCompactedLogs++
+	}
+	return nCompactedLogs, nil
+}
+
+// closeRetiredEntryLogs closes the retired entry logs which no unfinished reader may read, i.e. the ones retired
+// before the oldest reader began.
+func closeRetiredEntryLogs() {
+	entryLogsLock.Lock()
+	defer entryLogsLock.Unlock()
+	oldestGeneration := oldestReadGeneration()
+	for logID, log := range entryLogs {
+		if !log.isRetired || log.retiredGeneration >= oldestGeneration {
+			continue
+		}
+		if err := log.file.Close(); err != nil {
+			pkg.Logger.Errorf("Failed to close entry log %d: %v", logID, err)
+		}
+		delete(entryLogs, logID)
+	}
+}
+
+// selectCompactionCandidates returns the sealed entry logs whose live ratio is below CompactionLiveRatio in ascending
+// order of the live ratio, and keeps them from the garbage collection until finishCompaction. The entry logs without
+// live entries are left to the garbage collection.
+func selectCompactionCandidates() []*entryLog {
+	entryLogsLock.Lock()
+	defer entryLogsLock.Unlock()
+	liveRatios := make(map[*entryLog]float64)
+	candidates := make([]*entryLog, 0)
+	for _, log := range entryLogs {
+		if log == activeEntryLog || log.isRetired || log.size <= fileHeaderSize {
+			continue
+		}
+		liveSize := liveSize(log)
+		liveRatio := float64(liveSize) / float64(log.size-fileHeaderSize)
+		if liveSize == 0 || liveRatio >= myConfig.CompactionLiveRatio {
+			continue
+		}
+		log.isCompacting = true
+		liveRatios[log] = liveRatio
+		candidates = append(candidates, log)
+	}
+	slices.SortFunc(candidates, func(a, b *entryLog) int {
+		return cmp.Compare(liveRatios[a], liveRatios[b])
+	})
+	return candidates
+}
+
+// liveSize returns the total size of the entries of the live ledgers in the entry log.
+//
+// Expected to be called with entryLogsLock held.
+func liveSize(log *entryLog) int64 {
+	size := int64(0)
+	for ledgerID, ledgerSize := range log.ledgerSizes {
+		if _, ok := liveLedgers[ledgerID]; ok {
+			size += max(ledgerSize, 0)
+		}
+	}
+	return size
+}
+
+// finishCompaction releases the entry log to the garbage collection.
+func finishCompaction(log *entryLog) {
+	entryLogsLock.Lock()
+	defer entryLogsLock.Unlock()
+	log.isCompacting = false
+}
+
+// compactEntryLog copies the entries of the live ledgers in the sealed entry log to the active entry log chunk by
+// chunk, has the index of each ledger point at the copies, and retires the entry log.
+func compactEntryLog(log *entryLog, workerDescription *pkg.WorkerDescription, rateLimiter *pkg.RateLimiter) error {
+	pkg.Logger.Infof("Compacting entry log %d", log.logID)
+	for offset := int64(fileHeaderSize); offset < log.size; {
+		workerDescription.Heartbeat()
+		if err := waitForRateLimit(rateLimiter, int(min(compactionChunkSize, log.size-offset)), workerDescription.StopChannel()); err != nil {
+			return err
+		}
+		data, err := readFrames(log, offset)
+		if err != nil {
+			return err
+		}
+		if err := relocateFrames(log, offset, data, workerDescription.StopChannel(), rateLimiter); err != nil {
+			return err
+		}
+		offset += int64(len(data))
+	}
+	return retireEntryLog(log)
+}
+
+// waitForRateLimit waits until n bytes of I/O are allowed by the rate limiter. errCompactionStopped is returned if
+// the worker is stopped in the meantime.
+func waitForRateLimit(rateLimiter *pkg.RateLimiter, n int, stopChannel <-chan struct{}) error {
+	delay := rateLimiter.Reserve(n)
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-stopChannel:
+		return errCompactionStopped
+	}
+}
+
+// readFrames reads the whole frames from the offset in the entry log, up to compactionChunkSize bytes unless the
+// first frame is larger.
+func readFrames(log *entryLog, offset int64) ([]byte, error) {
+	data := make([]byte, min(compactionChunkSize, log.size-offset))
+	if _, err := log.file.ReadAt(data, offset); err != nil {
+		return nil, err
+	}
+	end := int64(0)
+	for end+frameHeaderSize <= int64(len(data)) {
+		frameSize := frameHeaderSize + int64(binary.BigEndian.Uint32(data[end:end+4]))
+		if offset+end+frameSize > log.size {
+			return nil, fmt.Errorf("frame at offset %d exceeds the entry log", offset+end)
+		}
+		if end+frameSize <= int64(len(data)) {
+			end += frameSize
+			continue
+		}
+		if end > 0 {
+			break
+		}
+		// The first frame is larger than the chunk.
+		data = make([]byte, frameSize)
+		if _, err := log.file.ReadAt(data, offset); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+	if end == 0 {
+		return nil, fmt.Errorf("torn frame at offset %d", offset)
+	}
+	return data[:end], nil
+}
+
+// pendingRelocation is an entry to be copied by the compaction.
+type pendingRelocation struct {
+	entry      *pkg.LedgerEntry
+	relocation *Relocation
+}
+
+// relocateFrames copies the entries of the live ledgers in the frames read from the offset in the entry log to the
+// active entry log with one batch, and has the owner of the index of each ledger point at the copies.
+func relocateFrames(log *entryLog, offset int64, data []byte, stopChannel <-chan struct{}, rateLimiter *pkg.RateLimiter) error {
+	entryLogsLock.RLock()
+	entryLoggers := maps.Clone(liveLedgers)
+	entryLogsLock.RUnlock()
+
+	relocations := make(map[uint64][]*pendingRelocation)
+	totalSize := 0
+	for position := int64(0); position < int64(len(data)); {
+		frameSize := frameHeaderSize + int64(binary.BigEndian.Uint32(data[position:position+4]))
+		ledgerID, entry, ok := decodeInterleavedEntry(data[position : position+frameSize])
+		if !ok {
+			return fmt.Errorf("corrupted entry at offset %d", offset+position)
+		}
+		if _, ok := entryLoggers[ledgerID]; ok {
+			relocations[ledgerID] = append(relocations[ledgerID], &pendingRelocation{
+				entry: entry,
+				relocation: &Relocation{
+					EntryID:   entry.EntryID,
+					OldOffset: makePosition(log.logID, offset+position),
+					Size:      int(frameSize),
+				},
+			})
+			totalSize += int(frameSize)
+		}
+		position += frameSize
+	}
+	if len(relocations) == 0 {
+		return nil
+	}
+	if err := waitForRateLimit(rateLimiter, totalSize, stopChannel); err != nil {
+		return err
+	}
+
+	// The flush worker sorts the entries of a ledger by entryID, so they are sorted in advance to match the offsets
+	// of the copies to the relocations.
+	requests := make(map[uint64]*flushRequest)
+	for ledgerID, ledgerRelocations := range relocations {
+		slices.SortStableFunc(ledgerRelocations, func(a, b *pendingRelocation) int {
+			return cmp.Compare(a.entry.EntryID, b.entry.EntryID)
+		})
+		entries := make([]*pkg.LedgerEntry, 0, len(ledgerRelocations))
+		for _, r := range ledgerRelocations {
+			entries = append(entries, r.entry)
+		}
+		requests[ledgerID] = submitFlush(ledgerID, entries)
+	}
+	var flushErr error
+	copies := make(map[uint64][]*Relocation)
+	for ledgerID, request := range requests {
+		result := <-request.resultChannel
+		if result.err != nil {
+			flushErr = result.err
+			continue
+		}
+		for i, entryMetadata := range result.entryMetadata {
+			relocations[ledgerID][i].relocation.NewOffset = entryMetadata.Offset
+			copies[ledgerID] = append(copies[ledgerID], relocations[ledgerID][i].relocation)
+		}
+	}
+	if flushErr != nil {
+		for ledgerID, ledgerCopies := range copies {
+			markDead(ledgerID, ledgerCopies)
+		}
+		return flushErr
+	}
+
+	for ledgerID, ledgerCopies := range copies {
+		if err := requestRelocation(entryLoggers[ledgerID], ledgerCopies, stopChannel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requestRelocation asks the owner of the index of the ledger to point at the copies, and waits until the index is
+// synced. The copies which the index does not point at are marked dead.
+func requestRelocation(entryLogger *EntryLogger, relocations []*Relocation, stopChannel <-chan struct{}) error {
+	request := &RelocationRequest{
+		Relocations: relocations,
+		doneChannel: make(chan error, 1),
+	}
+	select {
+	case entryLogger.relocationRequests <- request:
+	case <-entryLogger.closedChannel:
+		// The entries of a closed ledger may still be in the index after it is reopened, so the entry log is kept
+		// until the ledger is deleted.
+		markDead(entryLogger.ledgerID, relocations)
+		return fmt.Errorf("ledger %d is closed", entryLogger.ledgerID)
+	case <-stopChannel:
+		markDead(entryLogger.ledgerID, relocations)
+		return errCompactionStopped
+	}
+
+	select {
+	case err := <-request.doneChannel:
+		if err != nil {
+			markDead(entryLogger.ledgerID, relocations)
+			return err
+		}
+	case <-stopChannel:
+		return errCompactionStopped
+	}
+	skipped := make([]*Relocation, 0)
+	for _, relocation := range relocations {
+		if relocation.Skipped {
+			skipped = append(skipped, relocation)
+		}
+	}
+	markDead(entryLogger.ledgerID, skipped)
+	return nil
+}
+
+// markDead excludes the copies of the ledger from the live sizes of the entry logs they were copied to.
+func markDead(ledgerID uint64, relocations []*Relocation) {
+	entryMetadata := make([]*EntryMetadata, 0, len(relocations))
+	for _, relocation := range relocations {
+		entryMetadata = append(entryMetadata, NewEntryMetadata(relocation.EntryID, relocation.NewOffset, relocation.Size))
+	}
+	reclaimEntryLog(ledgerID, entryMetadata)
+}
+
+// retireEntryLog removes the file of the compacted entry log. The entry log is kept open until the readers which
+// began before it was retired are done, since they may have looked up the old positions in the index.
+func retireEntryLog(log *entryLog) error {
+	entryLogsLock.Lock()
+	defer entryLogsLock.Unlock()
+	if err := os.Remove(log.file.Name()); err != nil {
+		return err
+	}
+	log.isRetired = true
+	log.retiredGeneration = advanceReadGeneration()
+	pkg.Logger.Infof("Compacted entry log %d", log.logID)
+	return nil
+}
@@ -0,0 +1,77 @@
+package entrylogger
+
+import (
+	"os"
+	"porage/internal/pkg"
+	"sync/atomic"
+	"time"
+)
+
+const gcWorkerName = "entry_log_gc_worker"
+
+var (
+	enableGC = atomic.Bool{}
+)
+
+// gcInterval returns the interval of the garbage collection.
+func gcInterval() time.Duration {
+	if myConfig.GCInterval == 0 {
+		return pkg.DefaultEntryLogGCInterval * time.Second
+	}
+	return time.Duration(myConfig.GCInterval) * time.Second
+}
+
+// gc_worker is the worker that removes the sealed entry logs whose entries all belong to deleted ledgers, and closes
+// the entry logs retired by the compaction once they are no longer read.
+func gc_worker(workerDescription *pkg.WorkerDescription) {
+	workerName := gcWorkerName
+
+	for {
+		workerDescription.Heartbeat()
+		select {
+		case <-workerDescription.StopChannel():
+			pkg.Logger.Infof("%s: stopped", workerName)
+			localWorkerControl.UnregisterWorker(workerName)
+			workerDescription.StopResponseChannel() <- struct{}{}
+			return
+		case <-time.After(gcInterval()):
+			// If in recovering, the live ledgers are not registered yet.
+			if !enableGC.Load() {
+				continue
+			}
+			closeRetiredEntryLogs()
+			workerDescription.AddProcessed(collectGarbage())
+		}
+	}
+}
+
+// collectGarbage removes the sealed entry logs without entries of live ledgers and returns the number of the removed
+// entry logs.
+func collectGarbage() int {
+	entryLogsLock.Lock()
+	defer entryLogsLock.Unlock()
+	nRemovedLogs := 0
+	for logID, log := range entryLogs {
+		if log == activeEntryLog || log.isRetired || log.isCompacting || hasLiveLedger(log) {
+			continue
+		}
+		if err := log.file.Close(); err != nil {
+			pkg.Logger.Errorf("Failed to close entry log %d: %v", logID, err)
+			continue
+		}
+		if err := os.Remove(log.file.Name()); err != nil {
+			pkg.Logger.Errorf("Failed to remove entry log %d: %v", logID, err)
+		}
+		delete(entryLogs, logID)
+		nRemovedLogs++
+		pkg.Logger.Infof("Removed entry log %d, whose ledgers are all deleted", logID)
+	}
+	return nRemovedLogs
+}
+
+// hasLiveLedger reports whether the entry log has entries of live ledgers.
+//
+// Expected to be called with entryLogsLock held.
+func hasLiveLedger(log *entryLog) bool {
+	return liveSize(log) > 0
+}
@@ -0,0 +1,365 @@
+package entrylogger
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"porage/internal/metrics"
+	"porage/internal/pkg"
+	"slices"
+	"sync"
+	"time"
+)
+
+// flushRequestBufferSize is the number of flush requests which can wait for the flush worker. A ledger has at most one
+// flush request at a time.
+const flushRequestBufferSize = 1024
+
+// entryLog is an interleaved entry log shared by the ledgers.
+type entryLog struct {
+	logID uint64
+	file  *os.File
+	// size is the end of the last entry in the log.
+	size int64
+	// ledgerSizes is the total size of the entries of each ledger in the log, less the copies which the compaction
+	// found dead. It is recounted from the frames when the log is opened.
+	ledgerSizes map[uint64]int64
+	// isRetired is set when the log is compacted. The file of a retired log is removed, but it is kept open until
+	// the readers which began in retiredGeneration or before are done, since they may have looked up the old
+	// positions in the index.
+	isRetired         bool
+	retiredGeneration uint64
+	// isCompacting keeps the log from the garbage collection while it is compacted.
+	isCompacting bool
+}
+
+// flushRequest is the message from an EntryLogger to the flush worker, which writes the entries to the active entry
+// log and syncs it.
+type flushRequest struct {
+	ledgerID      uint64
+	entries       []*pkg.LedgerEntry
+	resultChannel chan *flushResult
+}
+
+type flushResult struct {
+	entryMetadata []*EntryMetadata
+	err           error
+}
+
+var (
+	// entryLogs are the interleaved entry logs by logID. Only the flush worker appends to the active one.
+	entryLogs      map[uint64]*entryLog
+	activeEntryLog *entryLog
+	// liveLedgers are the entry loggers of the ledgers whose entries are kept by the garbage collection.
+	liveLedgers   map[uint64]*EntryLogger
+	entryLogsLock = &sync.RWMutex{}
+
+	flushRequests chan *flushRequest
+)
+
+// maxEntryLogSize returns the size at which the active entry log is rolled over.
+func maxEntryLogSize() int64 {
+	if myConfig.MaxEntryLogSize <= 0 {
+		return pkg.DefaultMaxEntryLogSize
+	}
+	return min(myConfig.MaxEntryLogSize, 1<<logOffsetBits)
+}
+
+// openEntryLogs opens the interleaved entry logs in the storage path. The last one is the active entry log, whose
+// torn tail is truncated. A new active entry log is created if there is none.
+func openEntryLogs() error {
+	entryLogs = make(map[uint64]*entryLog)
+	activeEntryLog = nil
+	liveLedgers = make(map[uint64]*EntryLogger)
+	flushRequests = make(chan *flushRequest, flushRequestBufferSize)
+
+	dirEntries, err := os.ReadDir(myConfig.StoragePath)
+	if err != nil {
+		return err
+	}
+	logIDs := make([]uint64, 0)
+	for _, dirEntry := range dirEntries {
+		if logID, ok := parseEntryLogFileName(dirEntry.Name()); ok {
+			logIDs = append(logIDs, logID)
+		}
+	}
+	slices.Sort(logIDs)
+
+	for i, logID := range logIDs {
+		isLast := i == len(logIDs)-1
+		log, err := openEntryLog(logID, isLast)
+		if err != nil {
+			closeEntryLogs()
+			return fmt.Errorf("failed to open entry log %d: %w", logID, err)
+		}
+		entryLogs[logID] = log
+		if isLast {
+			activeEntryLog = log
+		}
+	}
+	if activeEntryLog == nil || activeEntryLog.size >= maxEntryLogSize() {
+		if err := rollEntryLog(); err != nil {
+			closeEntryLogs()
+			return err
+		}
+	}
+	return nil
+}
+
+// openEntryLog opens an interleaved entry log and collects the sizes of its ledgers. The frames of the last entry log
+// are verified and its torn tail is truncated. The older entry logs are sealed, so only their frame headers are read.
+func openEntryLog(logID uint64, isLast bool) (*entryLog, error) {
+	file, err := os.OpenFile(makeEntryLogFilePath(logID), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := initFileHeader(file, fileVersionInterleaved); err != nil {
+		file.Close()
+		return nil, err
+	}
+	log := &entryLog{
+		logID:       logID,
+		file:        file,
+		ledgerSizes: make(map[uint64]int64),
+	}
+	if err := log.scan(isLast); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if isLast {
+		if err := file.Truncate(log.size); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	return log, nil
+}
+
+// scan collects the sizes of the ledgers in the entry log and sets its size to the end of the last frame. The frames
+// are verified if verify is true.
+func (log *entryLog) scan(verify bool) error {
+	fileInfo, err := log.file.Stat()
+	if err != nil {
+		return err
+	}
+	fileSize := fileInfo.Size()
+	offset := int64(fileHeaderSize)
+	frameHeader := make([]byte, frameHeaderSize+8)
+	for offset+int64(len(frameHeader)) <= fileSize {
+		if _, err := log.file.ReadAt(frameHeader, offset); err != nil {
+			return err
+		}
+		frameSize := frameHeaderSize + int64(binary.BigEndian.Uint32(frameHeader[0:4]))
+		if offset+frameSize > fileSize {
+			break
+		}
+		ledgerID := binary.BigEndian.Uint64(frameHeader[frameHeaderSize:])
+		if verify {
+			frame := make([]byte, frameSize)
+			if _, err := log.file.ReadAt(frame, offset); err != nil {
+				return err
+			}
+			var ok bool
+			if ledgerID, _, ok = decodeInterleavedEntry(frame); !ok {
+				break
+			}
+		}
+		log.ledgerSizes[ledgerID] += frameSize
+		offset += frameSize
+	}
+	log.size = offset
+	return nil
+}
+
+// rollEntryLog creates a new active entry log. The previous active entry log is sealed and kept for the reads.
+func rollEntryLog() error {
+	logID := uint64(0)
+	if activeEntryLog != nil {
+		logID = activeEntryLog.logID + 1
+	}
+	file, err := os.OpenFile(makeEntryLogFilePath(logID), os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := initFileHeader(file, fileVersionInterleaved); err != nil {
+		file.Close()
+		return err
+	}
+	log := &entryLog{
+		logID:       logID,
+		file:        file,
+		size:        fileHeaderSize,
+		ledgerSizes: make(map[uint64]int64),
+	}
+
+	entryLogsLock.Lock()
+	defer entryLogsLock.Unlock()
+	entryLogs[logID] = log
+	activeEntryLog = log
+	pkg.Logger.Infof("Rolled over to entry log %d", logID)
+	return nil
+}
+
+// closeEntryLogs closes all the interleaved entry logs.
+func closeEntryLogs() {
+	entryLogsLock.Lock()
+	defer entryLogsLock.Unlock()
+	for _, log := range entryLogs {
+		if err := log.file.Close(); err != nil {
+			pkg.Logger.Errorf("Failed to close entry log %d: %v", log.logID, err)
+		}
+	}
+	entryLogs = nil
+	activeEntryLog = nil
+}
+
+// registerLedger keeps the entries of the ledger of the entry logger from the garbage collection.
+func registerLedger(entryLogger *EntryLogger) {
+	entryLogsLock.Lock()
+	defer entryLogsLock.Unlock()
+	liveLedgers[entryLogger.ledgerID] = entryLogger
+}
+
+// deregisterLedger leaves the entries of the deleted ledger to the garbage collection.
+func deregisterLedger(ledgerID uint64) {
+	entryLogsLock.Lock()
+	defer entryLogsLock.Unlock()
+	delete(liveLedgers, ledgerID)
+}
+
+// reclaimEntryLog excludes the entries of the ledger from the live sizes of the entry logs they are in.
+func reclaimEntryLog(ledgerID uint64, entryMetadata []*EntryMetadata) {
+	entryLogsLock.Lock()
+	defer entryLogsLock.Unlock()
+	for _, metadata := range entryMetadata {
+		logID, _ := parsePosition(metadata.Offset)
+		if log, ok := entryLogs[logID]; ok {
+			log.ledgerSizes[ledgerID] -= int64(metadata.Size)
+		}
+	}
+}
+
+// readEntryLog reads size bytes at the position in the interleaved entry logs.
+func readEntryLog(position int, size int) ([]byte, error) {
+	logID, offset := parsePosition(position)
+	entryLogsLock.RLock()
+	defer entryLogsLock.RUnlock()
+	log, ok := entryLogs[logID]
+	if !ok {
+		return nil, fmt.Errorf("entry log %d not found", logID)
+	}
+	data := make([]byte, size)
+	if _, err := log.file.ReadAt(data, offset); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// flushEntryLog writes the entries of the ledger to the active entry log and syncs it. The flush worker writes the
+// entries flushed by all the ledgers at the same time in one batch and syncs the entry log once for them.
+func flushEntryLog(ledgerID uint64, entries []*pkg.LedgerEntry) ([]*EntryMetadata, error) {
+	result := <-submitFlush(ledgerID, entries).resultChannel
+	return result.entryMetadata, result.err
+}
+
+// submitFlush queues the entries of the ledger for the flush worker without waiting for the result.
+func submitFlush(ledgerID uint64, entries []*pkg.LedgerEntry) *flushRequest {
+	request := &flushRequest{
+		ledgerID:      ledgerID,
+		entries:       entries,
+		resultChannel: make(chan *flushResult, 1),
+	}
+	flushRequests <- request
+	return request
+}
+
+// flushWorkerName is the name of the worker which flushes the interleaved entry logs.
+const flushWorkerName = "entry_log_flush_worker"
+
+// flush_worker writes the entries of the flush requests to the active entry log in batches.
+func flush_worker(workerDescription *pkg.WorkerDescription) {
+	workerName := flushWorkerName
+
+	for {
+		workerDescription.Heartbeat()
+		select {
+		case request := <-flushRequests:
+			requests := []*flushRequest{request}
+			for len(flushRequests) > 0 {
+				requests = append(requests, <-flushRequests)
+			}
+			flushBatch(requests)
+			workerDescription.AddProcessed(len(requests))
+		case <-workerDescription.StopChannel():
+			pkg.Logger.Infof("%s: stopped", workerName)
+			localWorkerControl.UnregisterWorker(workerName)
+			workerDescription.StopResponseChannel() <- struct{}{}
+			return
+		}
+	}
+}
+
+// flushBatch writes the entries of the requests sorted by (ledgerID, entryID) with a single write and syncs the
+// active entry log, so that the entries of a ledger in a batch are adjacent in the entry log.
+func flushBatch(requests []*flushRequest) {
+	slices.SortFunc(requests, func(a, b *flushRequest) int {
+		return cmp.Compare(a.ledgerID, b.ledgerID)
+	})
+	data := make([]byte, 0)
+	results := make([]*flushResult, len(requests))
+	for i, request := range requests {
+		slices.SortStableFunc(request.entries, func(a, b *pkg.LedgerEntry) int {
+			return cmp.Compare(a.EntryID, b.EntryID)
+		})
+		results[i] = &flushResult{entryMetadata: make([]*EntryMetadata, 0, len(request.entries))}
+		for _, entry := range request.entries {
+			frame := encodeInterleavedEntry(request.ledgerID, entry)
+			// The offsets are relative to the batch until the batch is written.
+			entryMetadata := NewEntryMetadata(entry.EntryID, len(data), len(frame))
+			entryMetadata.AppendTime = entry.AppendTime
+			results[i].entryMetadata = append(results[i].entryMetadata, entryMetadata)
+			data = append(data, frame...)
+		}
+	}
+
+	log, offset, err := writeBatch(data)
+	if err == nil {
+		entryLogsLock.Lock()
+		log.size = offset + int64(len(data))
+		for i, request := range requests {
+			for _, entryMetadata := range results[i].entryMetadata {
+				entryMetadata.Offset = makePosition(log.logID, offset+int64(entryMetadata.Offset))
+				log.ledgerSizes[request.ledgerID] += int64(entryMetadata.Size)
+			}
+		}
+		entryLogsLock.Unlock()
+	}
+	for i, request := range requests {
+		if err != nil {
+			request.resultChannel <- &flushResult{err: err}
+			continue
+		}
+		request.resultChannel <- results[i]
+	}
+}
+
+// writeBatch appends the data to the active entry log, which is rolled over first if the data does not fit, and
+// syncs it. The entry log and the offset of the data in it are returned.
+func writeBatch(data []byte) (*entryLog, int64, error) {
+	if activeEntryLog.size > fileHeaderSize && activeEntryLog.size+int64(len(data)) > maxEntryLogSize() {
+		if err := rollEntryLog(); err != nil {
+			return nil, 0, err
+		}
+	}
+	log := activeEntryLog
+	if _, err := log.file.WriteAt(data, log.size); err != nil {
+		return nil, 0, err
+	}
+	syncStartTime := time.Now()
+	if err := log.file.Sync(); err != nil {
+		return nil, 0, err
+	}
+	metrics.ObserveSince(metrics.EntryLoggerFsyncDuration, syncStartTime)
+	return log, log.size, nil
+}
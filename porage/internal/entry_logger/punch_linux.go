@@ -0,0 +1,28 @@
+//go:build linux
+
+package entrylogger
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// isPunchHoleSupported reports whether punchHole frees the space of the trimmed entries in the per-ledger mode.
+const isPunchHoleSupported = true
+
+// The mode flags of fallocate(2).
+const (
+	fallocFlKeepSize  = 0x01
+	fallocFlPunchHole = 0x02
+)
+
+// punchHole deallocates the range of the file, which reads as zeros afterwards. The size of the file is kept. Nothing
+// is done if the file system does not support it.
+func punchHole(file *os.File, offset int64, size int64) error {
+	err := syscall.Fallocate(int(file.Fd()), fallocFlKeepSize|fallocFlPunchHole, offset, size)
+	if errors.Is(err, syscall.EOPNOTSUPP) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package entrylogger
+
+import "os"
+
+// isPunchHoleSupported reports whether punchHole frees the space of the trimmed entries in the per-ledger mode.
+const isPunchHoleSupported = false
+
+// punchHole does nothing where punching holes is not supported, so the space is only reclaimed when the file is
+// removed.
+func punchHole(file *os.File, offset int64, size int64) error {
+	return nil
+}
@@ -7,7 +7,9 @@ import (
 
 var myConfig *pkg.EntryLoggerConfig
 
-// Startup sets the configuration items for entry logger.
+// Startup sets the configuration items for entry logger. In the interleaved mode, the entry logs are opened and their
+// workers are started. In the per-ledger mode, there is no compaction, and the trimmed entries are only reclaimed by
+// punching holes in the ledger files where supported.
 func Startup(config *pkg.EntryLoggerConfig) {
 	myConfig = config
 
@@ -15,4 +17,42 @@ func Startup(config *pkg.EntryLoggerConfig) {
 	if err != nil {
 		panic(err)
 	}
+
+	if isInterleaved() {
+		enableGC.Store(false)
+		if err := openEntryLogs(); err != nil {
+			panic(err)
+		}
+		startWorkers()
+	} else if !isPunchHoleSupported {
+		// The compaction only rewrites the interleaved entry logs, so nothing else frees the space of a ledger file.
+		pkg.Logger.Warningf("The space of the trimmed entries is not reclaimed in the per-ledger mode on this platform until their ledgers are deleted, use the interleaved mode instead")
+	}
+}
+
+// Stop stops the workers and closes the entry logs in the interleaved mode. Expected to be called after all the
+// ledgers are closed.
+func Stop() {
+	if !isInterleaved() {
+		return
+	}
+	closeWorkers()
+	closeEntryLogs()
+	pkg.Logger.Infof("Entry logger stopped")
+}
+
+// EnableGC enables the garbage collection of the interleaved entry logs. Expected to be called after the recovery, when
+// all the live ledgers are opened.
+func EnableGC() {
+	enableGC.Store(true)
+}
+
+// GetWorkerDescriptions returns the descriptions of the workers of the entry logger.
+func GetWorkerDescriptions() map[string]*pkg.WorkerDescription {
+	return localWorkerControl.GetWorkerDescriptions()
+}
+
+// isInterleaved reports whether the entry logger is in the interleaved mode.
+func isInterleaved() bool {
+	return myConfig.Mode == pkg.EntryLoggerModeInterleaved
 }
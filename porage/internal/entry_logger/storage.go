@@ -1,8 +1,48 @@
 package entrylogger
 
 import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
 	"path"
+	"porage/internal/pkg"
 	"strconv"
+	"strings"
+)
+
+// On-disk format of an entry logger file:
+//
+//	File:  [Header][Frame][Frame]...
+//	Header: [Magic(4)][Version(2)][Reserved(2)]
+//	Frame:  [Length(4)][Checksum(4)][EntryID][Payload]
+//
+// Length is the size of [EntryID][Payload] and Checksum is the CRC32C of it. Files written before the header
+// was introduced have no header and their entries are bare [EntryID][Payload]. They are of version 0.
+//
+// The interleaved entry logs shared by the ledgers are of version 2, whose frames carry the ledgerID:
+//
+//	Frame:  [Length(4)][Checksum(4)][LedgerID(8)][EntryID][Payload]
+//
+// Length is the size of [LedgerID][EntryID][Payload] and Checksum is the CRC32C of it.
+const (
+	fileMagic       = uint32(0x50454C47) // "PELG"
+	fileHeaderSize  = 8
+	frameHeaderSize = 8
+
+	// fileVersionLegacy is the version of the files without header and frames.
+	fileVersionLegacy = uint16(0)
+	// fileVersionChecksummed is the version of the files with header and checksummed frames.
+	fileVersionChecksummed = uint16(1)
+	// fileVersionInterleaved is the version of the interleaved entry logs, whose frames carry the ledgerID.
+	fileVersionInterleaved = uint16(2)
+
+	// entryLogFilePrefix and entryLogFileSuffix enclose the logID in the file name of an interleaved entry log.
+	entryLogFilePrefix = "entry_log_"
+	entryLogFileSuffix = ".log"
+	// logOffsetBits is the number of the low bits of a position which are the offset in the interleaved entry log.
+	// The high bits are the logID.
+	logOffsetBits = 40
 )
 
 // makeFilePathByLedgerID makes the file path by the ledger ID.
@@ -10,3 +50,174 @@ func makeFilePathByLedgerID(ledgerID uint64) string {
 	filePath := path.Join(myConfig.StoragePath, "ledger_"+strconv.FormatUint(ledgerID, 10)+".logger")
 	return filePath
 }
+
+// makeEntryLogFilePath makes the file path of the interleaved entry log.
+func makeEntryLogFilePath(logID uint64) string {
+	return path.Join(myConfig.StoragePath, entryLogFilePrefix+strconv.FormatUint(logID, 10)+entryLogFileSuffix)
+}
+
+// parseEntryLogFileName returns the logID of the interleaved entry log file name. False is returned if the file is
+// not an interleaved entry log.
+func parseEntryLogFileName(fileName string) (uint64, bool) {
+	if !strings.HasPrefix(fileName, entryLogFilePrefix) || !strings.HasSuffix(fileName, entryLogFileSuffix) {
+		return 0, false
+	}
+	logID, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(fileName, entryLogFilePrefix), entryLogFileSuffix), 10, 64)
+	return logID, err == nil
+}
+
+// makePosition returns the position of the offset in the interleaved entry log, which is stored in the index as the
+// offset of the entry.
+func makePosition(logID uint64, offset int64) int {
+	return int(logID<<logOffsetBits | uint64(offset))
+}
+
+// parsePosition returns the logID and the offset in the entry log of a position made by makePosition.
+func parsePosition(position int) (uint64, int64) {
+	return uint64(position) >> logOffsetBits, int64(uint64(position) & (1<<logOffsetBits - 1))
+}
+
+// initFileHeader writes the file header of newFileVersion if the file is empty, or reads the version from the
+// header otherwise. The file is expected to be empty or of newFileVersion, unless it is a legacy file without header.
+func initFileHeader(file *os.File, newFileVersion uint16) (uint16, error) {
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	if fileInfo.Size() == 0 {
+		header := make([]byte, fileHeaderSize)
+		binary.BigEndian.PutUint32(header[0:4], fileMagic)
+		binary.BigEndian.PutUint16(header[4:6], newFileVersion)
+		if _, err := file.Write(header); err != nil {
+			return 0, err
+		}
+		return newFileVersion, file.Sync()
+	}
+
+	header := make([]byte, fileHeaderSize)
+	if _, err := file.ReadAt(header, 0); err != nil && err != io.EOF {
+		return 0, err
+	}
+	if binary.BigEndian.Uint32(header[0:4]) != fileMagic {
+		return fileVersionLegacy, nil
+	}
+	version := binary.BigEndian.Uint16(header[4:6])
+	if version != newFileVersion {
+		return 0, fmt.Errorf("unsupported entry logger file version %d of %s", version, file.Name())
+	}
+	return version, nil
+}
+
+// encodeEntry encodes the entry in the format of the given file version.
+func encodeEntry(entry *pkg.LedgerEntry, version uint16) []byte {
+	body := entry.Serialize()
+	if version == fileVersionLegacy {
+		return body
+	}
+	data := make([]byte, frameHeaderSize+len(body))
+	binary.BigEndian.PutUint32(data[0:4], uint32(len(body)))
+	binary.BigEndian.PutUint32(data[4:8], pkg.Checksum(body))
+	copy(data[frameHeaderSize:], body)
+	return data
+}
+
+// encodeInterleavedEntry encodes the entry of the ledger in the format of the interleaved entry logs.
+func encodeInterleavedEntry(ledgerID uint64, entry *pkg.LedgerEntry) []byte {
+	body := binary.BigEndian.AppendUint64(make([]byte, 0, 8+8+len(entry.Payload)), ledgerID)
+	body = append(body, entry.Serialize()...)
+	data := make([]byte, frameHeaderSize+len(body))
+	binary.BigEndian.PutUint32(data[0:4], uint32(len(body)))
+	binary.BigEndian.PutUint32(data[4:8], pkg.Checksum(body))
+	copy(data[frameHeaderSize:], body)
+	return data
+}
+
+// entryOverhead returns the size of an encoded entry besides its payload in the format of the given file version.
+func entryOverhead(version uint16) int {
+	// EntryID
+	overhead := 8
+	if version != fileVersionLegacy {
+		overhead += frameHeaderSize
+	}
+	if version == fileVersionInterleaved {
+		// LedgerID
+		overhead += 8
+	}
+	return overhead
+}
+
+// decodeEntry decodes the entry in the format of the given file version. False is returned if the data does
+// not pass the verification.
+func decodeEntry(data []byte, version uint16) (*pkg.LedgerEntry, bool) {
+	body := data
+	if version != fileVersionLegacy {
+		var ok bool
+		if body, ok = verifyFrame(data); !ok {
+			return nil, false
+		}
+	}
+	if len(body) < 8 {
+		return nil, false
+	}
+	entry := &pkg.LedgerEntry{}
+	entry.Deserialize(body)
+	return entry, true
+}
+
+// decodeInterleavedEntry decodes the entry in the format of the interleaved entry logs and returns it with its
+// ledgerID. False is returned if the data does not pass the verification.
+func decodeInterleavedEntry(data []byte) (uint64, *pkg.LedgerEntry, bool) {
+	body, ok := verifyFrame(data)
+	if !ok || len(body) < 8+8 {
+		return 0, nil, false
+	}
+	entry := &pkg.LedgerEntry{}
+	entry.Deserialize(body[8:])
+	return binary.BigEndian.Uint64(body[:8]), entry, true
+}
+
+// verifyFrame returns the body of the frame if its length and checksum are correct.
+func verifyFrame(data []byte) ([]byte, bool) {
+	if len(data) < frameHeaderSize {
+		return nil, false
+	}
+	body := data[frameHeaderSize:]
+	if binary.BigEndian.Uint32(data[0:4]) != uint32(len(body)) || binary.BigEndian.Uint32(data[4:8]) != pkg.Checksum(body) {
+		return nil, false
+	}
+	return body, true
+}
+
+// scanValidSize scans the frames from the offset and returns the end of the last frame which passes the
+// verification. The file size is returned if the file is of the legacy version.
+func scanValidSize(file *os.File, version uint16, offset int64) (int64, error) {
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	fileSize := fileInfo.Size()
+	if version == fileVersionLegacy {
+		return fileSize, nil
+	}
+
+	frameHeader := make([]byte, frameHeaderSize)
+	for offset+frameHeaderSize <= fileSize {
+		if _, err := file.ReadAt(frameHeader, offset); err != nil {
+			return 0, err
+		}
+		frameSize := frameHeaderSize + int64(binary.BigEndian.Uint32(frameHeader[0:4]))
+		if offset+frameSize > fileSize {
+			break
+		}
+		frame := make([]byte, frameSize)
+		if _, err := file.ReadAt(frame, offset); err != nil {
+			return 0, err
+		}
+		if _, ok := decodeEntry(frame, version); !ok {
+			break
+		}
+		offset += frameSize
+	}
+	return offset, nil
+}
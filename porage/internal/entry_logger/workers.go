@@ -0,0 +1,40 @@
+package entrylogger
+
+import (
+	"porage/internal/pkg"
+)
+
+var (
+	localWorkerControl = pkg.NewLocalWorkerControl()
+)
+
+// startWorkers registers and starts the workers of the interleaved entry logs.
+func startWorkers() {
+	flushWorkerDescription := pkg.NewWorkerDescription("Write the flushed entries of the ledgers to the interleaved entry log")
+	flushWorkerDescription.SetQueueLength(func() int {
+		return len(flushRequests)
+	})
+	localWorkerControl.RegisterWorker(flushWorkerName, flushWorkerDescription)
+	go flush_worker(flushWorkerDescription)
+
+	gcWorkerDescription := pkg.NewWorkerDescription("Remove the interleaved entry logs of the deleted ledgers")
+	gcWorkerDescription.SetPeriod(gcInterval())
+	localWorkerControl.RegisterWorker(gcWorkerName, gcWorkerDescription)
+	go gc_worker(gcWorkerDescription)
+
+	compactionWorkerDescription := pkg.NewWorkerDescription("Rewrite the live entries of the interleaved entry logs with few live entries")
+	compactionWorkerDescription.SetPeriod(compactionInterval())
+	localWorkerControl.RegisterWorker(compactionWorkerName, compactionWorkerDescription)
+	go compaction_worker(compactionWorkerDescription)
+}
+
+// closeWorkers stops the workers. The flush worker is stopped last, since the compaction waits for it.
+func closeWorkers() {
+	descriptions := localWorkerControl.GetWorkerDescriptions()
+	for workerName, description := range descriptions {
+		if workerName != flushWorkerName {
+			description.Stop()
+		}
+	}
+	descriptions[flushWorkerName].Stop()
+}
@@ -2,134 +2,63 @@ package index
 
 import (
 	"encoding/binary"
-	"errors"
-	"os"
 	"porage/internal/pkg"
-
-	"github.com/dgraph-io/badger/v3"
 )
 
-type Index struct {
-	ledgerID uint64
-
-	db *badger.DB
-}
-
-func NewIndex(ledgerID uint64) (*Index, error) {
-	// Open the Badger database
-	dbStoragePath := makeStoragePathByLedgerID(ledgerID)
-	option := badger.DefaultOptions(dbStoragePath)
-	option.MemTableSize = myConfig.MemtableSize
-	option.Logger = pkg.Logger
-	db, err := badger.Open(option)
-
-	if err != nil {
-		return nil, err
-	}
-	return &Index{
-		ledgerID: ledgerID,
-		db:       db,
-	}, nil
-}
-
-// Put writes the index value to the badger database.
-func (i *Index) Put(entryID int, value *IndexValue) error {
-	// Write entryID as key and offset as value to myBadger.
-	err := i.db.Update(func(txn *badger.Txn) error {
-		key, err := pkg.Int64ToBytes(int64(entryID))
-		if err != nil {
-			return err
-		}
-		value := value.serialize()
-		err = txn.Set(key, value)
-		return err
-	})
-	return err
+// Index maps the entryIDs of a ledger to the positions of the entries in the entry logger.
+type Index interface {
+	// Put writes the index value of the entryID.
+	Put(entryID int, value *IndexValue) error
+	// Get returns the index value of the entryID. If the entryID does not exist, nil is returned.
+	Get(entryID int) (*IndexValue, error)
+	// Range calls fn with the entryIDs and the index values in [fromEntryID, toEntryID] in ascending order of
+	// entryID, until fn returns false.
+	Range(fromEntryID int, toEntryID int, fn func(entryID int, value *IndexValue) bool) error
+	// DeleteRange removes the index values of the entryIDs in [fromEntryID, toEntryID].
+	DeleteRange(fromEntryID int, toEntryID int) error
+	// LastItem returns the last entryID and index value in the ledger. If there is no item, indexValue will be nil.
+	LastItem() (entryID int, indexValue *IndexValue, err error)
+	// Sync makes the index values put so far durable. Expected to be called after the entry logger is flushed.
+	Sync() error
+	// Delete deletes the index of the ledger. Expected to be called after Close.
+	Delete() error
+	// Close closes the index.
+	Close() error
 }
 
-// Get the index of the entryID in the ledgerID. If the entryID does not exist, return nil.
-func (i *Index) Get(entryID int) (*IndexValue, error) {
-	var value *IndexValue = nil
-	err := i.db.View(func(txn *badger.Txn) error {
-		key, err := pkg.Int64ToBytes(int64(entryID))
-		if err != nil {
-			return err
-		}
-		item, err := txn.Get(key)
-		if errors.Is(err, badger.ErrKeyNotFound) {
-			return nil
-		}
-		if err != nil {
-			return err
-		}
-		value = &IndexValue{}
-		err = item.Value(func(val []byte) error {
-			value.deserialize(val)
-			return nil
-		})
-		return err
-	})
-	if err != nil {
-		return nil, err
+// NewIndex opens the index of the ledger in the format of IndexFile.Format, creating it if it does not exist.
+func NewIndex(ledgerID uint64) (Index, error) {
+	if myConfig.Format == pkg.IndexFormatFlat {
+		return newFlatIndex(ledgerID)
 	}
-	return value, nil
-}
-
-// LastItem returns the last entryID and index value in the ledger. If there is no item, indexValue
-// will be nil.
-//
-// Expected to be called in recovery.
-func (i *Index) LastItem() (entryID int, indexValue *IndexValue, err error) {
-	err = i.db.View(func(txn *badger.Txn) error {
-		opts := badger.DefaultIteratorOptions
-		opts.Reverse = true
-		it := txn.NewIterator(opts)
-		defer it.Close()
-		it.Rewind()
-		if !it.Valid() {
-			return nil
-		}
-		item := it.Item()
-		gotEntryID, err := pkg.BytesToInt64(item.Key())
-		if err != nil {
-			return err
-		}
-		entryID = int(gotEntryID)
-		indexValue = &IndexValue{}
-		err = item.Value(func(val []byte) error {
-			indexValue.deserialize(val)
-			return nil
-		})
-		return err
-	})
-	return
-}
-
-// Delete deletes the index file of the ledger.
-func (i *Index) Delete() error {
-	storagePath := makeStoragePathByLedgerID(i.ledgerID)
-	err := os.RemoveAll(storagePath)
-	return err
-}
-
-// Close closes the index file database.
-func (i *Index) Close() error {
-	return i.db.Close()
+	return newBadgerIndex(ledgerID)
 }
 
 type IndexValue struct {
 	Offset int
 	Size   int
+	// AppendTime is the time (in Unix milliseconds) at which the entry is appended, which is 0 if it is unknown.
+	AppendTime int64
 }
 
+// indexValueSize is the size of a serialized IndexValue.
+const indexValueSize = 24
+
+// deserialize deserializes the index value. The index values written before AppendTime was added are 16 bytes long,
+// whose AppendTime is 0, as are the slots of the flat index.
 func (iv *IndexValue) deserialize(data []byte) {
 	iv.Offset = int(binary.BigEndian.Uint64(data[:8]))
-	iv.Size = int(binary.BigEndian.Uint64(data[8:]))
+	iv.Size = int(binary.BigEndian.Uint64(data[8:16]))
+	iv.AppendTime = 0
+	if len(data) >= indexValueSize {
+		iv.AppendTime = int64(binary.BigEndian.Uint64(data[16:24]))
+	}
 }
 
 func (iv *IndexValue) serialize() []byte {
-	data := make([]byte, 16)
+	data := make([]byte, indexValueSize)
 	binary.BigEndian.PutUint64(data[:8], uint64(iv.Offset))
-	binary.BigEndian.PutUint64(data[8:], uint64(iv.Size))
+	binary.BigEndian.PutUint64(data[8:16], uint64(iv.Size))
+	binary.BigEndian.PutUint64(data[16:24], uint64(iv.AppendTime))
 	return data
 }
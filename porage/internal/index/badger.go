@@ -0,0 +1,248 @@
+package index
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"os"
+	"porage/internal/metrics"
+	"porage/internal/pkg"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+)
+
+// badgerIndex is the Index in a Badger database.
+//
+// In the per-ledger mode, the index of each ledger is a Badger database keyed by entryID. In the shared mode, the
+// indexes of all the ledgers are in one Badger database keyed by (ledgerID, entryID), and keyPrefix is the ledgerID.
+type badgerIndex struct {
+	ledgerID uint64
+
+	db        *badger.DB
+	keyPrefix []byte
+}
+
+func newBadgerIndex(ledgerID uint64) (*badgerIndex, error) {
+	if myConfig.Mode == pkg.IndexModeShared {
+		db, err := getSharedDB()
+		if err != nil {
+			return nil, err
+		}
+		return &badgerIndex{
+			ledgerID:  ledgerID,
+			db:        db,
+			keyPrefix: makeSharedKeyPrefix(ledgerID),
+		}, nil
+	}
+
+	// Open the Badger database
+	dbStoragePath := makeStoragePathByLedgerID(ledgerID)
+	db, err := openDB(dbStoragePath)
+	if err != nil {
+		return nil, err
+	}
+	return &badgerIndex{
+		ledgerID: ledgerID,
+		db:       db,
+	}, nil
+}
+
+// isShared reports whether the index is in the shared database.
+func (i *badgerIndex) isShared() bool {
+	return i.keyPrefix != nil
+}
+
+// makeKey returns the key of the entryID in the database.
+func (i *badgerIndex) makeKey(entryID int) ([]byte, error) {
+	entryIDKey, err := pkg.Int64ToBytes(int64(entryID))
+	if err != nil {
+		return nil, err
+	}
+	return append(append(make([]byte, 0, len(i.keyPrefix)+len(entryIDKey)), i.keyPrefix...), entryIDKey...), nil
+}
+
+// parseKey returns the entryID of a key in the database.
+func (i *badgerIndex) parseKey(key []byte) (int, error) {
+	entryID, err := pkg.BytesToInt64(key[len(i.keyPrefix):])
+	return int(entryID), err
+}
+
+// Put writes the index value to the badger database.
+func (i *badgerIndex) Put(entryID int, value *IndexValue) error {
+	defer metrics.ObserveSince(metrics.IndexOperationDuration.WithLabelValues("put"), time.Now())
+	// Write entryID as key and offset as value to myBadger.
+	err := i.db.Update(func(txn *badger.Txn) error {
+		key, err := i.makeKey(entryID)
+		if err != nil {
+			return err
+		}
+		value := value.serialize()
+		err = txn.Set(key, value)
+		return err
+	})
+	return err
+}
+
+// Get the index of the entryID in the ledgerID. If the entryID does not exist, return nil.
+func (i *badgerIndex) Get(entryID int) (*IndexValue, error) {
+	defer metrics.ObserveSince(metrics.IndexOperationDuration.WithLabelValues("get"), time.Now())
+	var value *IndexValue = nil
+	err := i.db.View(func(txn *badger.Txn) error {
+		key, err := i.makeKey(entryID)
+		if err != nil {
+			return err
+		}
+		item, err := txn.Get(key)
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		value = &IndexValue{}
+		err = item.Value(func(val []byte) error {
+			value.deserialize(val)
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Range calls fn with the entryIDs and the index values in [fromEntryID, toEntryID] in ascending order of entryID,
+// until fn returns false.
+func (i *badgerIndex) Range(fromEntryID int, toEntryID int, fn func(entryID int, value *IndexValue) bool) error {
+	defer metrics.ObserveSince(metrics.IndexOperationDuration.WithLabelValues("range"), time.Now())
+	return i.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = i.keyPrefix
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		fromKey, err := i.makeKey(fromEntryID)
+		if err != nil {
+			return err
+		}
+		for it.Seek(fromKey); it.ValidForPrefix(i.keyPrefix); it.Next() {
+			item := it.Item()
+			entryID, err := i.parseKey(item.Key())
+			if err != nil {
+				return err
+			}
+			if entryID > toEntryID {
+				return nil
+			}
+			value := &IndexValue{}
+			err = item.Value(func(val []byte) error {
+				value.deserialize(val)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if !fn(entryID, value) {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteRange removes the index values of the entryIDs in [fromEntryID, toEntryID] with a write batch.
+func (i *badgerIndex) DeleteRange(fromEntryID int, toEntryID int) error {
+	defer metrics.ObserveSince(metrics.IndexOperationDuration.WithLabelValues("delete_range"), time.Now())
+	keys := make([][]byte, 0)
+	err := i.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Prefix = i.keyPrefix
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		fromKey, err := i.makeKey(fromEntryID)
+		if err != nil {
+			return err
+		}
+		toKey, err := i.makeKey(toEntryID)
+		if err != nil {
+			return err
+		}
+		for it.Seek(fromKey); it.ValidForPrefix(i.keyPrefix) && bytes.Compare(it.Item().Key(), toKey) <= 0; it.Next() {
+			keys = append(keys, it.Item().KeyCopy(nil))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	batch := i.db.NewWriteBatch()
+	defer batch.Cancel()
+	for _, key := range keys {
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+	}
+	return batch.Flush()
+}
+
+// LastItem returns the last entryID and index value in the ledger. If there is no item, indexValue
+// will be nil.
+//
+// Expected to be called in recovery.
+func (i *badgerIndex) LastItem() (entryID int, indexValue *IndexValue, err error) {
+	err = i.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Reverse = true
+		opts.Prefix = i.keyPrefix
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		// In reverse, Seek finds the largest key not greater than the key of the largest entryID.
+		lastKey, err := i.makeKey(math.MaxInt64)
+		if err != nil {
+			return err
+		}
+		it.Seek(lastKey)
+		if !it.ValidForPrefix(i.keyPrefix) {
+			return nil
+		}
+		item := it.Item()
+		entryID, err = i.parseKey(item.Key())
+		if err != nil {
+			return err
+		}
+		indexValue = &IndexValue{}
+		err = item.Value(func(val []byte) error {
+			indexValue.deserialize(val)
+			return nil
+		})
+		return err
+	})
+	return
+}
+
+// Sync syncs the Badger database, which is the shared one in the shared mode.
+func (i *badgerIndex) Sync() error {
+	return i.db.Sync()
+}
+
+// Delete deletes the index file of the ledger. In the shared mode, the keys of the ledger are dropped from the shared
+// database.
+func (i *badgerIndex) Delete() error {
+	if i.isShared() {
+		return i.db.DropPrefix(i.keyPrefix)
+	}
+	storagePath := makeStoragePathByLedgerID(i.ledgerID)
+	err := os.RemoveAll(storagePath)
+	return err
+}
+
+// Close closes the index file database. In the shared mode, the shared database is left open until Stop.
+func (i *badgerIndex) Close() error {
+	if i.isShared() {
+		return nil
+	}
+	return i.db.Close()
+}
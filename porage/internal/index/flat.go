@@ -0,0 +1,341 @@
+package index
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"porage/internal/metrics"
+	"sync"
+	"time"
+)
+
+const (
+	// flatIndexSlotSize is the size of the slot of an entry in the flat index file, which holds [Offset][Size].
+	flatIndexSlotSize = 16
+	// flatAppendTimeSlotSize is the size of the slot of an entry in the append time file, which holds [AppendTime].
+	flatAppendTimeSlotSize = 8
+	// flatIndexGrowSize is the size by which the files of the flat index are extended. The extension is sparse, so it
+	// takes no disk space until the slots are written.
+	flatIndexGrowSize = 1 << 20
+)
+
+// flatIndex is the Index in fixed-width, append-only files. The slot of an entryID in the index file is at
+// entryID*16 and holds [Offset][Size] of the IndexValue. A slot of zeros is an entryID which does not exist, since
+// an entry in the entry logger is never empty.
+//
+// The AppendTime of an entryID is kept apart in the append time file at entryID*8, so that the index file keeps its
+// layout. The AppendTime of the entries put before the append time file existed is 0, i.e. unknown.
+type flatIndex struct {
+	ledgerID uint64
+
+	lock        *sync.RWMutex
+	slots       *flatFile
+	appendTimes *flatFile
+	// lastEntryID is the largest entryID in the index, which is -1 if the index is empty.
+	lastEntryID int
+}
+
+func newFlatIndex(ledgerID uint64) (*flatIndex, error) {
+	if err := os.MkdirAll(myConfig.StoragePath, 0755); err != nil {
+		return nil, err
+	}
+	slots, err := openFlatFile(makeFlatStoragePathByLedgerID(ledgerID), flatIndexSlotSize)
+	if err != nil {
+		return nil, err
+	}
+	appendTimes, err := openFlatFile(makeFlatAppendTimeStoragePathByLedgerID(ledgerID), flatAppendTimeSlotSize)
+	if err != nil {
+		slots.close()
+		return nil, err
+	}
+	i := &flatIndex{
+		ledgerID:    ledgerID,
+		lock:        &sync.RWMutex{},
+		slots:       slots,
+		appendTimes: appendTimes,
+		lastEntryID: -1,
+	}
+	if err := i.findLastEntryID(); err != nil {
+		i.Close()
+		return nil, err
+	}
+	return i, nil
+}
+
+// findLastEntryID finds the last slot which is not zeros.
+func (i *flatIndex) findLastEntryID() error {
+	for entryID := i.slots.slotCount() - 1; entryID >= 0; entryID-- {
+		value, err := i.readSlot(entryID)
+		if err != nil {
+			return err
+		}
+		if value != nil {
+			i.lastEntryID = entryID
+			return nil
+		}
+	}
+	return nil
+}
+
+// readSlot returns the index value in the slot of the entryID, which is nil if the slot is zeros.
+//
+// Expected to be called with the lock held.
+func (i *flatIndex) readSlot(entryID int) (*IndexValue, error) {
+	slot, err := i.slots.readSlot(entryID)
+	if err != nil {
+		return nil, err
+	}
+	if binary.BigEndian.Uint64(slot[8:16]) == 0 {
+		return nil, nil
+	}
+	value := &IndexValue{}
+	value.deserialize(slot)
+	appendTime, err := i.appendTimes.readSlot(entryID)
+	if err != nil {
+		return nil, err
+	}
+	value.AppendTime = int64(binary.BigEndian.Uint64(appendTime))
+	return value, nil
+}
+
+// Put writes the index value to the slot of the entryID. The AppendTime is written first, so that an entryID never
+// exists with the AppendTime of a deleted one.
+func (i *flatIndex) Put(entryID int, value *IndexValue) error {
+	defer metrics.ObserveSince(metrics.IndexOperationDuration.WithLabelValues("put"), time.Now())
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	data := value.serialize()
+	if err := i.appendTimes.writeSlot(entryID, data[flatIndexSlotSize:]); err != nil {
+		return err
+	}
+	if err := i.slots.writeSlot(entryID, data[:flatIndexSlotSize]); err != nil {
+		return err
+	}
+	i.lastEntryID = max(i.lastEntryID, entryID)
+	return nil
+}
+
+// Get the index of the entryID in the ledgerID. If the entryID does not exist, return nil.
+func (i *flatIndex) Get(entryID int) (*IndexValue, error) {
+	defer metrics.ObserveSince(metrics.IndexOperationDuration.WithLabelValues("get"), time.Now())
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	if entryID < 0 || entryID > i.lastEntryID {
+		return nil, nil
+	}
+	return i.readSlot(entryID)
+}
+
+// Range calls fn with the entryIDs and the index values in [fromEntryID, toEntryID] in ascending order of entryID,
+// until fn returns false.
+func (i *flatIndex) Range(fromEntryID int, toEntryID int, fn func(entryID int, value *IndexValue) bool) error {
+	defer metrics.ObserveSince(metrics.IndexOperationDuration.WithLabelValues("range"), time.Now())
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	for entryID := max(fromEntryID, 0); entryID <= min(toEntryID, i.lastEntryID); entryID++ {
+		value, err := i.readSlot(entryID)
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			continue
+		}
+		if !fn(entryID, value) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// DeleteRange zeros the slots of the entryIDs in [fromEntryID, toEntryID]. The files are not shrunk.
+func (i *flatIndex) DeleteRange(fromEntryID int, toEntryID int) error {
+	defer metrics.ObserveSince(metrics.IndexOperationDuration.WithLabelValues("delete_range"), time.Now())
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	fromEntryID = max(fromEntryID, 0)
+	toEntryID = min(toEntryID, i.lastEntryID)
+	if toEntryID < fromEntryID {
+		return nil
+	}
+	if err := i.slots.zeroSlots(fromEntryID, toEntryID); err != nil {
+		return err
+	}
+	if err := i.appendTimes.zeroSlots(fromEntryID, toEntryID); err != nil {
+		return err
+	}
+	if toEntryID == i.lastEntryID {
+		// Find the last slot which is not zeros below the deleted ones.
+		i.lastEntryID = -1
+		for entryID := fromEntryID - 1; entryID >= 0; entryID-- {
+			value, err := i.readSlot(entryID)
+			if err != nil {
+				return err
+			}
+			if value != nil {
+				i.lastEntryID = entryID
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// LastItem returns the last entryID and index value in the ledger. If there is no item, indexValue
+// will be nil.
+func (i *flatIndex) LastItem() (entryID int, indexValue *IndexValue, err error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	if i.lastEntryID < 0 {
+		return 0, nil, nil
+	}
+	indexValue, err = i.readSlot(i.lastEntryID)
+	return i.lastEntryID, indexValue, err
+}
+
+// Sync fsyncs the files of the index.
+func (i *flatIndex) Sync() error {
+	if err := i.appendTimes.sync(); err != nil {
+		return err
+	}
+	return i.slots.sync()
+}
+
+// Delete deletes the files of the index of the ledger.
+func (i *flatIndex) Delete() error {
+	if err := os.RemoveAll(makeFlatAppendTimeStoragePathByLedgerID(i.ledgerID)); err != nil {
+		return err
+	}
+	return os.RemoveAll(makeFlatStoragePathByLedgerID(i.ledgerID))
+}
+
+// Close unmaps, syncs and closes the files of the index.
+func (i *flatIndex) Close() error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	return errors.Join(i.appendTimes.close(), i.slots.close())
+}
+
+// flatFile is a file of fixed-width slots. The file is memory-mapped for reads where supported. The writes go
+// through the file, so the mapping is remapped only when the file is extended.
+type flatFile struct {
+	file     *os.File
+	slotSize int64
+	// size is the size of the file, which is a multiple of flatIndexGrowSize.
+	size int64
+	// mapping is the read-only memory mapping of the file. It is nil if the memory mapping is not supported.
+	mapping []byte
+}
+
+// openFlatFile opens the file of slotSize-byte slots, creating it if it does not exist.
+func openFlatFile(filePath string, slotSize int64) (*flatFile, error) {
+	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	f := &flatFile{
+		file:     file,
+		slotSize: slotSize,
+	}
+	fileInfo, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	// Extend the file to a multiple of flatIndexGrowSize in case the last extension was interrupted.
+	if err := f.grow(fileInfo.Size()); err != nil {
+		f.close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// grow extends the file so that it is at least minSize and a multiple of flatIndexGrowSize, and remaps it.
+func (f *flatFile) grow(minSize int64) error {
+	size := (minSize + flatIndexGrowSize - 1) / flatIndexGrowSize * flatIndexGrowSize
+	if size == 0 {
+		size = flatIndexGrowSize
+	}
+	if size == f.size {
+		return nil
+	}
+	if err := munmapFile(f.mapping); err != nil {
+		return err
+	}
+	f.mapping = nil
+	if err := f.file.Truncate(size); err != nil {
+		return err
+	}
+	f.size = size
+	mapping, err := mmapFile(f.file, size)
+	if err != nil {
+		return err
+	}
+	f.mapping = mapping
+	return nil
+}
+
+// slotCount returns the number of the slots in the file.
+func (f *flatFile) slotCount() int {
+	return int(f.size / f.slotSize)
+}
+
+// readSlot returns the slot at slotIndex, which is zeros if it is beyond the file.
+func (f *flatFile) readSlot(slotIndex int) ([]byte, error) {
+	slotOffset := int64(slotIndex) * f.slotSize
+	if slotOffset+f.slotSize > f.size {
+		return make([]byte, f.slotSize), nil
+	}
+	if f.mapping != nil {
+		return f.mapping[slotOffset : slotOffset+f.slotSize], nil
+	}
+	slot := make([]byte, f.slotSize)
+	if _, err := f.file.ReadAt(slot, slotOffset); err != nil {
+		return nil, err
+	}
+	return slot, nil
+}
+
+// writeSlot writes the slot at slotIndex, extending the file if needed.
+func (f *flatFile) writeSlot(slotIndex int, slot []byte) error {
+	slotOffset := int64(slotIndex) * f.slotSize
+	if slotOffset+f.slotSize > f.size {
+		if err := f.grow(slotOffset + f.slotSize); err != nil {
+			return err
+		}
+	}
+	_, err := f.file.WriteAt(slot, slotOffset)
+	return err
+}
+
+// zeroSlots zeros the slots in [fromSlotIndex, toSlotIndex] which are in the file.
+func (f *flatFile) zeroSlots(fromSlotIndex int, toSlotIndex int) error {
+	end := min(int64(toSlotIndex+1)*f.slotSize, f.size)
+	offset := int64(fromSlotIndex) * f.slotSize
+	if end <= offset {
+		return nil
+	}
+	zeros := make([]byte, min(flatIndexGrowSize, end-offset))
+	for ; offset < end; offset += int64(len(zeros)) {
+		if _, err := f.file.WriteAt(zeros[:min(int64(len(zeros)), end-offset)], offset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *flatFile) sync() error {
+	return f.file.Sync()
+}
+
+// close unmaps, syncs and closes the file.
+func (f *flatFile) close() error {
+	if err := munmapFile(f.mapping); err != nil {
+		return err
+	}
+	f.mapping = nil
+	if err := f.file.Sync(); err != nil {
+		f.file.Close()
+		return err
+	}
+	return f.file.Close()
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package index
+
+import "os"
+
+// mmapFile returns no mapping where the memory mapping is not supported, so that the reads go through the file.
+func mmapFile(file *os.File, size int64) ([]byte, error) {
+	return nil, nil
+}
+
+// munmapFile does nothing where the memory mapping is not supported.
+func munmapFile(mapping []byte) error {
+	return nil
+}
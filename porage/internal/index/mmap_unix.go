@@ -0,0 +1,21 @@
+//go:build unix
+
+package index
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapFile maps the first size bytes of the file read-only.
+func mmapFile(file *os.File, size int64) ([]byte, error) {
+	return syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+// munmapFile unmaps a mapping returned by mmapFile. A nil mapping is ignored.
+func munmapFile(mapping []byte) error {
+	if mapping == nil {
+		return nil
+	}
+	return syscall.Munmap(mapping)
+}
@@ -0,0 +1,128 @@
+package index
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"porage/internal/pkg"
+	"sync"
+
+	"github.com/dgraph-io/badger/v3"
+)
+
+var (
+	// sharedDB is the database of the indexes of all the ledgers in the shared mode. It is opened on the first use
+	// and closed by Stop.
+	sharedDB     *badger.DB
+	sharedDBLock = &sync.Mutex{}
+)
+
+// openDB opens a Badger database in storagePath.
+func openDB(storagePath string) (*badger.DB, error) {
+	option := badger.DefaultOptions(storagePath)
+	option.MemTableSize = myConfig.MemtableSize
+	option.Logger = pkg.Logger
+	return badger.Open(option)
+}
+
+// getSharedDB returns the shared database, which is opened on the first call. The per-ledger indexes left in the
+// storage path are migrated into the shared database when it is opened.
+func getSharedDB() (*badger.DB, error) {
+	sharedDBLock.Lock()
+	defer sharedDBLock.Unlock()
+	if sharedDB != nil {
+		return sharedDB, nil
+	}
+
+	db, err := openDB(makeSharedStoragePath())
+	if err != nil {
+		return nil, err
+	}
+	if err := migratePerLedgerIndexes(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	sharedDB = db
+	return sharedDB, nil
+}
+
+// closeSharedDB closes the shared database if it is open.
+func closeSharedDB() error {
+	sharedDBLock.Lock()
+	defer sharedDBLock.Unlock()
+	if sharedDB == nil {
+		return nil
+	}
+	err := sharedDB.Close()
+	sharedDB = nil
+	return err
+}
+
+// makeSharedKeyPrefix returns the prefix of the keys of a ledger in the shared database.
+func makeSharedKeyPrefix(ledgerID uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, ledgerID)
+}
+
+// migratePerLedgerIndexes moves the per-ledger indexes in the storage path into the shared database.
+func migratePerLedgerIndexes(db *badger.DB) error {
+	ledgerIDs, err := listPerLedgerIndexes()
+	if err != nil {
+		return err
+	}
+	for _, ledgerID := range ledgerIDs {
+		if err := migratePerLedgerIndex(db, ledgerID); err != nil {
+			return fmt.Errorf("failed to migrate index of ledger %d: %w", ledgerID, err)
+		}
+	}
+	return nil
+}
+
+// migratePerLedgerIndex copies the keys of a per-ledger index into the shared database and removes the per-ledger
+// index. The per-ledger index is removed only after the copied keys are synced, so an interrupted migration is redone
+// on the next open of the shared database.
+func migratePerLedgerIndex(db *badger.DB, ledgerID uint64) error {
+	storagePath := makeStoragePathByLedgerID(ledgerID)
+	perLedgerDB, err := openDB(storagePath)
+	if err != nil {
+		return err
+	}
+
+	keyPrefix := makeSharedKeyPrefix(ledgerID)
+	writeBatch := db.NewWriteBatch()
+	nKeys := 0
+	err = perLedgerDB.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			key := append(append(make([]byte, 0, len(keyPrefix)+len(item.Key())), keyPrefix...), item.Key()...)
+			if err := writeBatch.Set(key, value); err != nil {
+				return err
+			}
+			nKeys++
+		}
+		return nil
+	})
+	if closeErr := perLedgerDB.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		writeBatch.Cancel()
+		return err
+	}
+	if err := writeBatch.Flush(); err != nil {
+		return err
+	}
+	if err := db.Sync(); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(storagePath); err != nil {
+		return err
+	}
+	pkg.Logger.Infof("Migrated %d index items of ledger %d into the shared index", nKeys, ledgerID)
+	return nil
+}
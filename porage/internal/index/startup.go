@@ -10,3 +10,11 @@ var myConfig *pkg.IndexFileConfig
 func Startup(config *pkg.IndexFileConfig) {
 	myConfig = config
 }
+
+// Stop closes the shared index if it is open. Expected to be called after all the ledgers are closed.
+func Stop() {
+	if err := closeSharedDB(); err != nil {
+		pkg.Logger.Errorf("Failed to close shared index: %v", err)
+	}
+	pkg.Logger.Infof("Index stopped")
+}
@@ -1,10 +1,60 @@
 package index
 
 import (
+	"errors"
+	"io/fs"
+	"os"
 	"path"
 	"strconv"
+	"strings"
+)
+
+const (
+	// perLedgerStoragePrefix is the prefix of the directory names of the per-ledger indexes.
+	perLedgerStoragePrefix = "ledger_"
+	// sharedStorageName is the directory name of the shared index.
+	sharedStorageName = "shared"
+	// flatStorageSuffix is the suffix of the file names of the flat indexes.
+	flatStorageSuffix = ".index"
+	// flatAppendTimeStorageSuffix is the suffix of the file names of the append times of the flat indexes.
+	flatAppendTimeStorageSuffix = ".append_time"
 )
 
 func makeStoragePathByLedgerID(ledgerID uint64) string {
-	return path.Join(myConfig.StoragePath, "ledger_"+strconv.FormatUint(ledgerID, 10))
+	return path.Join(myConfig.StoragePath, perLedgerStoragePrefix+strconv.FormatUint(ledgerID, 10))
+}
+
+func makeFlatStoragePathByLedgerID(ledgerID uint64) string {
+	return makeStoragePathByLedgerID(ledgerID) + flatStorageSuffix
+}
+
+func makeFlatAppendTimeStoragePathByLedgerID(ledgerID uint64) string {
+	return makeStoragePathByLedgerID(ledgerID) + flatAppendTimeStorageSuffix
+}
+
+func makeSharedStoragePath() string {
+	return path.Join(myConfig.StoragePath, sharedStorageName)
+}
+
+// listPerLedgerIndexes returns the ledgerIDs of the per-ledger indexes in the storage path.
+func listPerLedgerIndexes() ([]uint64, error) {
+	dirEntries, err := os.ReadDir(myConfig.StoragePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ledgerIDs := make([]uint64, 0)
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.IsDir() || !strings.HasPrefix(dirEntry.Name(), perLedgerStoragePrefix) {
+			continue
+		}
+		ledgerID, err := strconv.ParseUint(strings.TrimPrefix(dirEntry.Name(), perLedgerStoragePrefix), 10, 64)
+		if err != nil {
+			continue
+		}
+		ledgerIDs = append(ledgerIDs, ledgerID)
+	}
+	return ledgerIDs, nil
 }
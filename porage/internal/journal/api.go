@@ -1,25 +1,38 @@
 package journal
 
 import (
-	"encoding/binary"
-	"os"
-	"path/filepath"
+	"context"
 	"porage/internal/pkg"
 	"time"
 )
 
 // AppendJournal appends a journal entry to the journal storage. The journal entry is guaranteed to
-// be written to the storage only if the notification channel notifies.
-func AppendJournal(entry *pkg.JournalEntryPayload) (pkg.NotificationRx, error) {
+// be written to the storage only if the notification channel notifies. ctx.Err() is returned if ctx is done
+// before the entry is handed over to the journal worker, in which case the entry is not appended.
+func AppendJournal(ctx context.Context, entry *pkg.JournalEntryPayload) (pkg.NotificationRx, error) {
+	return AppendJournalBatch(ctx, []*pkg.JournalEntryPayload{entry})
+}
+
+// AppendJournalBatch appends a batch of journal entries to the journal storage. The entries are written in
+// order and committed in the same group commit, so a single notification covers all of them.
+func AppendJournalBatch(ctx context.Context, entries []*pkg.JournalEntryPayload) (pkg.NotificationRx, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if uint64(len(messageBuffer)) > myConfig.MessageBufferBusyThreshold {
 		return nil, pkg.ErrBufferBusy
 	}
 	notificationChannel := make(pkg.NotificationChannel, 1)
-	messageBuffer <- &pkg.WriteRequest{
-		Entry:          entry,
+	writeRequest := &pkg.WriteRequest{
+		Entries:        entries,
 		NotificationTx: notificationChannel,
 	}
-	return notificationChannel, nil
+	select {
+	case messageBuffer <- writeRequest:
+		return notificationChannel, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 }
 
 func RegisterLedger(ledgerID uint64) {
@@ -38,49 +51,29 @@ func UpdateLedgerFlushTime(ledgerID uint64) {
 // If there are more segment files before the current segment file, `nextSegmentIdx` will be negative. Start
 // Use the returned `nextSegmentIdx` to read the next segment file. Start with `nextSegmentIdx` as 0.
 //
+// The checksum of every journal entry is verified. A torn entry at the tail of a segment is truncated away,
+// while a corrupted entry in the middle of a segment results in a *pkg.JournalCorruptionError.
+//
 // Expected to be called after the local storage is initialized for recovery.
 func ReadJournal(segmentIdx int) (entries []*JournalEntry, nextSegmentIdx int, err error) {
-	segmentFiles, _ := os.ReadDir(myConfig.StoragePath)
+	segmentFilePathList, err := getSegmentFilePathList()
+	if err != nil {
+		return nil, -1, err
+	}
 	// We do not read the current segment file.
-	if len(segmentFiles) == 1 {
+	if len(segmentFilePathList) == 1 {
 		return nil, -1, nil
 	}
-	pkg.Logger.Debugf("Reading journal entries from %d segment files.", len(segmentFiles)-1)
+	pkg.Logger.Debugf("Reading journal entries from %d segment files.", len(segmentFilePathList)-1)
 
-	segmentFile := segmentFiles[segmentIdx]
-	segmentPath := filepath.Join(myConfig.StoragePath, segmentFile.Name())
-	file, err := os.Open(segmentPath)
+	entries, err = readSegment(segmentFilePathList[segmentIdx])
 	if err != nil {
 		return nil, -1, err
 	}
-	defer file.Close()
-
-	entries = make([]*JournalEntry, 0)
-	for {
-		entry := &JournalEntry{}
-		err = binary.Read(file, binary.BigEndian, &entry.Size)
-		if err != nil {
-			break
-		}
-		err = binary.Read(file, binary.BigEndian, &entry.sequenceID)
-		if err != nil {
-			break
-		}
-		entryData := make([]byte, entry.Size)
-		_, err = file.Read(entryData)
-		if err != nil {
-			break
-		}
-		entry.Entry = pkg.DeserializeJournalEntry(entryData)
-		entries = append(entries, entry)
-	}
-	if err.Error() != "EOF" {
-		return nil, -1, err
-	}
 
 	// Do not read the current segment file.
 	nextSegmentIdx = segmentIdx + 1
-	if nextSegmentIdx == len(segmentFiles)-1 {
+	if nextSegmentIdx == len(segmentFilePathList)-1 {
 		return entries, -1, nil
 	}
 
@@ -92,6 +85,11 @@ func EnableTrimWorker() {
 	enableTrimming.Store(true)
 }
 
+// IsTrimWorkerEnabled reports whether the trim worker is enabled, which happens after the recovery.
+func IsTrimWorkerEnabled() bool {
+	return enableTrimming.Load()
+}
+
 // GetWorkerDescriptions returns the workers description.
 func GetWorkerDescriptions() map[string]*pkg.WorkerDescription {
 	return localWorkerControl.GetWorkerDescriptions()
@@ -1,41 +1,53 @@
 package journal
 
 import (
+	"porage/internal/metrics"
 	"porage/internal/pkg"
 	"time"
 )
 
+const journalWorkerName = "journal_worker"
+
 var (
 	messageBuffer chan *pkg.WriteRequest
 )
 
 // journal_worker is a goroutine that processes messages from the message buffer.
 // It receives messages from that buffer and dispatch them to the corresponding ledger channel.
-func journal_worker() {
-	workerName := "journal_worker"
-	workerDescriptionString := "Write the journal entries and group commit them"
-	workerDescription := pkg.NewWorkerDescription(workerDescriptionString)
-	localWorkerControl.RegisterWorker(workerName, workerDescription)
+func journal_worker(workerDescription *pkg.WorkerDescription) {
+	workerName := journalWorkerName
 
 	notificationChannelArray := make([]pkg.NotificationTx, 0, myConfig.GroupCommitThreasold)
+	nUncommittedEntries := uint64(0)
+	groupStartTime := time.Now()
 	groupCommitInterval := time.Duration(myConfig.GroupCommitInterval) * time.Millisecond
 	groupCommitIntervalTicker := time.NewTicker(groupCommitInterval)
 
 	shouldCommit := false
 	for {
+		workerDescription.Heartbeat()
 		select {
 		case message := <-messageBuffer:
 			pkg.Logger.Debugf("Worker receives a message: %v", message)
-			// Write the entry to the journal storage
-			journalEntry := NewJournalEntry(message.Entry)
-			if err := writeEntry(journalEntry); err != nil {
+			metrics.JournalMessageBufferDepth.Set(float64(len(messageBuffer)))
+			// Write the entries to the journal storage
+			journalEntries := make([]*JournalEntry, 0, len(message.Entries))
+			for _, entry := range message.Entries {
+				journalEntries = append(journalEntries, NewJournalEntry(entry))
+			}
+			if err := writeEntries(journalEntries); err != nil {
 				message.NotificationTx <- pkg.Notification{
 					Err: err,
 				}
 				continue
 			}
+			workerDescription.AddProcessed(len(journalEntries))
+			if len(notificationChannelArray) == 0 {
+				groupStartTime = time.Now()
+			}
 			notificationChannelArray = append(notificationChannelArray, message.NotificationTx)
-			if uint64(len(notificationChannelArray)) >= myConfig.GroupCommitThreasold {
+			nUncommittedEntries += uint64(len(journalEntries))
+			if nUncommittedEntries >= myConfig.GroupCommitThreasold {
 				shouldCommit = true
 				pkg.Logger.Debugf("Worker: should group commit triggerd by GroupCommitThreasold")
 			}
@@ -52,14 +64,21 @@ func journal_worker() {
 
 		if shouldCommit {
 			pkg.Logger.Debugf("Worker: group commit")
+			commitStartTime := time.Now()
 			commit()
+			metrics.ObserveSince(metrics.JournalFsyncDuration, commitStartTime)
 			// Notify and clear the notification array
 			for _, notificationChannel := range notificationChannelArray {
 				notificationChannel <- pkg.Notification{
 					Err: nil,
 				}
 			}
+			if len(notificationChannelArray) > 0 {
+				metrics.ObserveSince(metrics.JournalGroupCommitDuration, groupStartTime)
+				metrics.JournalGroupCommitEntries.Observe(float64(nUncommittedEntries))
+			}
 			notificationChannelArray = notificationChannelArray[:0]
+			nUncommittedEntries = 0
 			createNewSegmentIfNeed()
 			shouldCommit = false
 			groupCommitIntervalTicker.Reset(groupCommitInterval)
@@ -8,6 +8,8 @@ var myConfig *pkg.JournalConfig
 
 func Startup(config *pkg.JournalConfig) {
 	myConfig = config
+	// Trimming is enabled again after the recovery.
+	enableTrimming.Store(false)
 	startStorage()
 	startWorkers()
 }
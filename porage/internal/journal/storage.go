@@ -1,8 +1,11 @@
 package journal
 
 import (
+	"bufio"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"porage/internal/pkg"
@@ -11,12 +14,19 @@ import (
 	"time"
 )
 
-const journalFileSuffix = ".journal"
+const (
+	journalFileSuffix = ".journal"
+	// journalEntryHeaderSize is the size of [Size][SequenceID][HeaderChecksum][Checksum] in front of every record.
+	journalEntryHeaderSize = 8 + 8 + 4 + 4
+)
 
 var (
 	currentSegmentFile *os.File = nil
 
 	registeredLedgers = newLedgerRegisterationCenter()
+
+	errJournalChecksumMismatch       = errors.New("journal checksum mismatch")
+	errJournalHeaderChecksumMismatch = errors.New("journal header checksum mismatch")
 )
 
 type ledgerInfo struct {
@@ -75,6 +85,10 @@ func (lrc *ledgerRegisterationCenter) getLedgersMinFlushTime() int64 {
 
 // JournalEntry represents a journal entry.
 //
+// On-disk format: [Size][SequenceID][HeaderChecksum][Checksum][Payload]. HeaderChecksum is the CRC32C of Size and
+// SequenceID, so that a corrupted Size is not taken for the end of the segment. Checksum is the CRC32C of Size,
+// SequenceID and Payload.
+//
 // Export for testing.
 type JournalEntry struct {
 	// Timestamp sequence id. Valid after Complete is called.
@@ -104,26 +118,156 @@ func (je *JournalEntry) complete() {
 //
 // Export for testing.
 func (je *JournalEntry) WriteTo(w *os.File) error {
+	_, err := w.Write(je.appendRecord(nil))
+	return err
+}
+
+// appendRecord completes the journal entry and appends its on-disk record to buf.
+func (je *JournalEntry) appendRecord(buf []byte) []byte {
 	je.complete()
-	// Write the size
-	err := binary.Write(w, binary.BigEndian, je.Size)
+	start := len(buf)
+	buf = append(buf, make([]byte, journalEntryHeaderSize)...)
+	buf = append(buf, je.BinPayload...)
+	record := buf[start:]
+	binary.BigEndian.PutUint64(record[0:8], je.Size)
+	binary.BigEndian.PutUint64(record[8:16], je.sequenceID)
+	binary.BigEndian.PutUint32(record[16:20], pkg.Checksum(record[:16]))
+	binary.BigEndian.PutUint32(record[20:24], pkg.Checksum(record[:16], je.BinPayload))
+	return buf
+}
+
+// readJournalEntry reads one journal entry from the reader. `remaining` is the number of bytes left in the
+// segment file from the beginning of this record.
+//
+// io.EOF is returned if there is no record left. io.ErrUnexpectedEOF is returned if the record is incomplete.
+// errJournalHeaderChecksumMismatch is returned if the header does not match its checksum. errJournalChecksumMismatch
+// is returned if the record is complete but the checksum does not match.
+func readJournalEntry(reader io.Reader, remaining int64) (*JournalEntry, error) {
+	header := make([]byte, journalEntryHeaderSize)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+	if pkg.Checksum(header[:16]) != binary.BigEndian.Uint32(header[16:20]) {
+		return nil, errJournalHeaderChecksumMismatch
+	}
+	entry := &JournalEntry{
+		Size:       binary.BigEndian.Uint64(header[0:8]),
+		sequenceID: binary.BigEndian.Uint64(header[8:16]),
+	}
+	// The size is verified, so a size larger than the remaining bytes can only come from a partially written record.
+	if entry.Size > uint64(remaining-journalEntryHeaderSize) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	entry.BinPayload = make([]byte, entry.Size)
+	if _, err := io.ReadFull(reader, entry.BinPayload); err != nil {
+		return nil, err
+	}
+	if pkg.Checksum(header[:16], entry.BinPayload) != binary.BigEndian.Uint32(header[20:24]) {
+		return nil, errJournalChecksumMismatch
+	}
+	entry.Entry = pkg.DeserializeJournalEntry(entry.BinPayload)
+	return entry, nil
+}
+
+// readSegment reads all the journal entries in the segment file and verifies their checksums.
+//
+// A torn record at the tail of the segment, which is left by a crash in the middle of a write, is truncated
+// away. A record is torn if it is cut by the end of the segment, or if its header or payload checksum does not match
+// and no valid record follows it, e.g. a partially written header or the zeros left by the file system when the file
+// is extended but never written. Any other corrupted record results in a *pkg.JournalCorruptionError.
+func readSegment(segmentPath string) ([]*JournalEntry, error) {
+	file, err := os.OpenFile(segmentPath, os.O_RDWR, 0644)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	// Write the sequence ID
-	err = binary.Write(w, binary.BigEndian, je.sequenceID)
+	defer file.Close()
+	fileInfo, err := file.Stat()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	// Write the entry
-	_, err = w.Write(je.BinPayload)
-	return err
+	fileSize := fileInfo.Size()
+
+	entries := make([]*JournalEntry, 0)
+	reader := bufio.NewReader(file)
+	offset := int64(0)
+	for offset < fileSize {
+		entry, err := readJournalEntry(reader, fileSize-offset)
+		if err == nil {
+			entries = append(entries, entry)
+			offset += journalEntryHeaderSize + int64(entry.Size)
+			continue
+		}
+
+		isTorn := errors.Is(err, io.ErrUnexpectedEOF)
+		isCorrupted := false
+		if errors.Is(err, errJournalHeaderChecksumMismatch) || errors.Is(err, errJournalChecksumMismatch) {
+			// A crash only damages the tail of the segment, so a damaged record followed by a valid one is corrupted.
+			isCorrupted, err = hasValidRecordAfter(file, offset, fileSize)
+			if err != nil {
+				return nil, err
+			}
+			isTorn = !isCorrupted
+		}
+		if isCorrupted {
+			return nil, &pkg.JournalCorruptionError{SegmentPath: segmentPath, Offset: offset}
+		}
+		if !isTorn {
+			return nil, err
+		}
+
+		pkg.Logger.Warningf("Torn journal record found in segment %s at offset %d, truncating %d bytes", segmentPath, offset, fileSize-offset)
+		if err := file.Truncate(offset); err != nil {
+			return nil, err
+		}
+		if err := file.Sync(); err != nil {
+			return nil, err
+		}
+		break
+	}
+	return entries, nil
+}
+
+// hasValidRecordAfter returns true if a record with a valid header and payload checksum starts anywhere in the
+// file after offset.
+func hasValidRecordAfter(file *os.File, offset int64, fileSize int64) (bool, error) {
+	const windowSize = 64 * 1024
+	buf := make([]byte, windowSize+journalEntryHeaderSize)
+	for start := offset + 1; start+journalEntryHeaderSize <= fileSize; start += windowSize {
+		n, err := file.ReadAt(buf[:min(int64(len(buf)), fileSize-start)], start)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return false, err
+		}
+		for i := 0; i < windowSize && i+journalEntryHeaderSize <= n; i++ {
+			header := buf[i : i+journalEntryHeaderSize]
+			if pkg.Checksum(header[:16]) != binary.BigEndian.Uint32(header[16:20]) {
+				continue
+			}
+			payloadOffset := start + int64(i) + journalEntryHeaderSize
+			size := binary.BigEndian.Uint64(header[0:8])
+			if size > uint64(fileSize-payloadOffset) {
+				continue
+			}
+			payload := make([]byte, size)
+			if _, err := file.ReadAt(payload, payloadOffset); err != nil {
+				return false, err
+			}
+			if pkg.Checksum(header[:16], payload) == binary.BigEndian.Uint32(header[20:24]) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
 }
 
-// writeEntry writes a journal entry to the current segment file. The entry might be lost if the process crashes
-// before Commit is called.
-func writeEntry(entry *JournalEntry) error {
-	return entry.WriteTo(currentSegmentFile)
+// writeEntries writes the journal entries to the current segment file with a single write. The entries might be
+// lost if the process crashes before Commit is called.
+func writeEntries(entries []*JournalEntry) error {
+	var buf []byte
+	for _, entry := range entries {
+		buf = entry.appendRecord(buf)
+	}
+	_, err := currentSegmentFile.Write(buf)
+	return err
 }
 
 // commit flushes the current segment file to disk.
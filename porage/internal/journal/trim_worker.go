@@ -10,17 +10,18 @@ import (
 	"time"
 )
 
+const trimWorkerName = "trim_worker"
+
 var (
 	enableTrimming = atomic.Bool{}
 )
 
 // trim_worker is the worker that trims the journal entries.
-func trim_worker() {
-	workerName := "trim_worker"
-	workerDescription := pkg.NewWorkerDescription("Trim the journal entries")
-	localWorkerControl.RegisterWorker(workerName, workerDescription)
+func trim_worker(workerDescription *pkg.WorkerDescription) {
+	workerName := trimWorkerName
 
 	for {
+		workerDescription.Heartbeat()
 		select {
 		case <-workerDescription.StopChannel():
 			pkg.Logger.Infof("%s: stopped", workerName)
@@ -54,6 +55,7 @@ func trim_worker() {
 						pkg.Logger.Errorf("Failed to remove segment file %s: %v", nextSegmentFilePath, err)
 						continue
 					}
+					workerDescription.AddProcessed(1)
 					pkg.Logger.Infof("Removed segment file %s", nextSegmentFilePath)
 				}
 			}
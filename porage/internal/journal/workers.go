@@ -2,16 +2,29 @@ package journal
 
 import (
 	"porage/internal/pkg"
+	"time"
 )
 
 var (
 	localWorkerControl = pkg.NewLocalWorkerControl()
 )
 
+// startWorkers registers and starts the workers. Workers are registered before they start, so that they can be
+// stopped right after startWorkers returns.
 func startWorkers() {
 	messageBuffer = make(chan *pkg.WriteRequest, myConfig.MessageBufferSize)
-	go journal_worker()
-	go trim_worker()
+
+	journalWorkerDescription := pkg.NewWorkerDescription("Write the journal entries and group commit them")
+	journalWorkerDescription.SetQueueLength(func() int {
+		return len(messageBuffer)
+	})
+	localWorkerControl.RegisterWorker(journalWorkerName, journalWorkerDescription)
+	go journal_worker(journalWorkerDescription)
+
+	trimWorkerDescription := pkg.NewWorkerDescription("Trim the journal entries")
+	trimWorkerDescription.SetPeriod(time.Duration(myConfig.TrimInterval) * time.Second)
+	localWorkerControl.RegisterWorker(trimWorkerName, trimWorkerDescription)
+	go trim_worker(trimWorkerDescription)
 }
 
 func closeWorkers() {
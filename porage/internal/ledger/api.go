@@ -1,30 +1,217 @@
 package ledger
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
 	entrylogger "porage/internal/entry_logger"
 	"porage/internal/index"
 	"porage/internal/journal"
 	"porage/internal/memtable"
+	"porage/internal/metrics"
 	"porage/internal/pkg"
+	porage "porage/pkg"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
+// appendedBytes is the total payload size of the entries appended to the ledgers since the start, excluding the
+// entries put during the recovery.
+var appendedBytes atomic.Uint64
+
+// AppendedBytes returns the total payload size of the entries appended to the ledgers since the start, from which the
+// write throughput of the Pora is measured.
+func AppendedBytes() uint64 {
+	return appendedBytes.Load()
+}
+
+// LedgerState is the state of a ledger. A ledger only moves to a larger state.
+type LedgerState int
+
+const (
+	// LedgerStateOpen is the state of a ledger that accepts writes.
+	LedgerStateOpen LedgerState = iota
+	// LedgerStateFenced is the state of a ledger that rejects writes because its ownership has been moved to
+	// another writer.
+	LedgerStateFenced
+	// LedgerStateClosed is the state of a ledger that is sealed. It can only be read.
+	LedgerStateClosed
+)
+
+func (s LedgerState) String() string {
+	switch s {
+	case LedgerStateOpen:
+		return "open"
+	case LedgerStateFenced:
+		return "fenced"
+	case LedgerStateClosed:
+		return "closed"
+	}
+	return "unknown"
+}
+
+// appendError returns the error for the appends to a ledger in the state, or nil if the appends are accepted.
+func (s LedgerState) appendError() error {
+	switch s {
+	case LedgerStateFenced:
+		return porage.ErrLedgerFenced
+	case LedgerStateClosed:
+		return porage.ErrLedgerClosed
+	}
+	return nil
+}
+
+func parseLedgerState(state string) (LedgerState, error) {
+	switch state {
+	case "open":
+		return LedgerStateOpen, nil
+	case "fenced":
+		return LedgerStateFenced, nil
+	case "closed":
+		return LedgerStateClosed, nil
+	}
+	return LedgerStateOpen, fmt.Errorf("unknown ledger state %q", state)
+}
+
 type Ledger struct {
-	ledgerID        uint64
-	nextEntryID     int
+	ledgerID uint64
+	// creationTime and properties are the metadata persisted in the ledger file.
+	creationTime time.Time
+	properties   map[string]string
+	nextEntryID  int
+	// nextEntryIDLock protects nextEntryID, state, the moves of lowWaterMark and the acceptance of entries.
 	nextEntryIDLock *sync.Mutex
+	state           LedgerState
+	// lowWaterMark is the first entry ID which is not trimmed. The entries below it can not be read.
+	lowWaterMark *atomic.Int64
+	// inflightAppends is the number of appends which are accepted but not notified by the journal yet.
+	inflightAppends *sync.WaitGroup
+	// unflushedHoleEntryIDs is the set of the accepted entries below nextEntryID which are not flushed yet.
+	unflushedHoleEntryIDs     map[int]struct{}
+	unflushedHoleEntryIDsLock *sync.Mutex
+
+	// confirmationLock protects the fields below, which are about the entries confirmed by the journal.
+	confirmationLock *sync.Mutex
+	// lastConfirmedEntryID is the largest entry ID confirmed by the journal. All the entries accepted before it
+	// are confirmed as well.
+	lastConfirmedEntryID int
+	// unconfirmedHoleEntryIDs is the set of the accepted entries below nextEntryID which are not confirmed yet.
+	unconfirmedHoleEntryIDs map[int]struct{}
+	// failedEntryErrors are the errors of the failed journal commits by the IDs of their entries, which are never
+	// confirmed.
+	failedEntryErrors map[int]error
+	// confirmationChannel is closed and replaced to wake up the tailing readers when more entries are confirmed or
+	// the ledger is sealed or deleted.
+	confirmationChannel chan struct{}
+	isSealed            bool
+	isDeleted           bool
 
 	entryLogger *entrylogger.EntryLogger
-	index       *index.Index
+	index       index.Index
 	memtable    *memtable.MemTable
 
-	lastFlushedEntryID           *atomic.Int64
-	messageBuffer                chan *pkg.LedgerEntry
+	lastFlushedEntryID *atomic.Int64
+	// statsLock protects the counters below of the indexed entries which are not trimmed. It keeps a flush from
+	// indexing entries while a trim moves lowWaterMark and uncounts the trimmed entries.
+	statsLock      *sync.Mutex
+	entryCount     int
+	payloadBytes   int
+	lastAppendTime int64
+
+	messageBuffer chan *pkg.LedgerEntry
+	// messageBufferConsumed is signaled when the persistence worker takes an entry from messageBuffer.
+	messageBufferConsumed chan struct{}
+	// retentionPolicy is enforced by the retention worker. It does not change after the ledger is created.
+	retentionPolicy RetentionPolicy
+	// trimSignal is signaled when lowWaterMark moves, so that the persistence worker removes the trimmed entries from
+	// the index and the entry logger.
+	trimSignal                   chan struct{}
 	persistenceWorkerDescription *pkg.WorkerDescription
 }
 
+// CreateOptions are the options of a new ledger, which do not change after the ledger is created.
+type CreateOptions struct {
+	// RetentionPolicy is enforced by the retention worker. A zero RetentionPolicy keeps all the entries.
+	RetentionPolicy RetentionPolicy
+	// Properties are the custom properties given by the client.
+	Properties map[string]string
+}
+
+// NewLedger creates a new ledger with the default options and persists it in the file system.
 func NewLedger(ledgerID uint64) (*Ledger, error) {
+	return NewLedgerWithOptions(ledgerID, CreateOptions{})
+}
+
+// NewLedgerWithOptions creates a new ledger with options and persists it in the file system.
+func NewLedgerWithOptions(ledgerID uint64, options CreateOptions) (*Ledger, error) {
+	if err := options.RetentionPolicy.validate(); err != nil {
+		return nil, err
+	}
+	ledger, err := newLedger(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	ledger.creationTime = time.Now()
+	ledger.retentionPolicy = options.RetentionPolicy
+	ledger.properties = options.Properties
+	// The retention policy is persisted before the ledger file, which makes the ledger visible to the recovery.
+	if !ledger.retentionPolicy.IsZero() {
+		if err := ledger.persistState(LedgerStateOpen, 0); err != nil {
+			return nil, err
+		}
+	}
+	err = ledger.persistInFileSystem()
+	if err != nil {
+		return nil, err
+	}
+
+	ledger.startWorkers()
+	journal.RegisterLedger(ledgerID)
+	registerLedger(ledger)
+	return ledger, nil
+}
+
+// OpenLedger opens a ledger which is persisted in the file system with its persisted state.
+//
+// Expected to be called in recovery.
+func OpenLedger(ledgerID uint64) (*Ledger, error) {
+	ledger, err := newLedger(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ledger.loadMetadata(); err != nil {
+		return nil, err
+	}
+	persisted, err := ledger.loadState()
+	if err != nil {
+		return nil, err
+	}
+	ledger.state, err = parseLedgerState(persisted.State)
+	if err != nil {
+		return nil, err
+	}
+	ledger.isSealed = ledger.state == LedgerStateClosed
+	// The entries are appended after the trimmed ones even if they are all trimmed.
+	lowWaterMark := persisted.LowWaterMark
+	ledger.lowWaterMark.Store(int64(lowWaterMark))
+	ledger.nextEntryID = lowWaterMark
+	ledger.lastConfirmedEntryID = lowWaterMark - 1
+	if persisted.RetentionPolicy != nil {
+		ledger.retentionPolicy = *persisted.RetentionPolicy
+	}
+	if err := ledger.countIndexedEntries(); err != nil {
+		return nil, err
+	}
+
+	ledger.startWorkers()
+	journal.RegisterLedger(ledgerID)
+	registerLedger(ledger)
+	return ledger, nil
+}
+
+func newLedger(ledgerID uint64) (*Ledger, error) {
 	entryLogger, err := entrylogger.NewEntryLogger(ledgerID)
 	if err != nil {
 		return nil, err
@@ -42,21 +229,27 @@ func NewLedger(ledgerID uint64) (*Ledger, error) {
 
 	messageBuffer := make(chan *pkg.LedgerEntry, myConfig.EntryLogger.MessageBufferSize)
 	ledger := &Ledger{
-		ledgerID:           ledgerID,
-		nextEntryIDLock:    &sync.Mutex{},
-		entryLogger:        entryLogger,
-		index:              index,
-		memtable:           memtable,
-		lastFlushedEntryID: lastFlushedEntryID,
-		messageBuffer:      messageBuffer,
+		ledgerID:                  ledgerID,
+		nextEntryIDLock:           &sync.Mutex{},
+		state:                     LedgerStateOpen,
+		lowWaterMark:              &atomic.Int64{},
+		inflightAppends:           &sync.WaitGroup{},
+		unflushedHoleEntryIDs:     make(map[int]struct{}),
+		unflushedHoleEntryIDsLock: &sync.Mutex{},
+		confirmationLock:          &sync.Mutex{},
+		lastConfirmedEntryID:      -1,
+		unconfirmedHoleEntryIDs:   make(map[int]struct{}),
+		failedEntryErrors:         make(map[int]error),
+		confirmationChannel:       make(chan struct{}),
+		entryLogger:               entryLogger,
+		index:                     index,
+		memtable:                  memtable,
+		lastFlushedEntryID:        lastFlushedEntryID,
+		statsLock:                 &sync.Mutex{},
+		messageBuffer:             messageBuffer,
+		messageBufferConsumed:     make(chan struct{}, 1),
+		trimSignal:                make(chan struct{}, 1),
 	}
-	err = ledger.persistInFileSystem()
-	if err != nil {
-		return nil, err
-	}
-
-	ledger.startWorkers()
-	journal.RegisterLedger(ledgerID)
 	return ledger, nil
 }
 
@@ -65,59 +258,474 @@ func (l *Ledger) LedgerID() uint64 {
 	return l.ledgerID
 }
 
-// PutEntry puts the entry with entryID and payload into the ledger.
-func (l *Ledger) PutEntry(payload []byte) (int, error) {
+// State returns the state of the ledger.
+func (l *Ledger) State() LedgerState {
 	l.nextEntryIDLock.Lock()
+	defer l.nextEntryIDLock.Unlock()
+	return l.state
+}
+
+// PutEntry puts the entry with payload into the ledger and returns the entry ID assigned to it.
+// porage.ErrLedgerFenced or porage.ErrLedgerClosed is returned if the ledger is fenced or closed.
+//
+// If ctx is done before the entry is accepted, ctx.Err() is returned and the entry is not appended. If ctx is done
+// after that, a *porage.DurabilityUnknownError is returned with the entry ID.
+func (l *Ledger) PutEntry(ctx context.Context, payload []byte) (int, error) {
+	l.nextEntryIDLock.Lock()
+	if err := l.state.appendError(); err != nil {
+		l.nextEntryIDLock.Unlock()
+		return -1, err
+	}
 	entryID := l.nextEntryID
-	l.nextEntryID++
 	pkg.Logger.Debugf("PutEntry: entryID=%d, payload=%s", entryID, string(payload))
-	// Write journal
-	pkg.Logger.Debugf("PutEntry: entryID=%d, payload=%s, put into journal", entryID, string(payload))
-	journalEntryPayload := pkg.JournalEntryPayload{
-		LedgerID: l.ledgerID,
-		EntryID:  entryID,
-		Payload:  payload,
+	notificationRx, err := l.appendEntry(ctx, entryID, payload)
+	l.nextEntryIDLock.Unlock()
+	if err != nil {
+		return -1, err
+	}
+
+	err = l.waitForJournal(ctx, entryID, entryID, notificationRx)
+	pkg.Logger.Debugf("PutEntry: entryID=%d, payload=%s, done", entryID, string(payload))
+	return entryID, err
+}
+
+// PutEntries puts the entries with payloads into the ledger and returns the entry IDs assigned to them, in the
+// order of the payloads. The entries are committed by the journal together.
+// porage.ErrLedgerFenced or porage.ErrLedgerClosed is returned if the ledger is fenced or closed. ctx is handled
+// in the same way as PutEntry.
+func (l *Ledger) PutEntries(ctx context.Context, payloads [][]byte) ([]int, error) {
+	pendingAppend, err := l.AppendEntries(ctx, payloads)
+	if err != nil {
+		return nil, err
+	}
+	if err := pendingAppend.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return pendingAppend.EntryIDs(), nil
+}
+
+// PendingAppend is a batch of entries which are accepted by the ledger and waiting for the journal commit.
+type PendingAppend struct {
+	ledger         *Ledger
+	entryIDs       []int
+	notificationRx pkg.NotificationRx
+}
+
+// EntryIDs returns the entry IDs assigned to the entries in the batch, in the order of their payloads.
+func (pa *PendingAppend) EntryIDs() []int {
+	return pa.entryIDs
+}
+
+// Wait waits for the journal commit of the batch. The entries are persisted only if nil is returned. If ctx is
+// done before the commit completes, a *porage.DurabilityUnknownError is returned. Wait is expected to be called
+// exactly once.
+func (pa *PendingAppend) Wait(ctx context.Context) error {
+	if len(pa.entryIDs) == 0 {
+		return nil
+	}
+	return pa.ledger.waitForJournal(ctx, pa.entryIDs[0], pa.entryIDs[len(pa.entryIDs)-1], pa.notificationRx)
+}
+
+// AppendEntries accepts the entries with payloads into the ledger with consecutive entry IDs and appends them to
+// the journal as a single batch, without waiting for the journal commit. The batches are accepted in the order
+// AppendEntries is called, so the caller can pipeline the batches and wait for them later.
+// porage.ErrLedgerFenced or porage.ErrLedgerClosed is returned if the ledger is fenced or closed. ctx.Err() is
+// returned if ctx is done before the batch is accepted.
+func (l *Ledger) AppendEntries(ctx context.Context, payloads [][]byte) (*PendingAppend, error) {
+	l.nextEntryIDLock.Lock()
+	defer l.nextEntryIDLock.Unlock()
+	if err := l.state.appendError(); err != nil {
+		return nil, err
+	}
+	pendingAppend := &PendingAppend{
+		ledger:   l,
+		entryIDs: make([]int, 0, len(payloads)),
+	}
+	if len(payloads) == 0 {
+		return pendingAppend, nil
+	}
+
+	appendTime := time.Now().UnixMilli()
+	journalEntryPayloads := make([]*pkg.JournalEntryPayload, 0, len(payloads))
+	for i, payload := range payloads {
+		journalEntryPayloads = append(journalEntryPayloads, &pkg.JournalEntryPayload{
+			LedgerID:   l.ledgerID,
+			EntryID:    l.nextEntryID + i,
+			AppendTime: appendTime,
+			Payload:    payload,
+		})
+	}
+	pkg.Logger.Debugf("AppendEntries: entryIDs=[%d, %d]", l.nextEntryID, l.nextEntryID+len(payloads)-1)
+	if err := l.waitForMessageBuffer(ctx, len(payloads)); err != nil {
+		return nil, err
 	}
-	notificationRx, err := journal.AppendJournal(&journalEntryPayload)
+	notificationRx, err := journal.AppendJournalBatch(ctx, journalEntryPayloads)
 	if err != nil {
+		return nil, err
+	}
+	for _, journalEntryPayload := range journalEntryPayloads {
+		l.acceptEntry(&pkg.LedgerEntry{
+			EntryID:    journalEntryPayload.EntryID,
+			Payload:    journalEntryPayload.Payload,
+			AppendTime: journalEntryPayload.AppendTime,
+		})
+		pendingAppend.entryIDs = append(pendingAppend.entryIDs, journalEntryPayload.EntryID)
+		appendedBytes.Add(uint64(len(journalEntryPayload.Payload)))
+	}
+	pendingAppend.notificationRx = notificationRx
+	l.inflightAppends.Add(1)
+	return pendingAppend, nil
+}
+
+// PutEntryWithID puts the entry with the entryID assigned by the client into the ledger. It is idempotent: putting
+// an entry with the same entryID and payload again has no effect and waits for the journal commit of the existing
+// entry like the first put, while putting a different payload at an existing entryID fails with
+// porage.ErrEntryIDConflict. Entry IDs are not required to be contiguous, and
+// missing entries below the last entry ID can be filled later.
+//
+// porage.ErrLedgerFenced or porage.ErrLedgerClosed is returned if the ledger is fenced or closed. ctx is handled in
+// the same way as PutEntry.
+func (l *Ledger) PutEntryWithID(ctx context.Context, entryID int, payload []byte) error {
+	if entryID < 0 {
+		return porage.ErrInvalidEntryID
+	}
+
+	l.nextEntryIDLock.Lock()
+	if err := l.state.appendError(); err != nil {
 		l.nextEntryIDLock.Unlock()
-		return -1, err
+		return err
+	}
+	if entryID < l.nextEntryID {
+		// The entry is accepted before it is appended to the journal, so it is visible here once accepted.
+		existingEntry, err := l.GetEntry(entryID)
+		if err != nil {
+			l.nextEntryIDLock.Unlock()
+			return err
+		}
+		if existingEntry != nil {
+			l.nextEntryIDLock.Unlock()
+			if !bytes.Equal(existingEntry.Payload, payload) {
+				return porage.ErrEntryIDConflict
+			}
+			pkg.Logger.Debugf("PutEntryWithID: entryID=%d already exists with the same payload", entryID)
+			return l.waitForConfirmation(ctx, entryID)
+		}
+	}
+	pkg.Logger.Debugf("PutEntryWithID: entryID=%d, payload=%s", entryID, string(payload))
+	notificationRx, err := l.appendEntry(ctx, entryID, payload)
+	l.nextEntryIDLock.Unlock()
+	if err != nil {
+		return err
 	}
 
-	// Write to memtable
-	pkg.Logger.Debugf("PutEntry: entryID=%d, payload=%s, put into memtable", entryID, string(payload))
-	ledgerEntry := &pkg.LedgerEntry{
-		EntryID: entryID,
-		Payload: payload,
+	err = l.waitForJournal(ctx, entryID, entryID, notificationRx)
+	pkg.Logger.Debugf("PutEntryWithID: entryID=%d, payload=%s, done", entryID, string(payload))
+	return err
+}
+
+// appendEntry appends the entry to the journal and accepts it. The returned channel notifies when the entry is
+// written to the journal.
+//
+// Expected to be called with nextEntryIDLock held.
+func (l *Ledger) appendEntry(ctx context.Context, entryID int, payload []byte) (pkg.NotificationRx, error) {
+	journalEntryPayload := pkg.JournalEntryPayload{
+		LedgerID:   l.ledgerID,
+		EntryID:    entryID,
+		AppendTime: time.Now().UnixMilli(),
+		Payload:    payload,
 	}
-	l.memtable.Put(ledgerEntry)
+	if err := l.waitForMessageBuffer(ctx, 1); err != nil {
+		return nil, err
+	}
+	notificationRx, err := journal.AppendJournal(ctx, &journalEntryPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	if entryID < l.nextEntryID {
+		l.confirmationLock.Lock()
+		l.unconfirmedHoleEntryIDs[entryID] = struct{}{}
+		l.confirmationLock.Unlock()
+	}
+	l.acceptEntry(&pkg.LedgerEntry{
+		EntryID:    entryID,
+		Payload:    payload,
+		AppendTime: journalEntryPayload.AppendTime,
+	})
+	appendedBytes.Add(uint64(len(payload)))
+	l.inflightAppends.Add(1)
+	return notificationRx, nil
+}
+
+// waitForMessageBuffer waits until the message buffer has room for nEntries entries, or is empty if nEntries exceeds
+// its capacity. An entry cannot be withdrawn once it is appended to the journal, so the wait is done before the
+// journal append, where giving up on ctx leaves the ledger untouched.
+//
+// Expected to be called with nextEntryIDLock held, so that no other entry is sent to the message buffer meanwhile.
+func (l *Ledger) waitForMessageBuffer(ctx context.Context, nEntries int) error {
+	for len(l.messageBuffer) > 0 && len(l.messageBuffer)+nEntries > cap(l.messageBuffer) {
+		select {
+		case <-l.messageBufferConsumed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
 
-	// Async flush
-	pkg.Logger.Debugf("PutEntry: entryID=%d, payload=%s, put into message buffer", entryID, string(payload))
+// acceptEntry puts the entry into the memtable and sends it to the persistence worker. An entry below nextEntryID
+// fills a hole and is tracked until it is flushed, so that it is not trimmed from the memtable before that.
+//
+// Expected to be called with nextEntryIDLock held.
+func (l *Ledger) acceptEntry(ledgerEntry *pkg.LedgerEntry) {
+	if ledgerEntry.EntryID < l.nextEntryID {
+		l.unflushedHoleEntryIDsLock.Lock()
+		l.unflushedHoleEntryIDs[ledgerEntry.EntryID] = struct{}{}
+		l.unflushedHoleEntryIDsLock.Unlock()
+	} else {
+		l.nextEntryID = ledgerEntry.EntryID + 1
+	}
+	l.memtable.Put(ledgerEntry)
 	l.messageBuffer <- ledgerEntry
+}
+
+// waitForJournal waits for the notification of an append of the entries in [firstEntryID, lastEntryID]. If ctx is
+// done before that, the append is completed in the background and a *porage.DurabilityUnknownError is returned.
+func (l *Ledger) waitForJournal(ctx context.Context, firstEntryID int, lastEntryID int, notificationRx pkg.NotificationRx) error {
+	select {
+	case notification := <-notificationRx:
+		return l.completeAppend(firstEntryID, lastEntryID, notification)
+	case <-ctx.Done():
+		go func() {
+			l.completeAppend(firstEntryID, lastEntryID, <-notificationRx)
+		}()
+		return &porage.DurabilityUnknownError{
+			FirstEntryID: firstEntryID,
+			LastEntryID:  lastEntryID,
+			Err:          ctx.Err(),
+		}
+	}
+}
+
+// completeAppend confirms the appended entries in [firstEntryID, lastEntryID] for the tailing readers if the journal
+// commit succeeds, or records the failure otherwise, and trims the memtable if needed.
+func (l *Ledger) completeAppend(firstEntryID int, lastEntryID int, notification pkg.Notification) error {
+	if notification.Err == nil {
+		l.confirmEntry(lastEntryID)
+		metrics.LedgerAppendedEntries.WithLabelValues(metrics.LedgerLabel(l.ledgerID)).Add(float64(lastEntryID - firstEntryID + 1))
+	} else {
+		l.failEntries(firstEntryID, lastEntryID, notification.Err)
+	}
+	l.inflightAppends.Done()
+	l.trimMemtableIfNeeded()
+	return notification.Err
+}
+
+// confirmEntry confirms the entry whose journal commit is completed and wakes up the tailing readers. The journal
+// notifies the entries in the order they are accepted.
+func (l *Ledger) confirmEntry(entryID int) {
+	l.confirmationLock.Lock()
+	defer l.confirmationLock.Unlock()
+	if _, ok := l.unconfirmedHoleEntryIDs[entryID]; ok {
+		delete(l.unconfirmedHoleEntryIDs, entryID)
+	} else {
+		l.lastConfirmedEntryID = max(l.lastConfirmedEntryID, entryID)
+	}
+	l.wakeUpTailingReaders()
+}
+
+// failEntries records the failed journal commit of the entries in [firstEntryID, lastEntryID] and wakes up the
+// retries waiting for their confirmation.
+func (l *Ledger) failEntries(firstEntryID int, lastEntryID int, err error) {
+	l.confirmationLock.Lock()
+	defer l.confirmationLock.Unlock()
+	for entryID := firstEntryID; entryID <= lastEntryID; entryID++ {
+		l.failedEntryErrors[entryID] = err
+	}
+	l.wakeUpTailingReaders()
+}
+
+// waitForConfirmation waits for the journal commit of the accepted entry, and returns its error if the commit
+// failed. If ctx is done before that, a *porage.DurabilityUnknownError is returned.
+func (l *Ledger) waitForConfirmation(ctx context.Context, entryID int) error {
+	for {
+		l.confirmationLock.Lock()
+		err, isFailed := l.failedEntryErrors[entryID]
+		_, isUnconfirmedHole := l.unconfirmedHoleEntryIDs[entryID]
+		isConfirmed := entryID <= l.lastConfirmedEntryID && !isUnconfirmedHole
+		isDeleted := l.isDeleted
+		confirmationChannel := l.confirmationChannel
+		l.confirmationLock.Unlock()
+
+		switch {
+		case isFailed:
+			return err
+		case isConfirmed:
+			return nil
+		case isDeleted:
+			return porage.ErrLedgerNotFound
+		}
+		select {
+		case <-confirmationChannel:
+		case <-ctx.Done():
+			return &porage.DurabilityUnknownError{
+				FirstEntryID: entryID,
+				LastEntryID:  entryID,
+				Err:          ctx.Err(),
+			}
+		}
+	}
+}
+
+// wakeUpTailingReaders wakes up the readers waiting in WaitForEntries and the retries waiting in
+// waitForConfirmation.
+//
+// Expected to be called with confirmationLock held.
+func (l *Ledger) wakeUpTailingReaders() {
+	close(l.confirmationChannel)
+	l.confirmationChannel = make(chan struct{})
+}
+
+// trimMemtableIfNeeded trims the flushed entries from the memtable if the memtable meets the trim threshold.
+func (l *Ledger) trimMemtableIfNeeded() {
+	if !l.memtable.MeetTrimThreshold() {
+		return
+	}
+	trimUntil := int(l.lastFlushedEntryID.Load())
+	l.unflushedHoleEntryIDsLock.Lock()
+	for entryID := range l.unflushedHoleEntryIDs {
+		trimUntil = min(trimUntil, entryID-1)
+	}
+	l.unflushedHoleEntryIDsLock.Unlock()
+	l.memtable.TrimUntil(trimUntil)
+}
+
+// Fence fences the ledger so that all the following appends are rejected with porage.ErrLedgerFenced. The
+// fenced state is persisted before Fence returns. Fence waits for the in-flight appends to complete and returns
+// the last entry ID persisted in the ledger, which is -1 if there is no entry.
+//
+// Fencing a fenced or closed ledger has no effect but returning the last entry ID.
+func (l *Ledger) Fence() (int, error) {
+	return l.moveToReadOnlyState(LedgerStateFenced)
+}
+
+// Close seals the ledger so that all the following appends are rejected with porage.ErrLedgerClosed, while the
+// entries can still be read. The closed state is persisted before Close returns. Close waits for the in-flight
+// appends to complete and returns the last entry ID persisted in the ledger, which is -1 if there is no entry.
+//
+// Closing a closed ledger has no effect but returning the last entry ID.
+func (l *Ledger) Close() (int, error) {
+	return l.moveToReadOnlyState(LedgerStateClosed)
+}
+
+// moveToReadOnlyState moves the ledger to the state rejecting appends if the ledger is in a smaller state.
+func (l *Ledger) moveToReadOnlyState(state LedgerState) (int, error) {
+	l.nextEntryIDLock.Lock()
+	if l.state < state {
+		if err := l.persistState(state, int(l.lowWaterMark.Load())); err != nil {
+			l.nextEntryIDLock.Unlock()
+			return -1, err
+		}
+		l.state = state
+		pkg.Logger.Infof("Ledger %d is %v", l.ledgerID, state)
+	}
+	lastEntryID := l.nextEntryID - 1
 	l.nextEntryIDLock.Unlock()
 
-	notification := <-notificationRx
-	if l.memtable.MeetTrimThreshold() {
-		l.memtable.TrimUntil(int(l.lastFlushedEntryID.Load()))
+	l.inflightAppends.Wait()
+	if state == LedgerStateClosed {
+		// All the accepted entries are confirmed now, so the tailing readers can finish after reading them.
+		l.confirmationLock.Lock()
+		l.isSealed = true
+		l.wakeUpTailingReaders()
+		l.confirmationLock.Unlock()
 	}
+	return lastEntryID, nil
+}
 
-	pkg.Logger.Debugf("PutEntry: entryID=%d, payload=%s, done", entryID, string(payload))
-	return entryID, notification.Err
+// Trim trims the entries up to untilEntryID, so that they can not be read afterwards and porage.ErrEntryTrimmed is
+// returned instead. The low-water mark, which is the first entry ID not trimmed, is persisted before Trim returns.
+// The persistence worker removes the trimmed entries from the index and reclaims their space in the entry logger
+// afterwards.
+//
+// porage.ErrInvalidEntryID is returned if untilEntryID is negative or not below the next entry ID. Trimming the
+// trimmed entries has no effect.
+func (l *Ledger) Trim(untilEntryID int) error {
+	l.nextEntryIDLock.Lock()
+	defer l.nextEntryIDLock.Unlock()
+	if untilEntryID < 0 || untilEntryID >= l.nextEntryID {
+		return porage.ErrInvalidEntryID
+	}
+	if untilEntryID < int(l.lowWaterMark.Load()) {
+		return nil
+	}
+
+	l.statsLock.Lock()
+	defer l.statsLock.Unlock()
+	nTrimmedEntries, nTrimmedBytes := 0, 0
+	err := l.index.Range(l.LowWaterMark(), untilEntryID, func(entryID int, value *index.IndexValue) bool {
+		nTrimmedEntries++
+		nTrimmedBytes += l.entryLogger.PayloadSize(value.Size)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if err := l.persistState(l.state, untilEntryID+1); err != nil {
+		return err
+	}
+	l.lowWaterMark.Store(int64(untilEntryID + 1))
+	l.entryCount -= nTrimmedEntries
+	l.payloadBytes -= nTrimmedBytes
+	pkg.Logger.Infof("Ledger %d is trimmed until entry %d", l.ledgerID, untilEntryID)
+	l.signalTrim()
+	return nil
+}
+
+// signalTrim wakes up the persistence worker to remove the trimmed entries.
+func (l *Ledger) signalTrim() {
+	select {
+	case l.trimSignal <- struct{}{}:
+	default:
+	}
+}
+
+// LowWaterMark returns the first entry ID which is not trimmed.
+func (l *Ledger) LowWaterMark() int {
+	return int(l.lowWaterMark.Load())
 }
 
 // GetEntry returns the entry with entryID in the ledger. If the entry does not exist, return nil.
+// porage.ErrEntryTrimmed is returned if the entry is trimmed.
 func (l *Ledger) GetEntry(entryID int) (*pkg.LedgerEntry, error) {
+	if entryID < l.LowWaterMark() {
+		return nil, porage.ErrEntryTrimmed
+	}
+	entry, err := l.getEntry(entryID)
+	// The entry may be trimmed while it is read.
+	if entryID < l.LowWaterMark() {
+		return nil, porage.ErrEntryTrimmed
+	}
+	return entry, err
+}
+
+func (l *Ledger) getEntry(entryID int) (*pkg.LedgerEntry, error) {
 	// Get from memtable first
 	entry, err := l.memtable.Get(entryID)
 	if err != nil {
 		return nil, err
 	}
 	if entry != nil {
+		metrics.MemtableLookups.WithLabelValues("hit").Inc()
+		metrics.LedgerReadEntries.WithLabelValues(metrics.LedgerLabel(l.ledgerID)).Inc()
 		return entry, nil
 	}
+	metrics.MemtableLookups.WithLabelValues("miss").Inc()
 
-	// Get from entry logger
+	// Get from entry logger. The entry log at the position looked up stays open until it is read, even if it is
+	// compacted meanwhile.
+	endRead := entrylogger.BeginRead()
+	defer endRead()
 	index, err := l.index.Get(entryID)
 	if err != nil {
 		return nil, err
@@ -125,10 +733,189 @@ func (l *Ledger) GetEntry(entryID int) (*pkg.LedgerEntry, error) {
 	if index == nil {
 		return nil, nil
 	}
+	metrics.LedgerReadEntries.WithLabelValues(metrics.LedgerLabel(l.ledgerID)).Inc()
 	return l.entryLogger.Read(index.Offset, index.Size)
 }
 
-// Length returns the number of entries in the ledger.
+// ReadEntries returns the entries with entryID in [fromEntryID, toEntryID] in ascending order of entryID. Missing
+// entries are skipped. The total payload size of the returned entries does not exceed maxBytes unless maxBytes is
+// not positive, except that the first entry is always returned. Sequential entries in the entry logger are read
+// together. porage.ErrEntryTrimmed is returned if fromEntryID is trimmed.
+func (l *Ledger) ReadEntries(fromEntryID int, toEntryID int, maxBytes int) ([]*pkg.LedgerEntry, error) {
+	if fromEntryID < 0 {
+		return nil, porage.ErrInvalidEntryID
+	}
+	if fromEntryID < l.LowWaterMark() {
+		return nil, porage.ErrEntryTrimmed
+	}
+	entries, err := l.readEntries(fromEntryID, toEntryID, maxBytes)
+	// The entries may be trimmed while they are read.
+	if fromEntryID < l.LowWaterMark() {
+		return nil, porage.ErrEntryTrimmed
+	}
+	return entries, err
+}
+
+func (l *Ledger) readEntries(fromEntryID int, toEntryID int, maxBytes int) ([]*pkg.LedgerEntry, error) {
+	l.nextEntryIDLock.Lock()
+	toEntryID = min(toEntryID, l.nextEntryID-1)
+	l.nextEntryIDLock.Unlock()
+	if toEntryID < fromEntryID {
+		return []*pkg.LedgerEntry{}, nil
+	}
+
+	// The memtable is read before the index, because an entry is trimmed from the memtable only after it is
+	// indexed. Otherwise an entry trimmed between the two reads would be missed.
+	memEntries := l.memtable.Range(fromEntryID, toEntryID, maxBytes)
+
+	entries := make([]*pkg.LedgerEntry, 0, len(memEntries))
+	pendingReads := make([]*pendingRead, 0)
+	nBytes := 0
+	isFull := false
+	tryAppend := func(entry *pkg.LedgerEntry, payloadSize int) bool {
+		if maxBytes > 0 && len(entries) > 0 && nBytes+payloadSize > maxBytes {
+			isFull = true
+			return false
+		}
+		entries = append(entries, entry)
+		nBytes += payloadSize
+		return true
+	}
+
+	// The entry logs at the positions looked up stay open until they are read, even if they are compacted meanwhile.
+	endRead := entrylogger.BeginRead()
+	defer endRead()
+	memPosition := 0
+	err := l.index.Range(fromEntryID, toEntryID, func(entryID int, value *index.IndexValue) bool {
+		for ; memPosition < len(memEntries) && memEntries[memPosition].EntryID <= entryID; memPosition++ {
+			if !tryAppend(memEntries[memPosition], len(memEntries[memPosition].Payload)) {
+				return false
+			}
+		}
+		// Prefer the entry in the memtable.
+		if len(entries) > 0 && entries[len(entries)-1] != nil && entries[len(entries)-1].EntryID == entryID {
+			return true
+		}
+		pendingReads = append(pendingReads, &pendingRead{position: len(entries), value: value})
+		if !tryAppend(nil, l.entryLogger.PayloadSize(value.Size)) {
+			pendingReads = pendingReads[:len(pendingReads)-1]
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	for ; !isFull && memPosition < len(memEntries); memPosition++ {
+		tryAppend(memEntries[memPosition], len(memEntries[memPosition].Payload))
+	}
+
+	if err := l.readPendingEntries(entries, pendingReads); err != nil {
+		return nil, err
+	}
+	metrics.MemtableLookups.WithLabelValues("hit").Add(float64(len(entries) - len(pendingReads)))
+	metrics.MemtableLookups.WithLabelValues("miss").Add(float64(len(pendingReads)))
+	metrics.LedgerReadEntries.WithLabelValues(metrics.LedgerLabel(l.ledgerID)).Add(float64(len(entries)))
+	return entries, nil
+}
+
+// WaitForEntries blocks until there are confirmed entries from fromEntryID, and returns them like ReadEntries. An
+// entry is confirmed once its journal commit is completed. Entries filling holes below fromEntryID are not
+// returned.
+//
+// porage.ErrLedgerClosed is returned if the ledger is closed and all its entries from fromEntryID are returned.
+// porage.ErrLedgerNotFound is returned if the ledger is deleted. The error of ctx is returned if ctx is done.
+func (l *Ledger) WaitForEntries(ctx context.Context, fromEntryID int, maxBytes int) ([]*pkg.LedgerEntry, error) {
+	for {
+		l.confirmationLock.Lock()
+		if l.isDeleted {
+			l.confirmationLock.Unlock()
+			return nil, porage.ErrLedgerNotFound
+		}
+		lastConfirmedEntryID := l.lastConfirmedEntryID
+		isSealed := l.isSealed
+		confirmationChannel := l.confirmationChannel
+		l.confirmationLock.Unlock()
+
+		if fromEntryID <= lastConfirmedEntryID {
+			entries, err := l.ReadEntries(fromEntryID, lastConfirmedEntryID, maxBytes)
+			if err != nil {
+				if l.IsDeleted() {
+					return nil, porage.ErrLedgerNotFound
+				}
+				return nil, err
+			}
+			entries = l.cutAtUnconfirmedEntry(entries)
+			if len(entries) > 0 {
+				return entries, nil
+			}
+		}
+		if isSealed {
+			return nil, porage.ErrLedgerClosed
+		}
+
+		select {
+		case <-confirmationChannel:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// cutAtUnconfirmedEntry returns the entries before the first entry which fills a hole but is not confirmed yet.
+func (l *Ledger) cutAtUnconfirmedEntry(entries []*pkg.LedgerEntry) []*pkg.LedgerEntry {
+	l.confirmationLock.Lock()
+	defer l.confirmationLock.Unlock()
+	if len(l.unconfirmedHoleEntryIDs) == 0 {
+		return entries
+	}
+	for i, entry := range entries {
+		if _, ok := l.unconfirmedHoleEntryIDs[entry.EntryID]; ok {
+			return entries[:i]
+		}
+	}
+	return entries
+}
+
+// IsDeleted returns true if the ledger is deleted.
+func (l *Ledger) IsDeleted() bool {
+	l.confirmationLock.Lock()
+	defer l.confirmationLock.Unlock()
+	return l.isDeleted
+}
+
+// pendingRead is an entry to read from the entry logger in ReadEntries.
+type pendingRead struct {
+	// position is the position of the entry in the result.
+	position int
+	value    *index.IndexValue
+}
+
+// readPendingEntries reads the pending entries from the entry logger into their positions in entries. The pending
+// entries which are adjacent in the entry logger are read with a single read.
+func (l *Ledger) readPendingEntries(entries []*pkg.LedgerEntry, pendingReads []*pendingRead) error {
+	for begin := 0; begin < len(pendingReads); {
+		sizes := []int{pendingReads[begin].value.Size}
+		nextOffset := pendingReads[begin].value.Offset + pendingReads[begin].value.Size
+		end := begin + 1
+		for ; end < len(pendingReads) && pendingReads[end].value.Offset == nextOffset; end++ {
+			sizes = append(sizes, pendingReads[end].value.Size)
+			nextOffset += pendingReads[end].value.Size
+		}
+
+		batch, err := l.entryLogger.ReadBatch(pendingReads[begin].value.Offset, sizes)
+		if err != nil {
+			return err
+		}
+		for i, entry := range batch {
+			entries[pendingReads[begin+i].position] = entry
+		}
+		begin = end
+	}
+	return nil
+}
+
+// Length returns the number of entries in the ledger, including the trimmed ones.
 func (l *Ledger) Length() (int, error) {
 	lastEntryID, indexValue, err := l.index.LastItem()
 	pkg.Logger.Debugf("Length: ledgerID=%d, lastEntryID=%d", l.ledgerID, lastEntryID)
@@ -136,13 +923,90 @@ func (l *Ledger) Length() (int, error) {
 		return -1, err
 	}
 	if indexValue == nil {
-		return 0, nil
+		return l.LowWaterMark(), nil
+	}
+	return max(lastEntryID+1, l.LowWaterMark()), nil
+}
+
+// Info is the metadata of a ledger. The entries are counted when they are indexed, so the entries not flushed yet are
+// not included.
+type Info struct {
+	CreationTime    time.Time
+	State           LedgerState
+	Properties      map[string]string
+	RetentionPolicy RetentionPolicy
+	// EntryCount is the number of the entries which are not trimmed.
+	EntryCount int
+	// ByteSize is the total payload size of the entries which are not trimmed.
+	ByteSize int
+	// LastAppendTime is the time at which the last entry is appended. It is zero if there is no entry.
+	LastAppendTime time.Time
+	// FirstEntryID is the first entry which can be read. It is -1 if there is no entry.
+	FirstEntryID int
+}
+
+// Info returns the metadata of the ledger. The counters of the entries are kept up to date by the flushes and the
+// trims, so only the first entry is looked up in the index.
+func (l *Ledger) Info() (*Info, error) {
+	info := &Info{
+		CreationTime:    l.creationTime,
+		State:           l.State(),
+		Properties:      l.properties,
+		RetentionPolicy: l.retentionPolicy,
+		FirstEntryID:    -1,
+	}
+	l.statsLock.Lock()
+	info.EntryCount = l.entryCount
+	info.ByteSize = l.payloadBytes
+	lastAppendTime := l.lastAppendTime
+	l.statsLock.Unlock()
+	if info.EntryCount > 0 && lastAppendTime > 0 {
+		info.LastAppendTime = time.UnixMilli(lastAppendTime)
 	}
-	return lastEntryID + 1, nil
+	err := l.index.Range(l.LowWaterMark(), math.MaxInt, func(entryID int, value *index.IndexValue) bool {
+		info.FirstEntryID = entryID
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// countIndexedEntries counts the entries in the index which are not trimmed. Expected to be called when the ledger is
+// opened, before the entries are flushed or trimmed.
+func (l *Ledger) countIndexedEntries() error {
+	l.statsLock.Lock()
+	defer l.statsLock.Unlock()
+	return l.index.Range(l.LowWaterMark(), math.MaxInt, func(entryID int, value *index.IndexValue) bool {
+		l.countEntry(value)
+		return true
+	})
 }
 
-// Close closes the ledger.
-func (l *Ledger) Close() error {
+// countEntry adds the indexed entry to the counters of the ledger.
+//
+// Expected to be called with statsLock held.
+func (l *Ledger) countEntry(value *index.IndexValue) {
+	l.entryCount++
+	l.payloadBytes += l.entryLogger.PayloadSize(value.Size)
+	l.lastAppendTime = max(l.lastAppendTime, value.AppendTime)
+}
+
+// Delete deletes the ledger with all its entries. porage.ErrLedgerNotFound is returned if the ledger is deleted
+// already.
+func (l *Ledger) Delete() error {
+	l.confirmationLock.Lock()
+	// The ledger may be deleted by the retention worker and DeleteLedger at the same time.
+	if l.isDeleted {
+		l.confirmationLock.Unlock()
+		return porage.ErrLedgerNotFound
+	}
+	l.isDeleted = true
+	l.wakeUpTailingReaders()
+	l.confirmationLock.Unlock()
+
+	unregisterLedger(l.ledgerID)
 	l.closeWorkers()
 	if err := l.removePersistenceInFileSystem(); err != nil {
 		return err
@@ -158,52 +1022,74 @@ func (l *Ledger) Close() error {
 	}
 	// If persistence file is removed, the recovery will not involve this ledger automatically.
 	journal.DeregisterLedger(l.ledgerID)
+	metrics.DeleteLedger(l.ledgerID)
 
 	return nil
 }
 
 // PrepareRecovery prepares the ledger for recovery. Return the lastEntryID persisted in the ledger. If there is no entry, return -1.
+// The trimmed entries are regarded as persisted.
+//
+// Entries with smaller IDs might be persisted after the last entry, so the entry logger is truncated after the
+// last valid entry instead of the last entry.
 func (l *Ledger) PrepareRecovery() (int, error) {
 	lastEntryID, lastIndexValue, err := l.index.LastItem()
 	pkg.Logger.Infof("PrepareRecovery: ledgerID=%d, lastEntryID=%d", l.ledgerID, lastEntryID)
 	if err != nil {
 		return -1, err
 	}
+	lowWaterMark := l.LowWaterMark()
+	if lowWaterMark > 0 {
+		// The trim is redone in case it was interrupted, after the entry logger is truncated.
+		defer l.signalTrim()
+	}
 	if lastIndexValue == nil {
-		return -1, nil
+		return lowWaterMark - 1, nil
 	}
 
-	l.nextEntryID = lastEntryID + 1
-	validSize := lastIndexValue.Offset + lastIndexValue.Size
-	if err := l.entryLogger.Truncate(int64(validSize)); err != nil {
+	l.nextEntryID = max(lastEntryID+1, lowWaterMark)
+	l.lastConfirmedEntryID = l.nextEntryID - 1
+	validSize, err := l.entryLogger.ValidSize(int64(lastIndexValue.Offset + lastIndexValue.Size))
+	if err != nil {
 		return -1, err
 	}
-	return lastEntryID, nil
+	if err := l.entryLogger.Truncate(validSize); err != nil {
+		return -1, err
+	}
+	return l.nextEntryID - 1, nil
 }
 
-// PutEntryOnRecovery puts the entry with payload into the ledger during recovery.
+// IsEntryPersisted returns true if the entry is persisted in the entry logger and the index. A trimmed entry is
+// regarded as persisted, so that it is not recovered from the journal.
 //
 // Expected to be called only in recovery.
-func (l *Ledger) PutEntryOnRecovery(payload []byte) error {
-	entryID := l.nextEntryID
-	l.nextEntryID++
-	pkg.Logger.Debugf("PutEntryOnRecovery: entryID=%d, payload=%s", entryID, string(payload))
-
-	// Write to memtable
-	pkg.Logger.Debugf("PutEntryOnRecovery: entryID=%d, payload=%s, put into memtable", entryID, string(payload))
-	ledgerEntry := &pkg.LedgerEntry{
-		EntryID: entryID,
-		Payload: payload,
+func (l *Ledger) IsEntryPersisted(entryID int) (bool, error) {
+	if entryID < l.LowWaterMark() {
+		return true, nil
 	}
-	l.memtable.Put(ledgerEntry)
-
-	// Async flush
-	pkg.Logger.Debugf("PutEntryOnRecovery: entryID=%d, payload=%s, put into message buffer", entryID, string(payload))
-	l.messageBuffer <- ledgerEntry
-
-	if l.memtable.MeetTrimThreshold() {
-		l.memtable.TrimUntil(int(l.lastFlushedEntryID.Load()))
+	indexValue, err := l.index.Get(entryID)
+	if err != nil {
+		return false, err
 	}
+	return indexValue != nil, nil
+}
+
+// PutEntryOnRecovery puts the entry with entryID and payload into the ledger during recovery. appendTime is the
+// append time recorded in the journal, so that the entry keeps it across the restarts.
+//
+// Expected to be called only in recovery.
+func (l *Ledger) PutEntryOnRecovery(entryID int, payload []byte, appendTime int64) error {
+	pkg.Logger.Debugf("PutEntryOnRecovery: entryID=%d, payload=%s", entryID, string(payload))
+	l.nextEntryIDLock.Lock()
+	l.acceptEntry(&pkg.LedgerEntry{
+		EntryID:    entryID,
+		Payload:    payload,
+		AppendTime: appendTime,
+	})
+	l.nextEntryIDLock.Unlock()
+	// The entry is read from the journal, so it is confirmed already.
+	l.confirmEntry(entryID)
+	l.trimMemtableIfNeeded()
 
 	pkg.Logger.Debugf("PutEntryOnRecovery: entryID=%d, payload=%s, done", entryID, string(payload))
 	return nil
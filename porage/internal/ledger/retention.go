@@ -0,0 +1,202 @@
+package ledger
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"porage/internal/index"
+	"porage/internal/pkg"
+	porage "porage/pkg"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const retentionWorkerName = "ledger_retention_worker"
+
+var (
+	// retainedLedgers is the set of the live ledgers with a retention policy.
+	retainedLedgers     = make(map[uint64]*Ledger)
+	retainedLedgersLock = &sync.Mutex{}
+	// enableRetention is set after the recovery, before which the entries of the ledgers are not complete.
+	enableRetention            = atomic.Bool{}
+	retentionWorkerDescription *pkg.WorkerDescription
+)
+
+// RetentionPolicy limits the entries kept in a ledger. The oldest entries beyond any of the limits are trimmed by the
+// retention worker, and a closed ledger is deleted once all its entries are beyond the limits. A zero limit means no
+// limit.
+type RetentionPolicy struct {
+	// MaxAge is how long an entry is kept after it is appended.
+	MaxAge time.Duration `json:"max_age,omitempty"`
+	// MaxBytes is the total payload size of the entries kept, in the same unit as Info.ByteSize.
+	MaxBytes int `json:"max_bytes,omitempty"`
+	// MaxEntries is the number of the entries kept.
+	MaxEntries int `json:"max_entries,omitempty"`
+}
+
+// IsZero reports whether the retention policy has no limit.
+func (p RetentionPolicy) IsZero() bool {
+	return p == RetentionPolicy{}
+}
+
+// validate returns porage.ErrInvalidRetentionPolicy if any of the limits is negative.
+func (p RetentionPolicy) validate() error {
+	if p.MaxAge < 0 || p.MaxBytes < 0 || p.MaxEntries < 0 {
+		return porage.ErrInvalidRetentionPolicy
+	}
+	return nil
+}
+
+// RetentionPolicy returns the retention policy of the ledger.
+func (l *Ledger) RetentionPolicy() RetentionPolicy {
+	return l.retentionPolicy
+}
+
+// EnableRetentionWorker enables the retention worker. Expected to be called after the recovery.
+func EnableRetentionWorker() {
+	enableRetention.Store(true)
+}
+
+// registerLedger lets the retention worker enforce the retention policy of the ledger if it has one.
+func registerLedger(l *Ledger) {
+	if l.retentionPolicy.IsZero() {
+		return
+	}
+	retainedLedgersLock.Lock()
+	defer retainedLedgersLock.Unlock()
+	retainedLedgers[l.ledgerID] = l
+}
+
+// unregisterLedger stops enforcing the retention policy of the ledger.
+func unregisterLedger(ledgerID uint64) {
+	retainedLedgersLock.Lock()
+	defer retainedLedgersLock.Unlock()
+	delete(retainedLedgers, ledgerID)
+}
+
+// retentionInterval returns the interval at which the retention policies are enforced.
+func retentionInterval() time.Duration {
+	if myConfig.Ledger.RetentionInterval == 0 {
+		return pkg.DefaultRetentionInterval * time.Second
+	}
+	return time.Duration(myConfig.Ledger.RetentionInterval) * time.Second
+}
+
+// startRetentionWorker registers and starts the retention worker.
+func startRetentionWorker() {
+	retentionWorkerDescription = pkg.NewWorkerDescription("Trim or delete the ledgers beyond their retention policies")
+	retentionWorkerDescription.SetPeriod(retentionInterval())
+	localWorkerControl.RegisterWorker(retentionWorkerName, retentionWorkerDescription)
+	go retention_worker(retentionWorkerDescription)
+}
+
+// retention_worker is the worker that enforces the retention policies of the ledgers periodically.
+func retention_worker(workerDescription *pkg.WorkerDescription) {
+	workerName := retentionWorkerName
+	for {
+		workerDescription.Heartbeat()
+		select {
+		case <-workerDescription.StopChannel():
+			pkg.Logger.Infof("%s: stopped", workerName)
+			localWorkerControl.UnregisterWorker(workerName)
+			workerDescription.StopResponseChannel() <- struct{}{}
+			return
+		case <-time.After(retentionInterval()):
+			if !enableRetention.Load() {
+				continue
+			}
+			workerDescription.AddProcessed(enforceRetentionPolicies(time.Now()))
+		}
+	}
+}
+
+// enforceRetentionPolicies enforces the retention policies of all the ledgers with one, and returns the number of the
+// ledgers trimmed or deleted.
+func enforceRetentionPolicies(now time.Time) int {
+	retainedLedgersLock.Lock()
+	ledgers := slices.Collect(maps.Values(retainedLedgers))
+	retainedLedgersLock.Unlock()
+
+	nEnforced := 0
+	for _, l := range ledgers {
+		enforced, err := l.enforceRetentionPolicy(now)
+		if err != nil {
+			pkg.Logger.Errorf("Ledger %d failed to enforce its retention policy: %v", l.ledgerID, err)
+			continue
+		}
+		if enforced {
+			nEnforced++
+		}
+	}
+	return nEnforced
+}
+
+// enforceRetentionPolicy trims the entries beyond the retention policy of the ledger. A closed ledger is deleted
+// instead once all its entries are beyond the policy. It returns true if the ledger is trimmed or deleted.
+func (l *Ledger) enforceRetentionPolicy(now time.Time) (bool, error) {
+	untilEntryID, err := l.findExpiredEntries(now)
+	if err != nil {
+		return false, err
+	}
+	if untilEntryID < l.LowWaterMark() {
+		return false, nil
+	}
+
+	l.nextEntryIDLock.Lock()
+	isAllExpired := l.state == LedgerStateClosed && untilEntryID == l.nextEntryID-1
+	l.nextEntryIDLock.Unlock()
+	if isAllExpired {
+		pkg.Logger.Infof("Ledger %d is deleted by its retention policy", l.ledgerID)
+		err := l.Delete()
+		if errors.Is(err, porage.ErrLedgerNotFound) {
+			// The ledger is deleted by DeleteLedger at the same time.
+			return false, nil
+		}
+		return err == nil, err
+	}
+	if err := l.Trim(untilEntryID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// findExpiredEntries returns the last entry ID beyond the retention policy at now, which is below the low-water mark
+// if there is none. The entries in the index are counted, so the entries not flushed yet are always kept.
+func (l *Ledger) findExpiredEntries(now time.Time) (int, error) {
+	policy := l.retentionPolicy
+	lowWaterMark := l.LowWaterMark()
+	nEntries := 0
+	nBytes := 0
+	if policy.MaxEntries > 0 || policy.MaxBytes > 0 {
+		err := l.index.Range(lowWaterMark, math.MaxInt, func(entryID int, value *index.IndexValue) bool {
+			nEntries++
+			nBytes += l.entryLogger.PayloadSize(value.Size)
+			return true
+		})
+		if err != nil {
+			return -1, err
+		}
+	}
+	expiryTime := now.Add(-policy.MaxAge).UnixMilli()
+
+	// The entries are expired from the oldest until one within all the limits.
+	untilEntryID := lowWaterMark - 1
+	err := l.index.Range(lowWaterMark, math.MaxInt, func(entryID int, value *index.IndexValue) bool {
+		isExpired := (policy.MaxEntries > 0 && nEntries > policy.MaxEntries) ||
+			(policy.MaxBytes > 0 && nBytes > policy.MaxBytes) ||
+			(policy.MaxAge > 0 && value.AppendTime < expiryTime)
+		if !isExpired {
+			return false
+		}
+		untilEntryID = entryID
+		nEntries--
+		nBytes -= l.entryLogger.PayloadSize(value.Size)
+		return true
+	})
+	if err != nil {
+		return -1, err
+	}
+	return untilEntryID, nil
+}
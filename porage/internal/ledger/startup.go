@@ -7,6 +7,8 @@ import (
 
 var myConfig *pkg.Config
 
+// Startup sets the configuration items for ledgers and starts the retention worker, which is enabled after the
+// recovery.
 func Startup(config *pkg.Config) {
 	myConfig = config
 
@@ -14,10 +16,22 @@ func Startup(config *pkg.Config) {
 	if err != nil {
 		pkg.Logger.Fatalf("Failed to create ledger storage directory: %v", err)
 	}
+
+	enableRetention.Store(false)
+	retainedLedgersLock.Lock()
+	clear(retainedLedgers)
+	retainedLedgersLock.Unlock()
+	startRetentionWorker()
 }
 
 // Stop stops the workers.
 func Stop() {
+	// The retention worker is stopped first, since it may delete a ledger, which stops the persistence worker of the
+	// ledger.
+	if retentionWorkerDescription != nil {
+		retentionWorkerDescription.Stop()
+		retentionWorkerDescription = nil
+	}
 	// Close the workers
 	for _, description := range localWorkerControl.GetWorkerDescriptions() {
 		description.Stop()
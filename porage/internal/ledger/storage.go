@@ -1,29 +1,116 @@
 package ledger
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path"
+	"time"
 )
 
-// persist persists the ledger to the file system.
+// persistedState is the content of the state file of a ledger.
+type persistedState struct {
+	State string `json:"state"`
+	// LowWaterMark is the first entry ID which is not trimmed.
+	LowWaterMark int `json:"low_water_mark,omitempty"`
+	// RetentionPolicy is the retention policy given when the ledger is created, which is nil if there is none.
+	RetentionPolicy *RetentionPolicy `json:"retention_policy,omitempty"`
+}
+
+// ledgerMetadataVersion is the version of the format of the ledger file. The ledger files created before the
+// metadata was added are empty, which is version 0.
+const ledgerMetadataVersion = 1
+
+// persistedMetadata is the content of the ledger file, which does not change after the ledger is created.
+type persistedMetadata struct {
+	Version int `json:"version"`
+	// CreationTime is the time (in Unix milliseconds) at which the ledger is created.
+	CreationTime int64             `json:"creation_time"`
+	Properties   map[string]string `json:"properties,omitempty"`
+}
+
+// persist persists the ledger to the file system. The ledger file holds the metadata of the ledger.
 func (l *Ledger) persistInFileSystem() error {
-	// Create a file for the ledger.
+	data, err := json.Marshal(&persistedMetadata{
+		Version:      ledgerMetadataVersion,
+		CreationTime: l.creationTime.UnixMilli(),
+		Properties:   l.properties,
+	})
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(l.makeLedgerFilePath(), data)
+}
+
+// loadMetadata loads the metadata of the ledger from the ledger file. An empty ledger file is version 0, whose
+// creation time is the modification time of the file.
+func (l *Ledger) loadMetadata() error {
 	filePath := l.makeLedgerFilePath()
-	file, err := os.Create(filePath)
+	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-	return file.Sync()
+	if len(data) == 0 {
+		fileInfo, err := os.Stat(filePath)
+		if err != nil {
+			return err
+		}
+		l.creationTime = fileInfo.ModTime()
+		return nil
+	}
+	var metadata persistedMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return err
+	}
+	if metadata.Version > ledgerMetadataVersion {
+		return fmt.Errorf("unsupported version %d of the metadata of ledger %d", metadata.Version, l.ledgerID)
+	}
+	l.creationTime = time.UnixMilli(metadata.CreationTime)
+	l.properties = metadata.Properties
+	return nil
 }
 
 // removePersistenceInFileSystem removes the persistence of the ledger in the file system.
 func (l *Ledger) removePersistenceInFileSystem() error {
+	if err := os.Remove(l.makeLedgerStateFilePath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
 	filePath := l.makeLedgerFilePath()
 	return os.Remove(filePath)
 }
 
+// persistState persists the state, the low-water mark and the retention policy of the ledger in the state file next
+// to the ledger file. The state file is replaced atomically.
+func (l *Ledger) persistState(state LedgerState, lowWaterMark int) error {
+	persisted := &persistedState{State: state.String(), LowWaterMark: lowWaterMark}
+	if !l.retentionPolicy.IsZero() {
+		persisted.RetentionPolicy = &l.retentionPolicy
+	}
+	data, err := json.Marshal(persisted)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(l.makeLedgerStateFilePath(), data)
+}
+
+// loadState loads the persisted state of the ledger. If there is no state file, the ledger is open, nothing is trimmed
+// and there is no retention policy.
+func (l *Ledger) loadState() (*persistedState, error) {
+	state := &persistedState{State: LedgerStateOpen.String()}
+	data, err := os.ReadFile(l.makeLedgerStateFilePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
 // getPersistentLedgerIDList returns the IDs of the ledgers that are persisted in the file system.
 func getPersistentLedgerIDList() ([]uint64, error) {
 	dirPath := myConfig.Ledger.StoragePath
@@ -36,7 +123,10 @@ func getPersistentLedgerIDList() ([]uint64, error) {
 
 	for _, file := range ledgerFileList {
 		filename := path.Base(file.Name())
-		ledgerID := parseLedgerIDFromFileName(filename)
+		ledgerID, ok := parseLedgerIDFromFileName(filename)
+		if !ok {
+			continue
+		}
 		ledgerIDList = append(ledgerIDList, ledgerID)
 	}
 	return ledgerIDList, err
@@ -47,9 +137,47 @@ func (l *Ledger) makeLedgerFilePath() string {
 	return path.Join(myConfig.Ledger.StoragePath, ledgerName)
 }
 
-// parseLedgerIDFromFileName parses the ledger ID from the file name(No slash).
-func parseLedgerIDFromFileName(filename string) uint64 {
+func (l *Ledger) makeLedgerStateFilePath() string {
+	return l.makeLedgerFilePath() + ".state"
+}
+
+// parseLedgerIDFromFileName parses the ledger ID from the file name(No slash). False is returned if the file is
+// not a ledger file, such as a state file.
+func parseLedgerIDFromFileName(filename string) (uint64, bool) {
 	var ledgerID uint64
-	fmt.Sscanf(filename, "ledger_%d", &ledgerID)
-	return ledgerID
+	if _, err := fmt.Sscanf(filename, "ledger_%d", &ledgerID); err != nil {
+		return 0, false
+	}
+	return ledgerID, filename == fmt.Sprintf("ledger_%d", ledgerID)
+}
+
+// writeFileAtomically writes the data to a temporary file and renames it to the file path, so that the file
+// either has the old content or the new content after a crash.
+func writeFileAtomically(filePath string, data []byte) error {
+	tempFilePath := filePath + ".tmp"
+	file, err := os.Create(tempFilePath)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tempFilePath, filePath); err != nil {
+		return err
+	}
+
+	dir, err := os.Open(path.Dir(filePath))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
 }
@@ -2,8 +2,11 @@ package ledger
 
 import (
 	"fmt"
+	"math"
+	entrylogger "porage/internal/entry_logger"
 	"porage/internal/index"
 	"porage/internal/journal"
+	"porage/internal/metrics"
 	"porage/internal/pkg"
 	"time"
 )
@@ -12,7 +15,15 @@ var (
 	localWorkerControl = pkg.NewLocalWorkerControl()
 )
 
+// startWorkers registers and starts the workers of the ledger. Workers are registered before they start, so
+// that they can be stopped right after startWorkers returns.
 func (l *Ledger) startWorkers() {
+	workerDescriptionString := fmt.Sprintf("Ledger %d persistence worker", l.ledgerID)
+	l.persistenceWorkerDescription = pkg.NewWorkerDescription(workerDescriptionString)
+	l.persistenceWorkerDescription.SetQueueLength(func() int {
+		return len(l.messageBuffer)
+	})
+	localWorkerControl.RegisterWorker(l.persistenceWorkerName(), l.persistenceWorkerDescription)
 	go l.persistenceWorker()
 }
 
@@ -21,21 +32,29 @@ func (l *Ledger) closeWorkers() {
 	l.persistenceWorkerDescription.Stop()
 }
 
+func (l *Ledger) persistenceWorkerName() string {
+	return fmt.Sprintf("ledger-%d-persistence-worker", l.ledgerID)
+}
+
 func (l *Ledger) persistenceWorker() {
-	workerName := fmt.Sprintf("ledger-%d-persistence-worker", l.ledgerID)
-	workerDescriptionString := fmt.Sprintf("Ledger %d persistence worker", l.ledgerID)
-	workerDescription := pkg.NewWorkerDescription(workerDescriptionString)
-	l.persistenceWorkerDescription = workerDescription
-	localWorkerControl.RegisterWorker(workerName, workerDescription)
+	workerName := l.persistenceWorkerName()
 
 	nWrittenEntry := uint64(0)
+	// trimmedUntil is the last entry ID whose trim is done by the worker.
+	trimmedUntil := -1
 	shouldFlushInterval := time.Duration(myConfig.EntryLogger.FlushInterval) * time.Second
 	shouldFlushIntervalTicker := time.NewTicker(shouldFlushInterval)
 	for {
+		l.persistenceWorkerDescription.Heartbeat()
 		pkg.Logger.Debugf("Ledger %d worker is running", l.ledgerID)
 		shouldFlush := false
 		select {
 		case entry := <-l.messageBuffer:
+			select {
+			case l.messageBufferConsumed <- struct{}{}:
+			default:
+			}
+			metrics.LedgerMessageBufferDepth.WithLabelValues(metrics.LedgerLabel(l.ledgerID)).Set(float64(len(l.messageBuffer)))
 			pkg.Logger.Debugf("Ledger %d worker is handling entry: %d", l.ledgerID, entry.EntryID)
 			// Write to entry logger
 			if err := l.entryLogger.Write(entry); err != nil {
@@ -43,6 +62,7 @@ func (l *Ledger) persistenceWorker() {
 				continue
 			}
 			nWrittenEntry += 1
+			l.persistenceWorkerDescription.AddProcessed(1)
 
 			// Flush to disk
 			if nWrittenEntry >= myConfig.EntryLogger.FlushRate {
@@ -53,6 +73,15 @@ func (l *Ledger) persistenceWorker() {
 			shouldFlushIntervalTicker.Stop()
 			shouldFlush = true
 			pkg.Logger.Debugf("Ledger %d worker: should flush triggerd by flush interval", l.ledgerID)
+		case request := <-l.entryLogger.RelocationRequests():
+			request.Done(l.relocate(request.Relocations))
+		case <-l.trimSignal:
+			newTrimmedUntil, err := l.trim(trimmedUntil)
+			if err != nil {
+				// The trim is retried on the next flush.
+				pkg.Logger.Errorf("Ledger %d failed to trim: %v", l.ledgerID, err)
+			}
+			trimmedUntil = newTrimmedUntil
 		case <-l.persistenceWorkerDescription.StopChannel():
 			pkg.Logger.Infof("%s: stopped", workerName)
 
@@ -75,6 +104,9 @@ func (l *Ledger) persistenceWorker() {
 			shouldFlush = false
 			nWrittenEntry = 0
 			shouldFlushIntervalTicker.Reset(shouldFlushInterval)
+			if trimmedUntil < l.LowWaterMark()-1 {
+				l.signalTrim()
+			}
 		}
 	}
 }
@@ -92,18 +124,139 @@ func (l *Ledger) flush() error {
 	}
 
 	// Write to index
+	trimmedEntryMetadata, err := l.indexFlushedEntries(flushedEntryMetadata)
+	if err != nil {
+		pkg.Logger.Errorf("Ledger %d failed to write index: %v", l.ledgerID, err)
+		return err
+	}
+	// The index is synced before the journal is allowed to trim the flushed entries.
+	if err := l.index.Sync(); err != nil {
+		pkg.Logger.Errorf("Ledger %d failed to sync index: %v", l.ledgerID, err)
+		return err
+	}
+	l.forgetFlushedHoleEntries(flushedEntryMetadata)
+	if len(trimmedEntryMetadata) > 0 {
+		// No space is punched out of the file here, since the recovery may scan it.
+		if err := l.entryLogger.Reclaim(trimmedEntryMetadata, 0); err != nil {
+			pkg.Logger.Errorf("Ledger %d failed to reclaim trimmed entries: %v", l.ledgerID, err)
+		}
+	}
+
+	journal.UpdateLedgerFlushTime(l.ledgerID)
+	return nil
+}
+
+// indexFlushedEntries writes the flushed entries to the index and counts them. The entries trimmed before they are
+// flushed are not indexed, and they are returned instead.
+func (l *Ledger) indexFlushedEntries(flushedEntryMetadata []*entrylogger.EntryMetadata) ([]*entrylogger.EntryMetadata, error) {
+	l.statsLock.Lock()
+	defer l.statsLock.Unlock()
+	lowWaterMark := l.LowWaterMark()
+	var trimmedEntryMetadata []*entrylogger.EntryMetadata
 	for _, entryMetadata := range flushedEntryMetadata {
+		if entryMetadata.EntryID < lowWaterMark {
+			trimmedEntryMetadata = append(trimmedEntryMetadata, entryMetadata)
+			l.lastFlushedEntryID.Store(int64(entryMetadata.EntryID))
+			continue
+		}
 		indexValue := index.IndexValue{
-			Offset: entryMetadata.Offset,
-			Size:   entryMetadata.Size,
+			Offset:     entryMetadata.Offset,
+			Size:       entryMetadata.Size,
+			AppendTime: entryMetadata.AppendTime,
 		}
 		if err := l.index.Put(entryMetadata.EntryID, &indexValue); err != nil {
-			pkg.Logger.Errorf("Ledger %d failed to write index: %v", l.ledgerID, err)
-			return err
+			return nil, err
 		}
+		l.countEntry(&indexValue)
 		l.lastFlushedEntryID.Store(int64(entryMetadata.EntryID))
 	}
+	return trimmedEntryMetadata, nil
+}
 
-	journal.UpdateLedgerFlushTime(l.ledgerID)
-	return nil
+// trim removes the entries after trimmedUntil and below the low-water mark from the index, and lets the entry logger
+// reclaim their space. The pending entries are flushed first, so that none of the trimmed entries is indexed
+// afterwards. It returns the last entry ID trimmed.
+func (l *Ledger) trim(trimmedUntil int) (int, error) {
+	untilEntryID := l.LowWaterMark() - 1
+	if untilEntryID <= trimmedUntil {
+		return trimmedUntil, nil
+	}
+	if err := l.flush(); err != nil {
+		return trimmedUntil, err
+	}
+
+	lastEntryID, lastIndexValue, err := l.index.LastItem()
+	if err != nil {
+		return trimmedUntil, err
+	}
+	var trimmedEntryMetadata []*entrylogger.EntryMetadata
+	err = l.index.Range(trimmedUntil+1, untilEntryID, func(entryID int, value *index.IndexValue) bool {
+		trimmedEntryMetadata = append(trimmedEntryMetadata, &entrylogger.EntryMetadata{
+			EntryID: entryID,
+			Offset:  value.Offset,
+			Size:    value.Size,
+		})
+		return true
+	})
+	if err != nil {
+		return trimmedUntil, err
+	}
+	if len(trimmedEntryMetadata) == 0 {
+		return untilEntryID, nil
+	}
+
+	if err := l.index.DeleteRange(trimmedUntil+1, untilEntryID); err != nil {
+		return trimmedUntil, err
+	}
+	if err := l.index.Sync(); err != nil {
+		return trimmedUntil, err
+	}
+	// The recovery scans the entry logger from the end of the last entry in the index, so the entries after it are
+	// kept.
+	validOffset := math.MaxInt
+	if lastIndexValue != nil && lastEntryID > untilEntryID {
+		validOffset = lastIndexValue.Offset + lastIndexValue.Size
+	}
+	if err := l.entryLogger.Reclaim(trimmedEntryMetadata, validOffset); err != nil {
+		return trimmedUntil, err
+	}
+	pkg.Logger.Infof("Ledger %d removed %d trimmed entries until entry %d", l.ledgerID, len(trimmedEntryMetadata), untilEntryID)
+	return untilEntryID, nil
+}
+
+// relocate points the index at the entries copied by the compaction of the entry logger. The entries whose index values
+// changed since they were copied are skipped. It runs in the persistence worker, so that a flush can not write the
+// index of an entry between the check and the update.
+func (l *Ledger) relocate(relocations []*entrylogger.Relocation) error {
+	for _, relocation := range relocations {
+		indexValue, err := l.index.Get(relocation.EntryID)
+		if err != nil {
+			return err
+		}
+		if indexValue == nil || indexValue.Offset != relocation.OldOffset {
+			relocation.Skipped = true
+			continue
+		}
+		newIndexValue := index.IndexValue{
+			Offset:     relocation.NewOffset,
+			Size:       relocation.Size,
+			AppendTime: indexValue.AppendTime,
+		}
+		if err := l.index.Put(relocation.EntryID, &newIndexValue); err != nil {
+			return err
+		}
+	}
+	return l.index.Sync()
+}
+
+// forgetFlushedHoleEntries stops tracking the flushed entries which filled holes.
+func (l *Ledger) forgetFlushedHoleEntries(flushedEntryMetadata []*entrylogger.EntryMetadata) {
+	l.unflushedHoleEntryIDsLock.Lock()
+	defer l.unflushedHoleEntryIDsLock.Unlock()
+	if len(l.unflushedHoleEntryIDs) == 0 {
+		return
+	}
+	for _, entryMetadata := range flushedEntryMetadata {
+		delete(l.unflushedHoleEntryIDs, entryMetadata.EntryID)
+	}
 }
@@ -32,12 +32,38 @@ func (m *MemTable) Get(entryID int) (*pkg.LedgerEntry, error) {
 	return entry, nil
 }
 
+// Range returns the entries with entryID in [fromEntryID, toEntryID] in ascending order of entryID. The scan stops
+// once the total payload size of the returned entries reaches maxBytes, unless maxBytes is not positive.
+func (m *MemTable) Range(fromEntryID int, toEntryID int, maxBytes int) []*pkg.LedgerEntry {
+	m.entryContainerLock.RLock()
+	defer m.entryContainerLock.RUnlock()
+
+	entries := make([]*pkg.LedgerEntry, 0)
+	nBytes := 0
+	for entryID := max(fromEntryID, m.minEntryInMem); entryID <= toEntryID; entryID++ {
+		if maxBytes > 0 && nBytes >= maxBytes {
+			break
+		}
+		entry, ok := m.entryContainer[entryID]
+		if !ok {
+			continue
+		}
+		entries = append(entries, entry)
+		nBytes += len(entry.Payload)
+	}
+	return entries
+}
+
 // Put puts the entry into the memtable.
 func (m *MemTable) Put(entry *pkg.LedgerEntry) {
 	m.entryContainerLock.Lock()
 	defer m.entryContainerLock.Unlock()
 
 	m.entryContainer[entry.EntryID] = entry
+	// An entry filling a hole below the trimmed entries must be trimmed later as well.
+	if entry.EntryID < m.minEntryInMem {
+		m.minEntryInMem = entry.EntryID
+	}
 }
 
 // TrimUntil trims the memtable until the entryID.
@@ -0,0 +1,32 @@
+// Package metrics collects the metrics of Porage and exposes them to Prometheus.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// DurationBuckets are the histogram buckets (in second) for the latencies of the disk and index operations.
+	DurationBuckets = []float64{0.0001, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+	// CountBuckets are the histogram buckets for the number of entries in a batch.
+	CountBuckets = []float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000, 10000, 50000}
+
+	// registry holds the metrics of Porage only, apart from the default registry of the process.
+	registry = prometheus.NewRegistry()
+	factory  = promauto.With(registry)
+)
+
+// Handler returns the HTTP handler which serves all the metrics.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveSince adds the seconds elapsed since start to the observer.
+func ObserveSince(observer prometheus.Observer, start time.Time) {
+	observer.Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// JournalGroupCommitDuration is the time from the first write of a group commit to its notification.
+	JournalGroupCommitDuration = factory.NewHistogram(prometheus.HistogramOpts{
+		Name:    "porage_journal_group_commit_duration_seconds",
+		Help:    "Time from the first write of a journal group commit to its notification.",
+		Buckets: DurationBuckets,
+	})
+	// JournalGroupCommitEntries is the number of entries in a group commit.
+	JournalGroupCommitEntries = factory.NewHistogram(prometheus.HistogramOpts{
+		Name:    "porage_journal_group_commit_entries",
+		Help:    "Number of entries in a journal group commit.",
+		Buckets: CountBuckets,
+	})
+	// JournalFsyncDuration is the duration of the fsync of a journal segment.
+	JournalFsyncDuration = factory.NewHistogram(prometheus.HistogramOpts{
+		Name:    "porage_journal_fsync_duration_seconds",
+		Help:    "Duration of the fsync of a journal segment.",
+		Buckets: DurationBuckets,
+	})
+	// JournalMessageBufferDepth is the number of write requests waiting in the message buffer of the journal.
+	JournalMessageBufferDepth = factory.NewGauge(prometheus.GaugeOpts{
+		Name: "porage_journal_message_buffer_depth",
+		Help: "Number of write requests waiting in the message buffer of the journal.",
+	})
+
+	// EntryLoggerFsyncDuration is the duration of the fsync of an entry logger file.
+	EntryLoggerFsyncDuration = factory.NewHistogram(prometheus.HistogramOpts{
+		Name:    "porage_entry_logger_fsync_duration_seconds",
+		Help:    "Duration of the fsync of an entry logger file.",
+		Buckets: DurationBuckets,
+	})
+
+	// LedgerMessageBufferDepth is the number of entries waiting in the message buffer of a ledger.
+	LedgerMessageBufferDepth = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "porage_ledger_message_buffer_depth",
+		Help: "Number of entries waiting in the message buffer of the ledger persistence worker.",
+	}, []string{"ledger"})
+	// LedgerAppendedEntries is the number of entries appended to a ledger.
+	LedgerAppendedEntries = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "porage_ledger_appended_entries_total",
+		Help: "Number of entries appended to the ledger.",
+	}, []string{"ledger"})
+	// LedgerReadEntries is the number of entries read from a ledger.
+	LedgerReadEntries = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "porage_ledger_read_entries_total",
+		Help: "Number of entries read from the ledger.",
+	}, []string{"ledger"})
+
+	// MemtableLookups is the number of entry lookups in the memtable by result, which is "hit" or "miss".
+	MemtableLookups = factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "porage_memtable_lookups_total",
+		Help: "Number of entry lookups in the memtable by result.",
+	}, []string{"result"})
+
+	// IndexOperationDuration is the duration of the operations on the Badger index by operation.
+	IndexOperationDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "porage_index_operation_duration_seconds",
+		Help:    "Duration of the operations on the Badger index.",
+		Buckets: DurationBuckets,
+	}, []string{"operation"})
+
+	// RecoveryDuration is the duration of the last recovery.
+	RecoveryDuration = factory.NewGauge(prometheus.GaugeOpts{
+		Name: "porage_recovery_duration_seconds",
+		Help: "Duration of the last recovery from the journal.",
+	})
+)
+
+// LedgerLabel returns the value of the ledger label of a ledger.
+func LedgerLabel(ledgerID uint64) string {
+	return strconv.FormatUint(ledgerID, 10)
+}
+
+// DeleteLedger removes the metrics of a deleted ledger.
+func DeleteLedger(ledgerID uint64) {
+	label := LedgerLabel(ledgerID)
+	LedgerMessageBufferDepth.DeleteLabelValues(label)
+	LedgerAppendedEntries.DeleteLabelValues(label)
+	LedgerReadEntries.DeleteLabelValues(label)
+}
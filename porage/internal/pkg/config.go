@@ -43,17 +43,80 @@ type EntryLoggerConfig struct {
 	// FlushInterval is the time interval (in second) at which the entry logger will flush the message buffer. After the time
 	// interval reaches the flush interval, the entry logger will flush the file system.
 	FlushInterval uint64 `toml:"flush_interval"`
+	// Mode is EntryLoggerModePerLedger or EntryLoggerModeInterleaved. An empty mode is EntryLoggerModePerLedger.
+	Mode string `toml:"mode"`
+	// MaxEntryLogSize is the size (in bytes) at which an interleaved entry log is rolled over. 0 means
+	// DefaultMaxEntryLogSize.
+	MaxEntryLogSize int64 `toml:"max_entry_log_size"`
+	// GCInterval is the time interval (in second) at which the garbage collection of the interleaved entry logs
+	// runs. 0 means DefaultEntryLogGCInterval.
+	GCInterval uint64 `toml:"gc_interval"`
+	// CompactionInterval is the time interval (in second) at which the compaction of the interleaved entry logs runs.
+	// 0 means DefaultCompactionInterval.
+	CompactionInterval uint64 `toml:"compaction_interval"`
+	// CompactionLiveRatio is the ratio of the live entries in a sealed interleaved entry log below which the entry log
+	// is compacted. 0 disables the compaction.
+	CompactionLiveRatio float64 `toml:"compaction_live_ratio"`
+	// CompactionRateLimit is the rate (in bytes per second) at which the compaction reads and writes the entry logs.
+	// 0 means no limit.
+	CompactionRateLimit int64 `toml:"compaction_rate_limit"`
 }
 
+// The modes of the entry logger.
+const (
+	// EntryLoggerModePerLedger writes the entries of each ledger to its own file. It is the default mode.
+	EntryLoggerModePerLedger = "per_ledger"
+	// EntryLoggerModeInterleaved writes the entries of all the ledgers to the shared entry logs.
+	EntryLoggerModeInterleaved = "interleaved"
+)
+
+const (
+	// DefaultMaxEntryLogSize is the default MaxEntryLogSize.
+	DefaultMaxEntryLogSize = 1 << 30
+	// DefaultEntryLogGCInterval is the default GCInterval.
+	DefaultEntryLogGCInterval = 60
+	// DefaultCompactionInterval is the default CompactionInterval.
+	DefaultCompactionInterval = 300
+)
+
+// The modes of the index.
+const (
+	// IndexModePerLedger keeps the index of each ledger in its own Badger database. It is the default mode.
+	IndexModePerLedger = "per_ledger"
+	// IndexModeShared keeps the indexes of all the ledgers in one Badger database keyed by (ledgerID, entryID).
+	IndexModeShared = "shared"
+)
+
+// The formats of the index.
+const (
+	// IndexFormatBadger keeps the index in Badger databases, laid out by the index mode. It is the default format.
+	IndexFormatBadger = "badger"
+	// IndexFormatFlat keeps the index of each ledger in a fixed-width file, where entryID*16 is the position of the
+	// offset and the size of the entryID, and in a side file of append times, where entryID*8 is the position of the
+	// append time of the entryID.
+	IndexFormatFlat = "flat"
+)
+
 type IndexFileConfig struct {
 	StoragePath  string `toml:"storage_path"`
 	MemtableSize int64  `toml:"memtable_size"`
+	// Format is IndexFormatBadger or IndexFormatFlat. An empty format is IndexFormatBadger.
+	Format string `toml:"format"`
+	// Mode is IndexModePerLedger or IndexModeShared, which applies to IndexFormatBadger. An empty mode is
+	// IndexModePerLedger.
+	Mode string `toml:"mode"`
 }
 
 type LedgerConfig struct {
 	StoragePath string `toml:"storage_path"`
+	// RetentionInterval is the time interval (in second) at which the retention policies of the ledgers are
+	// enforced. 0 means DefaultRetentionInterval.
+	RetentionInterval uint64 `toml:"retention_interval"`
 }
 
+// DefaultRetentionInterval is the default RetentionInterval.
+const DefaultRetentionInterval = 60
+
 type LogConfig struct {
 	Level     string `toml:"level"`
 	Output    string `toml:"output"`
@@ -61,11 +124,40 @@ type LogConfig struct {
 }
 
 type ServerConfig struct {
-	Host     string `toml:"host"`
-	Port     int    `toml:"port"`
-	GRPCPort int    `toml:"grpc_port"`
+	// Host is the host address for the HTTP server to listen on.
+	Host string `toml:"host"`
+	// Port is the port of the HTTP server, which serves the metrics at /metrics.
+	Port int `toml:"port"`
+	// GRPCPort is the port of the gRPC server.
+	GRPCPort int `toml:"grpc_port"`
+	// WorkerWatchdogWindow is the window (in seconds) within which a worker is expected to make progress. A worker
+	// making no progress within the window is flagged as stuck.
+	WorkerWatchdogWindow int `toml:"worker_watchdog_window"`
+}
+
+type RegistryConfig struct {
+	// EtcdEndpoints are the endpoints of the etcd cluster in which the Pora registers itself with its load. No
+	// endpoint disables the registration.
+	EtcdEndpoints []string `toml:"etcd_endpoints"`
+	// AdvertiseAddr is the gRPC address registered for the clients. An empty address is Server.Host:Server.GRPCPort.
+	AdvertiseAddr string `toml:"advertise_addr"`
+	// Capacity is the storage capacity (in bytes) registered. 0 means the size of the file system of the entry
+	// logger.
+	Capacity int64 `toml:"capacity"`
+	// LeaseTTL is the TTL (in second) of the lease of the registration. 0 means DefaultRegistryLeaseTTL.
+	LeaseTTL uint64 `toml:"lease_ttl"`
+	// HeartbeatInterval is the time interval (in second) at which the lease is renewed and the load is updated. 0
+	// means DefaultRegistryHeartbeatInterval.
+	HeartbeatInterval uint64 `toml:"heartbeat_interval"`
 }
 
+const (
+	// DefaultRegistryLeaseTTL is the default LeaseTTL.
+	DefaultRegistryLeaseTTL = 10
+	// DefaultRegistryHeartbeatInterval is the default HeartbeatInterval.
+	DefaultRegistryHeartbeatInterval = 3
+)
+
 type Config struct {
 	Ledger      LedgerConfig      `toml:"Ledger"`
 	Journal     JournalConfig     `toml:"Journal"`
@@ -74,6 +166,7 @@ type Config struct {
 	IndexFile   IndexFileConfig   `toml:"IndexFile"`
 	Log         LogConfig         `toml:"Log"`
 	Server      ServerConfig      `toml:"Server"`
+	Registry    RegistryConfig    `toml:"Registry"`
 }
 
 func ParseConfigFile(filePath string) (*Config, error) {
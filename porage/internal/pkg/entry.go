@@ -5,20 +5,24 @@ import (
 	"encoding/binary"
 )
 
+// JournalEntryPayload is the payload of a journal record, serialized as [LedgerID][EntryID][AppendTime][Payload].
 type JournalEntryPayload struct {
 	LedgerID uint64
 	EntryID  int
-	Payload  []byte
+	// AppendTime is the Unix time in milliseconds at which the entry is appended, which is restored by the recovery.
+	AppendTime int64
+	Payload    []byte
 }
 
 func noPayloadSize() int {
-	return 8 + 8
+	return 8 + 8 + 8
 }
 
 func (j *JournalEntryPayload) Serialize() []byte {
 	buf := new(bytes.Buffer)
 	binary.Write(buf, binary.BigEndian, j.LedgerID)
 	binary.Write(buf, binary.BigEndian, int64(j.EntryID))
+	binary.Write(buf, binary.BigEndian, j.AppendTime)
 	buf.Write(j.Payload)
 	return buf.Bytes()
 }
@@ -36,6 +40,7 @@ func DeserializeJournalEntry(data []byte) *JournalEntryPayload {
 	var entryID int64
 	binary.Read(buf, binary.BigEndian, &entryID)
 	entry.EntryID = int(entryID)
+	binary.Read(buf, binary.BigEndian, &entry.AppendTime)
 	entry.Payload = make([]byte, len(data)-noPayloadSize())
 	buf.Read(entry.Payload)
 	return entry
@@ -1,9 +1,48 @@
 package pkg
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrLedgerNotFound      = errors.New("ledger not found")
 	ErrLedgerAlreadyExists = errors.New("ledger already exists")
 	ErrBufferBusy          = errors.New("buffer busy")
+	ErrJournalCorrupted    = errors.New("journal corrupted")
+	ErrEntryCorrupted      = errors.New("entry corrupted")
 )
+
+// JournalCorruptionError is the error when a journal entry in the middle of a segment fails the checksum
+// verification. It wraps ErrJournalCorrupted.
+type JournalCorruptionError struct {
+	// SegmentPath is the path of the corrupted segment file.
+	SegmentPath string
+	// Offset is the offset of the corrupted journal entry in the segment file.
+	Offset int64
+}
+
+func (e *JournalCorruptionError) Error() string {
+	return fmt.Sprintf("%v: segment %s, offset %d", ErrJournalCorrupted, e.SegmentPath, e.Offset)
+}
+
+func (e *JournalCorruptionError) Unwrap() error {
+	return ErrJournalCorrupted
+}
+
+// EntryCorruptionError is the error when an entry read from the entry logger fails the checksum verification.
+// It wraps ErrEntryCorrupted.
+type EntryCorruptionError struct {
+	// LedgerID is the ID of the ledger the entry logger belongs to.
+	LedgerID uint64
+	// Offset is the offset of the corrupted entry in the entry logger file.
+	Offset int64
+}
+
+func (e *EntryCorruptionError) Error() string {
+	return fmt.Sprintf("%v: ledger %d, offset %d", ErrEntryCorrupted, e.LedgerID, e.Offset)
+}
+
+func (e *EntryCorruptionError) Unwrap() error {
+	return ErrEntryCorrupted
+}
@@ -0,0 +1,36 @@
+package pkg
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter paces the bytes of I/O to a rate in bytes per second. The bytes of each reservation are spread evenly
+// over time, so a burst is delayed instead of being let through.
+type RateLimiter struct {
+	bytesPerSecond int64
+	// next is the time at which the next reservation may start.
+	next time.Time
+	lock sync.Mutex
+}
+
+// NewRateLimiter creates a RateLimiter of bytesPerSecond. A rate which is not positive means no limit.
+func NewRateLimiter(bytesPerSecond int64) *RateLimiter {
+	return &RateLimiter{bytesPerSecond: bytesPerSecond}
+}
+
+// Reserve reserves n bytes and returns how long the caller should wait before doing the I/O.
+func (rl *RateLimiter) Reserve(n int) time.Duration {
+	if rl.bytesPerSecond <= 0 {
+		return 0
+	}
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	now := time.Now()
+	if rl.next.Before(now) {
+		rl.next = now
+	}
+	delay := rl.next.Sub(now)
+	rl.next = rl.next.Add(time.Duration(int64(n) * int64(time.Second) / rl.bytesPerSecond))
+	return delay
+}
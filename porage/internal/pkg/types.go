@@ -2,9 +2,14 @@ package pkg
 
 import (
 	"encoding/binary"
+	"maps"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	pb "porage/proto"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type NotificationChannel = chan Notification
@@ -16,17 +21,21 @@ type Notification struct {
 }
 
 // WriteRequest represents the message type from the network goroutine to ledger worker.
+// The entries of a request are written together and covered by the same notification.
 type WriteRequest struct {
-	Entry          *JournalEntryPayload
+	Entries        []*JournalEntryPayload
 	NotificationTx NotificationTx
 }
 
 type LedgerEntry struct {
 	EntryID int
 	Payload []byte
+	// AppendTime is the time (in Unix milliseconds) at which the entry is accepted by the ledger. It is kept in the
+	// index instead of being serialized with the entry.
+	AppendTime int64
 }
 
-// Serialize the ledger entry to a byte slice.
+// Serialize the ledger entry to a byte slice. The entry logger wraps it in a checksummed frame.
 //
 // Format: [EntryID][Payload]
 func (e *LedgerEntry) Serialize() []byte {
@@ -42,25 +51,100 @@ func (le *LedgerEntry) Deserialize(data []byte) {
 	le.Payload = data[8:]
 }
 
-// WorkerDescription is the description of a worker(goroutine).
+// WorkerDescription is the description of a worker(goroutine). Besides the description, it tracks the progress of the
+// worker, from which the watchdog tells whether the worker is stuck.
 type WorkerDescription struct {
 	Description         string
 	stopChannel         chan struct{}
 	stopResponseChannel chan struct{}
+
+	// lastHeartbeat is the Unix nano timestamp of the last time the worker handled its channels.
+	lastHeartbeat *atomic.Int64
+	// processed is the number of items processed by the worker. What an item is depends on the worker.
+	processed *atomic.Uint64
+	// queueLength returns the number of messages waiting in the channel of the worker. It is nil if the worker does
+	// not handle a message channel.
+	queueLength func() int
+	// period is the interval at which the worker wakes up by itself. It is 0 if the worker only wakes up on messages.
+	period time.Duration
 }
 
 func NewWorkerDescription(description string) *WorkerDescription {
+	lastHeartbeat := &atomic.Int64{}
+	lastHeartbeat.Store(time.Now().UnixNano())
 	return &WorkerDescription{
 		Description:         description,
 		stopChannel:         make(chan struct{}),
 		stopResponseChannel: make(chan struct{}),
+		lastHeartbeat:       lastHeartbeat,
+		processed:           &atomic.Uint64{},
+	}
+}
+
+// SetQueueLength sets the function which returns the number of messages waiting in the channel of the worker.
+//
+// Expected to be called before the worker starts.
+func (wd *WorkerDescription) SetQueueLength(queueLength func() int) {
+	wd.queueLength = queueLength
+}
+
+// SetPeriod sets the interval at which the worker wakes up by itself, e.g. on a timer.
+//
+// Expected to be called before the worker starts.
+func (wd *WorkerDescription) SetPeriod(period time.Duration) {
+	wd.period = period
+}
+
+// Heartbeat records that the worker is handling its channels. Expected to be called by the worker every time it
+// wakes up.
+func (wd *WorkerDescription) Heartbeat() {
+	wd.lastHeartbeat.Store(time.Now().UnixNano())
+}
+
+// LastHeartbeat returns the last time the worker handled its channels.
+func (wd *WorkerDescription) LastHeartbeat() time.Time {
+	return time.Unix(0, wd.lastHeartbeat.Load())
+}
+
+// AddProcessed adds n to the number of items processed by the worker.
+func (wd *WorkerDescription) AddProcessed(n int) {
+	wd.processed.Add(uint64(n))
+}
+
+// Processed returns the number of items processed by the worker.
+func (wd *WorkerDescription) Processed() uint64 {
+	return wd.processed.Load()
+}
+
+// QueueLength returns the number of messages waiting in the channel of the worker, which is 0 if the worker does
+// not handle a message channel.
+func (wd *WorkerDescription) QueueLength() int {
+	if wd.queueLength == nil {
+		return 0
+	}
+	return wd.queueLength()
+}
+
+// IsStuck reports whether the worker has made no progress within window. A worker makes no progress if it has
+// messages waiting in its channel but has not handled them within window, or if it wakes up periodically but has not
+// woken up within its period plus window. An idle worker is not stuck.
+func (wd *WorkerDescription) IsStuck(window time.Duration) bool {
+	sinceLastHeartbeat := time.Since(wd.LastHeartbeat())
+	if wd.QueueLength() > 0 && sinceLastHeartbeat > window {
+		return true
 	}
+	return wd.period > 0 && sinceLastHeartbeat > wd.period+window
 }
 
-// ToPb converts the WorkerDescription to a protobuf message.
-func (wd *WorkerDescription) ToPb() *pb.WorkerDescription {
+// ToPb converts the WorkerDescription to a protobuf message, in which the worker is flagged as stuck if it has made
+// no progress within window.
+func (wd *WorkerDescription) ToPb(window time.Duration) *pb.WorkerDescription {
 	return &pb.WorkerDescription{
-		Description: wd.Description,
+		Description:   wd.Description,
+		LastHeartbeat: timestamppb.New(wd.LastHeartbeat()),
+		Processed:     wd.Processed(),
+		QueueDepth:    int64(wd.QueueLength()),
+		Stuck:         wd.IsStuck(window),
 	}
 }
 
@@ -100,8 +184,10 @@ func (lwc *LocalWorkerControl) UnregisterWorker(name string) {
 	delete(lwc.workers, name)
 }
 
+// GetWorkerDescriptions returns a copy of the registered workers, which is safe to iterate while the workers are
+// registered or unregistered.
 func (lwc *LocalWorkerControl) GetWorkerDescriptions() map[string]*WorkerDescription {
 	lwc.rwlock.RLock()
 	defer lwc.rwlock.RUnlock()
-	return lwc.workers
+	return maps.Clone(lwc.workers)
 }
@@ -3,8 +3,11 @@ package pkg
 import (
 	"bytes"
 	"encoding/binary"
+	"hash/crc32"
 )
 
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
 // Int64ToBytes converts an int64 to a byte slice (Big Endian).
 func Int64ToBytes(num int64) ([]byte, error) {
 	buf := new(bytes.Buffer)
@@ -26,3 +29,12 @@ func BytesToInt64(b []byte) (int64, error) {
 	}
 	return num, nil
 }
+
+// Checksum computes the CRC32C(Castagnoli) checksum of the concatenation of the given byte slices.
+func Checksum(data ...[]byte) uint32 {
+	checksum := uint32(0)
+	for _, d := range data {
+		checksum = crc32.Update(checksum, crc32cTable, d)
+	}
+	return checksum
+}
@@ -1,9 +1,13 @@
 package recovery
 
 import (
+	"errors"
+	entrylogger "porage/internal/entry_logger"
 	"porage/internal/journal"
 	"porage/internal/ledger"
+	"porage/internal/metrics"
 	"porage/internal/pkg"
+	"time"
 )
 
 type recoverLedgerInfo struct {
@@ -11,11 +15,17 @@ type recoverLedgerInfo struct {
 	fromEntryID int
 }
 
-// Recover recovers the ledgers from the journal.
+// Recover recovers the ledgers from the journal. Journal entries are verified by their checksums. A torn entry
+// at the tail of a segment is discarded, and a corrupted entry in the middle of a segment makes the recovery
+// fail with a *pkg.JournalCorruptionError.
 //
 // Expected to be called after the local storage is initialized.
 func Recover() ([]*ledger.Ledger, error) {
 	pkg.Logger.Infof("Recovering ledgers from journal.")
+	startTime := time.Now()
+	defer func() {
+		metrics.RecoveryDuration.Set(time.Since(startTime).Seconds())
+	}()
 	persistentLedgerIDList, err := ledger.GetPersistentLedgerIDList()
 	if err != nil {
 		return nil, err
@@ -24,7 +34,7 @@ func Recover() ([]*ledger.Ledger, error) {
 	ledgers := make([]*ledger.Ledger, 0)
 	recoveryLedgerInfoMap := make(map[uint64]*recoverLedgerInfo)
 	for _, ledgerID := range persistentLedgerIDList {
-		thisLedger, err := ledger.NewLedger(ledgerID)
+		thisLedger, err := ledger.OpenLedger(ledgerID)
 		if err != nil {
 			return nil, err
 		}
@@ -46,6 +56,10 @@ func Recover() ([]*ledger.Ledger, error) {
 	for {
 		journalEntries, nextSegmentIdx, err := journal.ReadJournal(segmentIndex)
 		if err != nil {
+			var corruptionErr *pkg.JournalCorruptionError
+			if errors.As(err, &corruptionErr) {
+				pkg.Logger.Errorf("Journal segment %s is corrupted at offset %d.", corruptionErr.SegmentPath, corruptionErr.Offset)
+			}
 			return nil, err
 		}
 
@@ -57,14 +71,22 @@ func Recover() ([]*ledger.Ledger, error) {
 				continue
 			}
 
+			// Entries below fromEntryID might be written after it by PutEntryWithID, so they are not
+			// necessarily persisted.
 			if journalEntry.Entry.EntryID < recoverLedgerInfo.fromEntryID {
-				nSkippedJournalEntries++
-				continue
+				persisted, err := recoverLedgerInfo.ledger.IsEntryPersisted(journalEntry.Entry.EntryID)
+				if err != nil {
+					return nil, err
+				}
+				if persisted {
+					nSkippedJournalEntries++
+					continue
+				}
 			}
 
 			pkg.Logger.Debugf("Recovering entry %d in ledger %d with payload len %v.", journalEntry.Entry.EntryID, journalEntry.Entry.LedgerID, len(journalEntry.Entry.Payload))
 
-			if err := recoverLedgerInfo.ledger.PutEntryOnRecovery(journalEntry.Entry.Payload); err != nil {
+			if err := recoverLedgerInfo.ledger.PutEntryOnRecovery(journalEntry.Entry.EntryID, journalEntry.Entry.Payload, journalEntry.Entry.AppendTime); err != nil {
 				return nil, err
 			}
 			nTotalRecovered += 1
@@ -77,6 +99,8 @@ func Recover() ([]*ledger.Ledger, error) {
 	}
 
 	journal.EnableTrimWorker()
+	entrylogger.EnableGC()
+	ledger.EnableRetentionWorker()
 	pkg.Logger.Infof("Recovered %d entries from %d journal entries. Skipped %d journal entries.", nTotalRecovered, nJournalEntries, nSkippedJournalEntries)
 	return ledgers, nil
 }
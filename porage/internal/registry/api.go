@@ -0,0 +1,172 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"porage/internal/ledger"
+	"porage/internal/pkg"
+	"porage/pkg/metadata"
+	"slices"
+	"time"
+)
+
+var (
+	// leaseID is the lease of the registration, which is metadata.NoLease while the Pora is not registered.
+	leaseID = metadata.NoLease
+	// lastAppendedBytes and lastLoadTime are ledger.AppendedBytes and the time at the last update of the load, from
+	// which the write throughput is measured.
+	lastAppendedBytes uint64
+	lastLoadTime      time.Time
+)
+
+// leaseTTL returns the TTL of the lease of the registration.
+func leaseTTL() time.Duration {
+	if myConfig.Registry.LeaseTTL == 0 {
+		return pkg.DefaultRegistryLeaseTTL * time.Second
+	}
+	return time.Duration(myConfig.Registry.LeaseTTL) * time.Second
+}
+
+// heartbeatInterval returns the interval at which the lease is renewed and the load is updated.
+func heartbeatInterval() time.Duration {
+	if myConfig.Registry.HeartbeatInterval == 0 {
+		return pkg.DefaultRegistryHeartbeatInterval * time.Second
+	}
+	return time.Duration(myConfig.Registry.HeartbeatInterval) * time.Second
+}
+
+// register grants a new lease and puts the registration of the Pora under it.
+func register() error {
+	ctx, cancel := context.WithTimeout(context.Background(), heartbeatInterval())
+	defer cancel()
+	newLeaseID, err := store.GrantLease(ctx, leaseTTL())
+	if err != nil {
+		return err
+	}
+	leaseID = newLeaseID
+	return putPoraInfo(ctx)
+}
+
+// renew renews the lease and updates the load in the registration. The Pora is registered again if it is not
+// registered, or if its lease has expired, e.g. while the MetadataStore was unreachable.
+func renew() error {
+	if leaseID == metadata.NoLease {
+		return register()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), heartbeatInterval())
+	defer cancel()
+	if err := store.KeepAlive(ctx, leaseID); err != nil {
+		if errors.Is(err, metadata.ErrLeaseNotFound) {
+			leaseID = metadata.NoLease
+			return register()
+		}
+		return err
+	}
+	return putPoraInfo(ctx)
+}
+
+// deregister revokes the lease, which deletes the registration.
+func deregister() error {
+	if leaseID == metadata.NoLease {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), heartbeatInterval())
+	defer cancel()
+	err := store.RevokeLease(ctx, leaseID)
+	leaseID = metadata.NoLease
+	return err
+}
+
+// putPoraInfo puts the registration of the Pora with its current load.
+func putPoraInfo(ctx context.Context) error {
+	poraInfo, err := collectLoad()
+	if err != nil {
+		return err
+	}
+	value, err := json.Marshal(poraInfo)
+	if err != nil {
+		return err
+	}
+	_, err = store.Put(ctx, metadata.PoraKey(poraAddr), value, leaseID)
+	return err
+}
+
+// resetLoad starts measuring the write throughput from now.
+func resetLoad() {
+	lastAppendedBytes = ledger.AppendedBytes()
+	lastLoadTime = time.Now()
+}
+
+// collectLoad returns the registration of the Pora with its current load. The write throughput is measured since the
+// last call.
+func collectLoad() (*metadata.PoraInfo, error) {
+	now := time.Now()
+	appendedBytes := ledger.AppendedBytes()
+	writeThroughput := 0.0
+	if elapsed := now.Sub(lastLoadTime).Seconds(); elapsed > 0 {
+		writeThroughput = float64(appendedBytes-lastAppendedBytes) / elapsed
+	}
+	lastAppendedBytes = appendedBytes
+	lastLoadTime = now
+
+	diskUsage, err := diskUsage()
+	if err != nil {
+		return nil, err
+	}
+	capacity := myConfig.Registry.Capacity
+	if capacity == 0 {
+		if capacity, err = diskCapacity(myConfig.EntryLogger.StoragePath); err != nil {
+			return nil, err
+		}
+	}
+	return &metadata.PoraInfo{
+		Addr:            poraAddr,
+		Capacity:        capacity,
+		DiskUsage:       diskUsage,
+		LedgerCount:     ledgerCount(),
+		WriteThroughput: writeThroughput,
+		UpdateTime:      now,
+	}, nil
+}
+
+// diskUsage returns the total size of the files in the storage directories of the Pora.
+func diskUsage() (int64, error) {
+	storagePaths := []string{
+		myConfig.Journal.StoragePath,
+		myConfig.EntryLogger.StoragePath,
+		myConfig.IndexFile.StoragePath,
+		myConfig.Ledger.StoragePath,
+	}
+	slices.Sort(storagePaths)
+	size := int64(0)
+	for _, storagePath := range slices.Compact(storagePaths) {
+		err := filepath.WalkDir(storagePath, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				// The files removed during the walk, e.g. by the journal trim, are skipped.
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			info, err := entry.Info()
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return size, nil
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package registry
+
+// diskCapacity returns 0 where the size of the file system is not known, so the capacity should be configured.
+func diskCapacity(path string) (int64, error) {
+	return 0, nil
+}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package registry
+
+import "syscall"
+
+// diskCapacity returns the size of the file system of path.
+func diskCapacity(path string) (int64, error) {
+	stat := syscall.Statfs_t{}
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return int64(stat.Blocks) * int64(stat.Bsize), nil
+}
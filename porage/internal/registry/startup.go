@@ -0,0 +1,41 @@
+package registry
+
+import (
+	"porage/internal/pkg"
+	"porage/pkg/metadata"
+)
+
+var (
+	myConfig *pkg.Config
+	// store is the MetadataStore in which the Pora is registered.
+	store metadata.MetadataStore
+	// poraAddr is the address under which the Pora is registered.
+	poraAddr string
+	// ledgerCount returns the number of the ledgers on the Pora.
+	ledgerCount func() int
+)
+
+// Startup registers the Pora at addr in metadataStore and starts the heartbeat worker, which renews the lease of the
+// registration with the load of the Pora. A failed registration is logged and retried by the heartbeat worker, so
+// the Pora serves even if the MetadataStore is unavailable.
+func Startup(config *pkg.Config, metadataStore metadata.MetadataStore, addr string, countLedgers func() int) {
+	myConfig = config
+	store = metadataStore
+	poraAddr = addr
+	ledgerCount = countLedgers
+	resetLoad()
+
+	if err := register(); err != nil {
+		pkg.Logger.Errorf("Failed to register Pora %s: %v", poraAddr, err)
+	}
+	startWorkers()
+}
+
+// Stop stops the heartbeat worker and deregisters the Pora.
+func Stop() {
+	closeWorkers()
+	if err := deregister(); err != nil {
+		pkg.Logger.Errorf("Failed to deregister Pora %s: %v", poraAddr, err)
+	}
+	pkg.Logger.Infof("Registry stopped")
+}
@@ -0,0 +1,52 @@
+package registry
+
+import (
+	"porage/internal/pkg"
+	"time"
+)
+
+const heartbeatWorkerName = "registry_heartbeat_worker"
+
+var (
+	localWorkerControl = pkg.NewLocalWorkerControl()
+)
+
+// startWorkers registers and starts the heartbeat worker.
+func startWorkers() {
+	heartbeatWorkerDescription := pkg.NewWorkerDescription("Renew the registration of the Pora with its load")
+	heartbeatWorkerDescription.SetPeriod(heartbeatInterval())
+	localWorkerControl.RegisterWorker(heartbeatWorkerName, heartbeatWorkerDescription)
+	go heartbeat_worker(heartbeatWorkerDescription)
+}
+
+func closeWorkers() {
+	for _, description := range localWorkerControl.GetWorkerDescriptions() {
+		description.Stop()
+	}
+}
+
+// GetWorkerDescriptions returns the descriptions of the workers of the registry.
+func GetWorkerDescriptions() map[string]*pkg.WorkerDescription {
+	return localWorkerControl.GetWorkerDescriptions()
+}
+
+// heartbeat_worker is the worker that renews the registration of the Pora with its load periodically.
+func heartbeat_worker(workerDescription *pkg.WorkerDescription) {
+	workerName := heartbeatWorkerName
+	for {
+		workerDescription.Heartbeat()
+		select {
+		case <-workerDescription.StopChannel():
+			pkg.Logger.Infof("%s: stopped", workerName)
+			localWorkerControl.UnregisterWorker(workerName)
+			workerDescription.StopResponseChannel() <- struct{}{}
+			return
+		case <-time.After(heartbeatInterval()):
+			if err := renew(); err != nil {
+				pkg.Logger.Errorf("%s: failed to renew the registration of Pora %s: %v", workerName, poraAddr, err)
+				continue
+			}
+			workerDescription.AddProcessed(1)
+		}
+	}
+}
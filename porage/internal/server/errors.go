@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"porage/internal/pkg"
+	porage "porage/pkg"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorStatus is the gRPC status code and the ErrorInfo reason of an error returned by the handlers.
+type errorStatus struct {
+	err    error
+	code   codes.Code
+	reason string
+}
+
+// errorStatuses maps the errors in porage/pkg and internal/pkg to gRPC statuses. The first matching entry is used.
+var errorStatuses = []errorStatus{
+	{porage.ErrLedgerExisted, codes.AlreadyExists, porage.ReasonLedgerExisted},
+	{porage.ErrLedgerNotFound, codes.NotFound, porage.ReasonLedgerNotFound},
+	{porage.ErrEntryNotFound, codes.NotFound, porage.ReasonEntryNotFound},
+	{porage.ErrEntryTrimmed, codes.OutOfRange, porage.ReasonEntryTrimmed},
+	{porage.ErrLedgerFenced, codes.FailedPrecondition, porage.ReasonLedgerFenced},
+	{porage.ErrLedgerClosed, codes.FailedPrecondition, porage.ReasonLedgerClosed},
+	{porage.ErrEntryIDConflict, codes.AlreadyExists, porage.ReasonEntryIDConflict},
+	{porage.ErrInvalidEntryID, codes.InvalidArgument, porage.ReasonInvalidEntryID},
+	{porage.ErrInvalidRetentionPolicy, codes.InvalidArgument, porage.ReasonInvalidRetentionPolicy},
+	{porage.ErrServerBusy, codes.ResourceExhausted, porage.ReasonServerBusy},
+	{porage.ErrDataCorrupted, codes.DataLoss, porage.ReasonDataCorrupted},
+	{porage.ErrServerNotReady, codes.Unavailable, porage.ReasonServerNotReady},
+	{pkg.ErrLedgerNotFound, codes.NotFound, porage.ReasonLedgerNotFound},
+	{pkg.ErrLedgerAlreadyExists, codes.AlreadyExists, porage.ReasonLedgerExisted},
+	{pkg.ErrBufferBusy, codes.ResourceExhausted, porage.ReasonServerBusy},
+	{pkg.ErrJournalCorrupted, codes.DataLoss, porage.ReasonDataCorrupted},
+	{pkg.ErrEntryCorrupted, codes.DataLoss, porage.ReasonDataCorrupted},
+}
+
+// toStatusError converts an error returned by a handler into a gRPC status error. The known errors carry an
+// ErrorInfo detail with their reason, which the client converts back into the exported errors.
+func toStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var durabilityUnknownErr *porage.DurabilityUnknownError
+	if errors.As(err, &durabilityUnknownErr) {
+		return toDurabilityUnknownStatusError(durabilityUnknownErr)
+	}
+	for _, errorStatus := range errorStatuses {
+		if !errors.Is(err, errorStatus.err) {
+			continue
+		}
+		rpcStatus, detailErr := status.New(errorStatus.code, err.Error()).WithDetails(&errdetails.ErrorInfo{
+			Reason: errorStatus.reason,
+			Domain: porage.ErrorDomain,
+		})
+		if detailErr != nil {
+			pkg.Logger.Errorf("Failed to attach error details to %v: %v", err, detailErr)
+			return status.Error(errorStatus.code, err.Error())
+		}
+		return rpcStatus.Err()
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// toDurabilityUnknownStatusError converts a *porage.DurabilityUnknownError into a status error with the code of its
+// context error, whose ErrorInfo detail carries the range of the entries whose durability is unknown.
+func toDurabilityUnknownStatusError(err *porage.DurabilityUnknownError) error {
+	code := codes.Canceled
+	if errors.Is(err.Err, context.DeadlineExceeded) {
+		code = codes.DeadlineExceeded
+	}
+	rpcStatus, detailErr := status.New(code, err.Error()).WithDetails(&errdetails.ErrorInfo{
+		Reason: porage.ReasonDurabilityUnknown,
+		Domain: porage.ErrorDomain,
+		Metadata: map[string]string{
+			porage.MetadataFirstEntryID: strconv.Itoa(err.FirstEntryID),
+			porage.MetadataLastEntryID:  strconv.Itoa(err.LastEntryID),
+		},
+	})
+	if detailErr != nil {
+		pkg.Logger.Errorf("Failed to attach error details to %v: %v", err, detailErr)
+		return status.Error(code, err.Error())
+	}
+	return rpcStatus.Err()
+}
+
+// unaryErrorInterceptor converts the errors returned by the unary handlers into gRPC status errors.
+func unaryErrorInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	response, err := handler(ctx, req)
+	return response, toStatusError(err)
+}
+
+// streamErrorInterceptor converts the errors returned by the streaming handlers into gRPC status errors.
+func streamErrorInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return toStatusError(handler(srv, stream))
+}
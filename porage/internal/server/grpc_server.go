@@ -2,15 +2,28 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"porage/internal/control"
+	"porage/internal/ledger"
 	"porage/internal/pkg"
 	porage "porage/pkg"
 	pb "porage/proto"
+	"time"
 
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// readEntriesBatchBytes is the max payload size of the entries in a response of ReadEntries.
+	readEntriesBatchBytes = 1 << 20
+	// appendEntriesStreamWindow is the max number of batches accepted in an AppendEntriesStream but not responded.
+	appendEntriesStreamWindow = 128
 )
 
 type PorageRPCServiceServer struct {
@@ -22,8 +35,16 @@ type PorageRPCServiceServer struct {
 }
 
 // newPorageRPCServiceServer creates a new PorageServiceServer.
-func newPorageRPCServiceServer(ledgerControl *control.LedgerControl, workerControl *control.WorkerControl) *PorageRPCServiceServer {
-	grpcServer := grpc.NewServer()
+//
+// The calls are rejected with porage.ErrServerNotReady until the healthChecker reports the Pora is ready, except those
+// to the grpc.health.v1 service.
+func newPorageRPCServiceServer(ledgerControl *control.LedgerControl, workerControl *control.WorkerControl,
+	healthChecker *healthChecker) *PorageRPCServiceServer {
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryErrorInterceptor, healthChecker.unaryReadinessInterceptor),
+		grpc.ChainStreamInterceptor(streamErrorInterceptor, healthChecker.streamReadinessInterceptor),
+	)
+	healthpb.RegisterHealthServer(grpcServer, healthChecker.grpcHealthServer)
 	return &PorageRPCServiceServer{
 		ledgerControl: ledgerControl,
 		grpcServer:    grpcServer,
@@ -31,6 +52,26 @@ func newPorageRPCServiceServer(ledgerControl *control.LedgerControl, workerContr
 	}
 }
 
+// NewLocalGRPCServer creates a gRPC server with the handlers of a Pora, serving the ledgers of ledgerControl from the
+// local storage of the process, which must be started beforehand.
+//
+// Unlike a PoraServer, which owns the local storage, several of them can run in a process if their ledgers have
+// distinct IDs, so that the clients replicating ledgers on several Poras are tested against the real handlers. The
+// interceptors run inside the one converting the errors into gRPC statuses.
+func NewLocalGRPCServer(ledgerControl *control.LedgerControl, unaryInterceptor grpc.UnaryServerInterceptor,
+	streamInterceptor grpc.StreamServerInterceptor) *grpc.Server {
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryErrorInterceptor, unaryInterceptor),
+		grpc.ChainStreamInterceptor(streamErrorInterceptor, streamInterceptor),
+	)
+	pb.RegisterPorageServiceServer(grpcServer, &PorageRPCServiceServer{
+		ledgerControl: ledgerControl,
+		grpcServer:    grpcServer,
+		workerControl: control.NewWorkerControl(0),
+	})
+	return grpcServer
+}
+
 // stop stops the gRPC server gracefully.
 func (s *PorageRPCServiceServer) stop() {
 	s.grpcServer.GracefulStop()
@@ -50,9 +91,14 @@ func (s *PorageRPCServiceServer) start(config *pkg.Config) error {
 	return s.grpcServer.Serve(listener)
 }
 
-// CreateLedger creates a new ledger. Error would be returned if the ledger already exists.
+// CreateLedger creates a new ledger with its retention policy and properties. Error would be returned if the ledger
+// already exists.
 func (s *PorageRPCServiceServer) CreateLedger(ctx context.Context, in *pb.CreateLedgerRequest) (*emptypb.Empty, error) {
-	err := s.ledgerControl.CreateLedger(in.LedgerId)
+	options := ledger.CreateOptions{
+		RetentionPolicy: fromPbRetentionPolicy(in.RetentionPolicy),
+		Properties:      in.Properties,
+	}
+	err := s.ledgerControl.CreateLedger(in.LedgerId, options)
 	return nil, err
 }
 
@@ -62,7 +108,7 @@ func (s *PorageRPCServiceServer) AppendEntryOnLedger(ctx context.Context, in *pb
 	if ledger == nil {
 		return nil, porage.ErrLedgerNotFound
 	}
-	entryID, err := ledger.PutEntry(in.Payload)
+	entryID, err := ledger.PutEntry(ctx, in.Payload)
 	if err != nil {
 		return nil, err
 	}
@@ -72,6 +118,83 @@ func (s *PorageRPCServiceServer) AppendEntryOnLedger(ctx context.Context, in *pb
 	return response, nil
 }
 
+// AppendEntriesOnLedger puts a batch of entries on a ledger.
+func (s *PorageRPCServiceServer) AppendEntriesOnLedger(ctx context.Context, in *pb.AppendEntriesOnLedgerRequest) (*pb.AppendEntriesOnLedgerResponse, error) {
+	ledger := s.ledgerControl.GetLedger(in.LedgerId)
+	if ledger == nil {
+		return nil, porage.ErrLedgerNotFound
+	}
+	entryIDs, err := ledger.PutEntries(ctx, in.Payloads)
+	if err != nil {
+		return nil, err
+	}
+	return toPbAppendEntriesResponse(entryIDs), nil
+}
+
+// AppendEntriesStream puts the batches of entries received in the stream on ledgers. The batches are accepted as
+// they are received and responded in the same order once they are committed by the journal.
+func (s *PorageRPCServiceServer) AppendEntriesStream(stream pb.PorageService_AppendEntriesStreamServer) error {
+	pendingAppends := make(chan *ledger.PendingAppend, appendEntriesStreamWindow)
+	receiveResult := make(chan error, 1)
+	go func() {
+		receiveResult <- s.acceptAppendEntriesRequests(stream, pendingAppends)
+	}()
+
+	for pendingAppend := range pendingAppends {
+		err := pendingAppend.Wait(stream.Context())
+		if err == nil {
+			err = stream.Send(toPbAppendEntriesResponse(pendingAppend.EntryIDs()))
+		}
+		if err != nil {
+			// Wait for the batches accepted before the receiving stops with the stream, so that the ledgers do not
+			// regard them as in-flight forever.
+			go func() {
+				for pendingAppend := range pendingAppends {
+					pendingAppend.Wait(context.Background())
+				}
+			}()
+			return err
+		}
+	}
+	return <-receiveResult
+}
+
+// acceptAppendEntriesRequests receives the requests of an AppendEntriesStream and accepts them on the ledgers until
+// the client closes the stream or an error occurs. pendingAppends is closed on return.
+func (s *PorageRPCServiceServer) acceptAppendEntriesRequests(stream pb.PorageService_AppendEntriesStreamServer, pendingAppends chan<- *ledger.PendingAppend) error {
+	defer close(pendingAppends)
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		ledger := s.ledgerControl.GetLedger(in.LedgerId)
+		if ledger == nil {
+			return porage.ErrLedgerNotFound
+		}
+		pendingAppend, err := ledger.AppendEntries(stream.Context(), in.Payloads)
+		if err != nil {
+			return err
+		}
+		pendingAppends <- pendingAppend
+	}
+}
+
+// AppendEntryWithID puts an entry with the entry ID assigned by the client on a ledger.
+func (s *PorageRPCServiceServer) AppendEntryWithID(ctx context.Context, in *pb.AppendEntryWithIDRequest) (*emptypb.Empty, error) {
+	ledger := s.ledgerControl.GetLedger(in.LedgerId)
+	if ledger == nil {
+		return nil, porage.ErrLedgerNotFound
+	}
+	if err := ledger.PutEntryWithID(ctx, int(in.EntryId), in.Payload); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
 // GetEntryFromLedger gets an entry from a ledger.
 func (s *PorageRPCServiceServer) GetEntryFromLedger(ctx context.Context, in *pb.GetEntryFromLedgerRequest) (*pb.GetEntryFromLedgerResponse, error) {
 	ledger := s.ledgerControl.GetLedger(in.LedgerId)
@@ -91,7 +214,94 @@ func (s *PorageRPCServiceServer) GetEntryFromLedger(ctx context.Context, in *pb.
 	return response, nil
 }
 
-// DeleteLedger closes a ledger.
+// ReadEntries streams the entries in a range of a ledger in batches.
+func (s *PorageRPCServiceServer) ReadEntries(in *pb.ReadEntriesRequest, stream pb.PorageService_ReadEntriesServer) error {
+	ledger := s.ledgerControl.GetLedger(in.LedgerId)
+	if ledger == nil {
+		return porage.ErrLedgerNotFound
+	}
+
+	fromEntryID := int(in.FromEntryId)
+	remainingBytes := int(in.MaxBytes)
+	isFirstBatch := true
+	for {
+		batchBytes := readEntriesBatchBytes
+		if in.MaxBytes > 0 {
+			batchBytes = min(batchBytes, remainingBytes)
+		}
+		entries, err := ledger.ReadEntries(fromEntryID, int(in.ToEntryId), batchBytes)
+		if err != nil {
+			return err
+		}
+		// Only the first entry of the stream is allowed to exceed max_bytes.
+		if len(entries) > 0 && !isFirstBatch && in.MaxBytes > 0 && len(entries[0].Payload) > remainingBytes {
+			entries = entries[:0]
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+
+		response := &pb.ReadEntriesResponse{
+			Entries: toPbLedgerEntries(entries),
+		}
+		for _, entry := range entries {
+			remainingBytes -= len(entry.Payload)
+		}
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+		if in.MaxBytes > 0 && remainingBytes <= 0 {
+			return nil
+		}
+		fromEntryID = entries[len(entries)-1].EntryID + 1
+		isFirstBatch = false
+	}
+}
+
+// TailLedger streams the entries of a ledger once they are confirmed by the journal, until the ledger is closed or
+// deleted.
+func (s *PorageRPCServiceServer) TailLedger(in *pb.TailLedgerRequest, stream pb.PorageService_TailLedgerServer) error {
+	ledger := s.ledgerControl.GetLedger(in.LedgerId)
+	if ledger == nil {
+		return porage.ErrLedgerNotFound
+	}
+
+	fromEntryID := int(in.FromEntryId)
+	for {
+		entries, err := ledger.WaitForEntries(stream.Context(), fromEntryID, readEntriesBatchBytes)
+		if errors.Is(err, porage.ErrLedgerClosed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		response := &pb.TailLedgerResponse{
+			Entries: toPbLedgerEntries(entries),
+		}
+		if err := stream.Send(response); err != nil {
+			return err
+		}
+		fromEntryID = entries[len(entries)-1].EntryID + 1
+	}
+}
+
+// CloseLedger seals a ledger and returns the last entry ID persisted in the ledger.
+func (s *PorageRPCServiceServer) CloseLedger(ctx context.Context, in *pb.CloseLedgerRequest) (*pb.CloseLedgerResponse, error) {
+	ledger := s.ledgerControl.GetLedger(in.LedgerId)
+	if ledger == nil {
+		return nil, porage.ErrLedgerNotFound
+	}
+	lastEntryID, err := ledger.Close()
+	if err != nil {
+		return nil, err
+	}
+	response := &pb.CloseLedgerResponse{
+		LastEntryId: int64(lastEntryID),
+	}
+	return response, nil
+}
+
+// DeleteLedger deletes a ledger with all its entries.
 func (s *PorageRPCServiceServer) DeleteLedger(ctx context.Context, in *pb.DeleteLedgerRequest) (*emptypb.Empty, error) {
 	err := s.ledgerControl.RemoveLedger(in.LedgerId)
 	return nil, err
@@ -122,14 +332,105 @@ func (s *PorageRPCServiceServer) ListLedgers(ctx context.Context, in *emptypb.Em
 	return response, nil
 }
 
-// ListWorkers returns a list of workers in the ledger control.
+// ListWorkers returns the workers with their progress. The workers making no progress within the watchdog window are
+// flagged as stuck.
 func (s *PorageRPCServiceServer) ListWorkers(ctx context.Context, in *emptypb.Empty) (*pb.ListWorkersResponse, error) {
 	workerDescriptions := s.workerControl.List()
 	response := pb.ListWorkersResponse{
 		Workers: make(map[string]*pb.WorkerDescription),
 	}
 	for workerName, description := range workerDescriptions {
-		response.Workers[workerName] = description.ToPb()
+		response.Workers[workerName] = description.ToPb(s.workerControl.WatchdogWindow())
 	}
 	return &response, nil
 }
+
+// FenceLedger fences a ledger and returns the last entry ID persisted in the ledger.
+func (s *PorageRPCServiceServer) FenceLedger(ctx context.Context, in *pb.FenceLedgerRequest) (*pb.FenceLedgerResponse, error) {
+	ledger := s.ledgerControl.GetLedger(in.LedgerId)
+	if ledger == nil {
+		return nil, porage.ErrLedgerNotFound
+	}
+	lastEntryID, err := ledger.Fence()
+	if err != nil {
+		return nil, err
+	}
+	response := &pb.FenceLedgerResponse{
+		LastEntryId: int64(lastEntryID),
+	}
+	return response, nil
+}
+
+// TrimLedger trims the entries of a ledger up to the entry ID.
+func (s *PorageRPCServiceServer) TrimLedger(ctx context.Context, in *pb.TrimLedgerRequest) (*emptypb.Empty, error) {
+	ledger := s.ledgerControl.GetLedger(in.LedgerId)
+	if ledger == nil {
+		return nil, porage.ErrLedgerNotFound
+	}
+	if err := ledger.Trim(int(in.UntilEntryId)); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func toPbAppendEntriesResponse(entryIDs []int) *pb.AppendEntriesOnLedgerResponse {
+	response := &pb.AppendEntriesOnLedgerResponse{
+		EntryIds: make([]int64, 0, len(entryIDs)),
+	}
+	for _, entryID := range entryIDs {
+		response.EntryIds = append(response.EntryIds, int64(entryID))
+	}
+	return response
+}
+
+// GetLedgerInfo returns the metadata of a ledger.
+func (s *PorageRPCServiceServer) GetLedgerInfo(ctx context.Context, in *pb.GetLedgerInfoRequest) (*pb.GetLedgerInfoResponse, error) {
+	ledger := s.ledgerControl.GetLedger(in.LedgerId)
+	if ledger == nil {
+		return nil, porage.ErrLedgerNotFound
+	}
+	info, err := ledger.Info()
+	if err != nil {
+		return nil, err
+	}
+	response := &pb.GetLedgerInfoResponse{
+		CreationTime:    timestamppb.New(info.CreationTime),
+		State:           pb.LedgerState(info.State),
+		Properties:      info.Properties,
+		RetentionPolicy: toPbRetentionPolicy(info.RetentionPolicy),
+		EntryCount:      int64(info.EntryCount),
+		ByteSize:        int64(info.ByteSize),
+		FirstEntryId:    int64(info.FirstEntryID),
+	}
+	if !info.LastAppendTime.IsZero() {
+		response.LastAppendTime = timestamppb.New(info.LastAppendTime)
+	}
+	return response, nil
+}
+
+func toPbRetentionPolicy(retentionPolicy ledger.RetentionPolicy) *pb.RetentionPolicy {
+	return &pb.RetentionPolicy{
+		MaxAgeSeconds: int64(retentionPolicy.MaxAge / time.Second),
+		MaxBytes:      int64(retentionPolicy.MaxBytes),
+		MaxEntries:    int64(retentionPolicy.MaxEntries),
+	}
+}
+
+func fromPbRetentionPolicy(retentionPolicy *pb.RetentionPolicy) ledger.RetentionPolicy {
+	return ledger.RetentionPolicy{
+		MaxAge:     time.Duration(retentionPolicy.GetMaxAgeSeconds()) * time.Second,
+		MaxBytes:   int(retentionPolicy.GetMaxBytes()),
+		MaxEntries: int(retentionPolicy.GetMaxEntries()),
+	}
+}
+
+func toPbLedgerEntries(entries []*pkg.LedgerEntry) []*pb.LedgerEntry {
+	pbEntries := make([]*pb.LedgerEntry, 0, len(entries))
+	for _, entry := range entries {
+		pbEntries = append(pbEntries, &pb.LedgerEntry{
+			EntryId: int64(entry.EntryID),
+			Payload: entry.Payload,
+		})
+	}
+	return pbEntries
+}
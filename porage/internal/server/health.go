@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"porage/internal/control"
+	"porage/internal/journal"
+	porage "porage/pkg"
+	pb "porage/proto"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthCheckInterval is the interval at which the serving status of the gRPC health service is updated.
+const healthCheckInterval = time.Second
+
+// healthChecker tells whether the Pora is ready to serve and whether it is alive, and reports them through the
+// grpc.health.v1 service and the /healthz and /readyz HTTP endpoints.
+//
+// The Pora is ready after the recovery finishes and the journal trim worker is enabled. It is alive as long as the
+// watchdog of the WorkerControl flags no worker as stuck.
+type healthChecker struct {
+	isRecovered      *atomic.Bool
+	workerControl    *control.WorkerControl
+	grpcHealthServer *health.Server
+	stopChannel      chan struct{}
+}
+
+func newHealthChecker(workerControl *control.WorkerControl) *healthChecker {
+	hc := &healthChecker{
+		isRecovered:      &atomic.Bool{},
+		workerControl:    workerControl,
+		grpcHealthServer: health.NewServer(),
+		stopChannel:      make(chan struct{}),
+	}
+	hc.updateServingStatus()
+	return hc
+}
+
+// markRecovered records that the recovery has finished.
+func (hc *healthChecker) markRecovered() {
+	hc.isRecovered.Store(true)
+	hc.updateServingStatus()
+}
+
+// isReady reports whether the Pora is ready to serve requests.
+func (hc *healthChecker) isReady() bool {
+	return hc.isRecovered.Load() && journal.IsTrimWorkerEnabled()
+}
+
+// stuckWorkers returns the names of the stuck workers. The Pora is alive if there is none.
+func (hc *healthChecker) stuckWorkers() []string {
+	return hc.workerControl.StuckWorkers()
+}
+
+// watch updates the serving status of the gRPC health service periodically until stop is called.
+//
+// This function blocks.
+func (hc *healthChecker) watch() {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			hc.updateServingStatus()
+		case <-hc.stopChannel:
+			return
+		}
+	}
+}
+
+// updateServingStatus sets the serving status of the Pora and its PorageService in the gRPC health service.
+func (hc *healthChecker) updateServingStatus() {
+	servingStatus := healthpb.HealthCheckResponse_NOT_SERVING
+	if hc.isReady() && len(hc.stuckWorkers()) == 0 {
+		servingStatus = healthpb.HealthCheckResponse_SERVING
+	}
+	hc.grpcHealthServer.SetServingStatus("", servingStatus)
+	hc.grpcHealthServer.SetServingStatus(pb.PorageService_ServiceDesc.ServiceName, servingStatus)
+}
+
+// stop stops watching and sets the serving status to NOT_SERVING permanently.
+func (hc *healthChecker) stop() {
+	close(hc.stopChannel)
+	hc.grpcHealthServer.Shutdown()
+}
+
+// handleHealthz serves the liveness probe.
+func (hc *healthChecker) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if stuckWorkers := hc.stuckWorkers(); len(stuckWorkers) > 0 {
+		http.Error(w, fmt.Sprintf("stuck workers: %s", strings.Join(stuckWorkers, ", ")), http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+// handleReadyz serves the readiness probe.
+func (hc *healthChecker) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if !hc.isReady() {
+		http.Error(w, "recovering", http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+// isHealthMethod reports whether the gRPC method belongs to the health service, which is served before the Pora is
+// ready.
+func isHealthMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+healthpb.Health_ServiceDesc.ServiceName+"/")
+}
+
+// unaryReadinessInterceptor rejects the unary calls with porage.ErrServerNotReady until the Pora is ready.
+func (hc *healthChecker) unaryReadinessInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !isHealthMethod(info.FullMethod) && !hc.isReady() {
+		return nil, porage.ErrServerNotReady
+	}
+	return handler(ctx, req)
+}
+
+// streamReadinessInterceptor rejects the streaming calls with porage.ErrServerNotReady until the Pora is ready.
+func (hc *healthChecker) streamReadinessInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !isHealthMethod(info.FullMethod) && !hc.isReady() {
+		return porage.ErrServerNotReady
+	}
+	return handler(srv, stream)
+}
+
+// registerHandlers registers the liveness and readiness probes on the HTTP mux.
+func (hc *healthChecker) registerHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", hc.handleHealthz)
+	mux.HandleFunc("/readyz", hc.handleReadyz)
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"porage/internal/metrics"
+	"porage/internal/pkg"
+	"time"
+)
+
+// httpShutdownTimeout is the time to wait for the in-flight HTTP requests when the HTTP server stops.
+const httpShutdownTimeout = 5 * time.Second
+
+// PorageHTTPServer serves the HTTP endpoints of Pora on Server.Host:Server.Port.
+type PorageHTTPServer struct {
+	httpServer *http.Server
+}
+
+// newPorageHTTPServer creates a new PorageHTTPServer which serves /metrics and the liveness and readiness probes
+// /healthz and /readyz.
+func newPorageHTTPServer(config *pkg.Config, healthChecker *healthChecker) *PorageHTTPServer {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	healthChecker.registerHandlers(mux)
+	return &PorageHTTPServer{
+		httpServer: &http.Server{
+			Addr:    fmt.Sprintf("%s:%d", config.Server.Host, config.Server.Port),
+			Handler: mux,
+		},
+	}
+}
+
+// start starts the HTTP server.
+//
+// This function blocks.
+func (s *PorageHTTPServer) start() error {
+	err := s.httpServer.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// stop stops the HTTP server gracefully.
+func (s *PorageHTTPServer) stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	defer cancel()
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		pkg.Logger.Errorf("Failed to stop HTTP server: %v", err)
+	}
+	pkg.Logger.Infof("HTTP server stopped")
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"fmt"
 	"porage/internal/control"
 	entrylogger "porage/internal/entry_logger"
 	"porage/internal/index"
@@ -9,8 +10,14 @@ import (
 	"porage/internal/memtable"
 	"porage/internal/pkg"
 	"porage/internal/recovery"
+	"porage/internal/registry"
+	"porage/pkg/metadata"
+	"time"
 )
 
+// metadataStoreDialTimeout bounds the wait for the connection to the etcd cluster of the registry.
+const metadataStoreDialTimeout = 5 * time.Second
+
 // PoraServer is the main server struct.
 type PoraServer struct {
 	config *pkg.Config
@@ -18,6 +25,13 @@ type PoraServer struct {
 	workerControl *control.WorkerControl
 	ledgerControl *control.LedgerControl
 	grpcServer    *PorageRPCServiceServer
+	httpServer    *PorageHTTPServer
+	healthChecker *healthChecker
+
+	// metadataStore is the MetadataStore in which the Pora registers itself, which is nil if the registration is
+	// disabled. isMetadataStoreOwned is set if it is opened from the config, so that it is closed by Stop.
+	metadataStore        metadata.MetadataStore
+	isMetadataStoreOwned bool
 }
 
 // NewPorageServer creates a new PoraServer with the given config.
@@ -27,23 +41,50 @@ func NewPorageServer(config *pkg.Config) *PoraServer {
 	}
 }
 
+// NewPorageServerWithMetadataStore creates a new PoraServer registering itself in metadataStore instead of the etcd
+// cluster in the config. The metadataStore is not closed by Stop.
+func NewPorageServerWithMetadataStore(config *pkg.Config, metadataStore metadata.MetadataStore) *PoraServer {
+	return &PoraServer{
+		config:        config,
+		metadataStore: metadataStore,
+	}
+}
+
 // Start starts the PoraServer.
 //
 // This function blocks.
+// The gRPC server accepts the connections before the recovery, but rejects the calls other than the health checks with
+// porage.ErrServerNotReady until the recovery finishes. The Pora is registered once it is ready.
 func (ps *PoraServer) Start() {
 	ps.startLog()
 	ps.startWorkerControl()
+	ps.startHealthChecker()
+	ps.startHTTPServer()
 	ps.startLedgerControl()
 	ps.startLocalStorage()
+	rpcServerResult := ps.startRPCServer()
 	ps.startRecovery()
-	ps.startRPCServer()
+	ps.healthChecker.markRecovered()
+	ps.startRegistry()
+	pkg.Logger.Infof("Starting Porage server: ready to serve")
+	if err := <-rpcServerResult; err != nil {
+		pkg.Logger.Errorf("Failed to run gRPC server: %v", err)
+	}
 }
 
 // Stop stops the PoraServer gracefully.
+//
+// The Pora is deregistered first, so that the clients stop choosing it.
 func (ps *PoraServer) Stop() {
+	ps.stopRegistry()
+	ps.healthChecker.stop()
+	ps.workerControl.StopWatch()
 	ps.grpcServer.stop()
+	ps.httpServer.stop()
 	journal.Stop()
 	ledger.Stop()
+	entrylogger.Stop()
+	index.Stop()
 	pkg.Logger.Infof("Porage server stopped")
 }
 
@@ -57,11 +98,30 @@ func (ps *PoraServer) startLog() {
 	pkg.Logger.Infof("Starting Porage server: accomplished log configuration")
 }
 
+// startHTTPServer starts the HTTP server in the background, so that the metrics are served during the recovery.
+func (ps *PoraServer) startHTTPServer() {
+	ps.httpServer = newPorageHTTPServer(ps.config, ps.healthChecker)
+	go func() {
+		if err := ps.httpServer.start(); err != nil {
+			pkg.Logger.Errorf("Failed to run HTTP server: %v", err)
+		}
+	}()
+	pkg.Logger.Infof("Starting Porage server: accomplished HTTP server initialization")
+}
+
 func (ps *PoraServer) startWorkerControl() {
-	ps.workerControl = control.NewWorkerControl()
+	ps.workerControl = control.NewWorkerControl(time.Duration(ps.config.Server.WorkerWatchdogWindow) * time.Second)
+	go ps.workerControl.Watch()
 	pkg.Logger.Infof("Starting Porage server: accomplished worker control initialization")
 }
 
+// startHealthChecker starts watching the health of the Pora, which is not ready until the recovery finishes.
+func (ps *PoraServer) startHealthChecker() {
+	ps.healthChecker = newHealthChecker(ps.workerControl)
+	go ps.healthChecker.watch()
+	pkg.Logger.Infof("Starting Porage server: accomplished health checker initialization")
+}
+
 // startLedgerControl starts the ledger control.
 func (ps *PoraServer) startLedgerControl() {
 	ps.ledgerControl = control.NewLedgerControl()
@@ -90,14 +150,59 @@ func (ps *PoraServer) startRecovery() {
 	pkg.Logger.Infof("Starting Porage server: accomplished recovery process")
 }
 
-// startRPCServer starts the RPC.
-//
-// This function blocks.
-func (ps *PoraServer) startRPCServer() {
-	ps.grpcServer = newPorageRPCServiceServer(ps.ledgerControl, ps.workerControl)
+// startRPCServer starts the RPC server in the background. The returned channel receives the result of the server
+// after it stops.
+func (ps *PoraServer) startRPCServer() <-chan error {
+	ps.grpcServer = newPorageRPCServiceServer(ps.ledgerControl, ps.workerControl, ps.healthChecker)
+	result := make(chan error, 1)
+	go func() {
+		result <- ps.grpcServer.start(ps.config)
+	}()
 	pkg.Logger.Infof("Starting Porage server: accomplished RPC server initialization")
-	err := ps.grpcServer.start(ps.config)
-	if err != nil {
-		pkg.Logger.Errorf("Failed to run gRPC server: %v", err)
+	return result
+}
+
+// startRegistry registers the Pora in the MetadataStore and starts renewing the registration with its load. The
+// registration is disabled if there is neither a MetadataStore nor an etcd endpoint in the config.
+func (ps *PoraServer) startRegistry() {
+	if ps.metadataStore == nil {
+		if len(ps.config.Registry.EtcdEndpoints) == 0 {
+			pkg.Logger.Infof("Starting Porage server: registration disabled")
+			return
+		}
+		etcdStore, err := metadata.NewEtcdStore(ps.config.Registry.EtcdEndpoints, metadataStoreDialTimeout)
+		if err != nil {
+			pkg.Logger.Errorf("Failed to connect to etcd, registration disabled: %v", err)
+			return
+		}
+		ps.metadataStore = etcdStore
+		ps.isMetadataStoreOwned = true
+	}
+	registry.Startup(ps.config, ps.metadataStore, ps.advertiseAddr(), func() int {
+		return len(ps.ledgerControl.ListLedgers())
+	})
+	pkg.Logger.Infof("Starting Porage server: accomplished registration as %s", ps.advertiseAddr())
+}
+
+// stopRegistry deregisters the Pora, and closes the MetadataStore if it is opened from the config.
+func (ps *PoraServer) stopRegistry() {
+	if ps.metadataStore == nil {
+		return
+	}
+	registry.Stop()
+	if ps.isMetadataStoreOwned {
+		if err := ps.metadataStore.Close(); err != nil {
+			pkg.Logger.Errorf("Failed to close metadata store: %v", err)
+		}
+		ps.metadataStore = nil
+		ps.isMetadataStoreOwned = false
+	}
+}
+
+// advertiseAddr returns the gRPC address of the Pora registered for the clients.
+func (ps *PoraServer) advertiseAddr() string {
+	if ps.config.Registry.AdvertiseAddr != "" {
+		return ps.config.Registry.AdvertiseAddr
 	}
+	return fmt.Sprintf("%s:%d", ps.config.Server.Host, ps.config.Server.GRPCPort)
 }
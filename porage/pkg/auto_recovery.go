@@ -0,0 +1,158 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+)
+
+// AutoRecovery re-replicates the entries of the DistributedLedgers which lost a Pora for good. The lost Pora is
+// replaced in the ensembles by another Pora of the pool, onto which the entries it held are copied from the other
+// Poras of their write sets with their entry IDs.
+type AutoRecovery struct {
+	pool *PoraPool
+}
+
+// NewAutoRecovery creates an AutoRecovery choosing the replacements from pool.
+func NewAutoRecovery(pool *PoraPool) *AutoRecovery {
+	return &AutoRecovery{pool: pool}
+}
+
+// RecoverLedger replaces the lost Pora at lostAddr in every segment of the ledger described by metadata, and copies
+// onto the replacement the entries of the segment which have fewer than Qw copies in their write sets. The updated
+// metadata is returned with the number of copied entries. The metadata is returned unchanged if no ensemble of the
+// ledger includes lostAddr.
+//
+// The ledger must not be written during the recovery, as the writer of an open ledger replaces its failed Poras by
+// itself. The entries of the last segment of an open ledger are recovered up to the last one found on the other
+// Poras, and the replacements of a closed ledger are closed once the entries are copied. ErrEntryLost is returned if
+// no Pora holds an entry of a closed segment, and ErrNotEnoughReplicas if no Pora of the pool can replace the lost
+// one. The recovery can be run again after a failure, since the copies are idempotent.
+func (r *AutoRecovery) RecoverLedger(ctx context.Context, metadata DistributedLedgerMetadata, lostAddr string) (DistributedLedgerMetadata, int, error) {
+	recovered := metadata.clone()
+	nCopied := 0
+	replacements := make([]string, 0)
+	for i := range recovered.Segments {
+		index := slices.Index(recovered.Segments[i].Poras, lostAddr)
+		if index < 0 {
+			continue
+		}
+		replacement, err := r.chooseReplacement(ctx, recovered.LedgerID, recovered.Segments[i].Poras, lostAddr)
+		if err != nil {
+			return metadata, nCopied, err
+		}
+		recovered.Segments[i].Poras[index] = replacement
+		if !slices.Contains(replacements, replacement) {
+			replacements = append(replacements, replacement)
+		}
+
+		// The last entry ID of the last segment of an open ledger is unknown, so it is read to the end.
+		toEntryID := math.MaxInt32
+		if i+1 < len(recovered.Segments) {
+			toEntryID = recovered.Segments[i+1].FirstEntryID - 1
+		} else if recovered.IsClosed {
+			toEntryID = recovered.LastEntryID
+		}
+		nSegmentCopied, err := r.recoverSegment(ctx, &recovered, i, replacement, toEntryID)
+		nCopied += nSegmentCopied
+		if err != nil {
+			return metadata, nCopied, err
+		}
+	}
+	if recovered.IsClosed {
+		for _, replacement := range replacements {
+			client, err := r.pool.Client(replacement)
+			if err == nil {
+				_, err = client.CloseLedger(ctx, recovered.LedgerID)
+			}
+			if err != nil {
+				return metadata, nCopied, err
+			}
+		}
+	}
+	return recovered, nCopied, nil
+}
+
+// chooseReplacement creates the ledger on the first Pora of the pool which is neither lostAddr nor in ensemble, and
+// returns its address. A Pora on which the ledger already exists is chosen as well, as it may be the replacement of
+// an earlier segment or of an interrupted recovery.
+func (r *AutoRecovery) chooseReplacement(ctx context.Context, ledgerID uint64, ensemble []string, lostAddr string) (string, error) {
+	var err error
+	for _, addr := range r.pool.Addrs() {
+		if addr == lostAddr || slices.Contains(ensemble, addr) {
+			continue
+		}
+		var client *PorageClient
+		if client, err = r.pool.Client(addr); err != nil {
+			continue
+		}
+		if err = client.CreateLedger(ctx, ledgerID); err == nil || errors.Is(err, ErrLedgerExisted) {
+			return addr, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no Pora to replace %s in ledger %d: %v", ErrNotEnoughReplicas, lostAddr, ledgerID, err)
+}
+
+// recoverSegment copies the entries in [FirstEntryID, toEntryID] of the segment at segmentIndex, whose ensemble
+// already includes the replacement, onto the replacement if it is in their write sets and does not hold them yet.
+// The entries are read from all the Poras of the ensemble, skipping those failing.
+func (r *AutoRecovery) recoverSegment(ctx context.Context, metadata *DistributedLedgerMetadata, segmentIndex int, replacement string, toEntryID int) (int, error) {
+	segment := metadata.Segments[segmentIndex]
+	holders := make(map[int]map[string]bool)
+	payloads := make(map[int][]byte)
+	lastEntryID := segment.FirstEntryID - 1
+	lock := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for _, addr := range segment.Poras {
+		client, err := r.pool.Client(addr)
+		if err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entries, err := client.ReadEntries(ctx, metadata.LedgerID, segment.FirstEntryID, toEntryID, 0)
+			if err != nil {
+				return
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			for _, entry := range entries {
+				if holders[entry.EntryID] == nil {
+					holders[entry.EntryID] = make(map[string]bool)
+				}
+				holders[entry.EntryID][addr] = true
+				payloads[entry.EntryID] = entry.Payload
+				lastEntryID = max(lastEntryID, entry.EntryID)
+			}
+		}()
+	}
+	wg.Wait()
+	if toEntryID != math.MaxInt32 {
+		lastEntryID = toEntryID
+	}
+
+	client, err := r.pool.Client(replacement)
+	if err != nil {
+		return 0, err
+	}
+	nCopied := 0
+	for entryID := segment.FirstEntryID; entryID <= lastEntryID; entryID++ {
+		writeSet := metadata.WriteSet(entryID)
+		if !slices.Contains(writeSet, replacement) || countPoras(holders[entryID], writeSet) >= metadata.WriteQuorumSize {
+			continue
+		}
+		payload, ok := payloads[entryID]
+		if !ok {
+			return nCopied, fmt.Errorf("%w: entry %d of ledger %d", ErrEntryLost, entryID, metadata.LedgerID)
+		}
+		if err := client.AppendEntryWithID(ctx, metadata.LedgerID, entryID, payload); err != nil {
+			return nCopied, err
+		}
+		nCopied++
+	}
+	return nCopied, nil
+}
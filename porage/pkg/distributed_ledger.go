@@ -0,0 +1,603 @@
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// maxReplicaAppendAttempts is the number of attempts to append an entry to a Pora before the Pora is replaced.
+	maxReplicaAppendAttempts = 3
+	// replicaAppendRetryInterval is the interval between the attempts to append an entry to a Pora.
+	replicaAppendRetryInterval = 100 * time.Millisecond
+	// replicaAppendTimeout bounds each attempt to append an entry to a Pora.
+	replicaAppendTimeout = 10 * time.Second
+)
+
+// EnsembleSegment is the ensemble of Poras holding the entries from FirstEntryID until the FirstEntryID of the next
+// segment.
+type EnsembleSegment struct {
+	FirstEntryID int      `json:"first_entry_id"`
+	Poras        []string `json:"poras"`
+}
+
+// DistributedLedgerMetadata is the metadata of a DistributedLedger, which locates its entries on the Poras. The
+// segments are in ascending order of FirstEntryID, and the ensemble of the last segment is the one being written.
+type DistributedLedgerMetadata struct {
+	LedgerID        uint64            `json:"ledger_id"`
+	WriteQuorumSize int               `json:"write_quorum_size"`
+	AckQuorumSize   int               `json:"ack_quorum_size"`
+	Segments        []EnsembleSegment `json:"segments"`
+	IsClosed        bool              `json:"is_closed"`
+	// LastEntryID is the LastAddConfirmed when the ledger is closed, and -1 before.
+	LastEntryID int `json:"last_entry_id"`
+}
+
+// Ensemble returns the ensemble of the segment holding entryID.
+func (m *DistributedLedgerMetadata) Ensemble(entryID int) []string {
+	for i := len(m.Segments) - 1; i > 0; i-- {
+		if m.Segments[i].FirstEntryID <= entryID {
+			return m.Segments[i].Poras
+		}
+	}
+	return m.Segments[0].Poras
+}
+
+// WriteSet returns the Poras holding entryID, which are the Qw Poras of its ensemble of size E in a round-robin from
+// the (entryID mod E)-th one.
+func (m *DistributedLedgerMetadata) WriteSet(entryID int) []string {
+	ensemble := m.Ensemble(entryID)
+	writeSet := make([]string, 0, m.WriteQuorumSize)
+	for i := 0; i < m.WriteQuorumSize; i++ {
+		writeSet = append(writeSet, ensemble[(entryID+i)%len(ensemble)])
+	}
+	return writeSet
+}
+
+// clone returns a deep copy of the metadata.
+func (m *DistributedLedgerMetadata) clone() DistributedLedgerMetadata {
+	cloned := *m
+	cloned.Segments = make([]EnsembleSegment, 0, len(m.Segments))
+	for _, segment := range m.Segments {
+		cloned.Segments = append(cloned.Segments, EnsembleSegment{FirstEntryID: segment.FirstEntryID, Poras: slices.Clone(segment.Poras)})
+	}
+	return cloned
+}
+
+// DistributedLedger is a ledger replicated by the client on an ensemble of Poras. Each entry is appended with the
+// same entry ID to the write quorum (Qw) of the ensemble, and is acknowledged once the ack quorum (Qa) of them have
+// persisted it. The entries are confirmed in the order of their entry IDs, and the last confirmed entry ID is the
+// LastAddConfirmed (LAC). The entries up to the LAC can be read from any of the Poras holding them.
+//
+// The ensemble can be wider than the write quorum, in which case the entries are striped: the write set of entry e is
+// the Qw Poras starting from the (e mod E)-th one of the ensemble of size E, so each Pora holds Qw/E of the entries.
+//
+// A Pora to which the appends keep failing is replaced by another Pora of the pool. The ensemble change starts a new
+// segment from the entry after the LAC, so the confirmed entries stay on the Poras they were acknowledged by, and the
+// pending entries are appended to the new Pora before they are confirmed.
+//
+// The entry IDs are assigned by the DistributedLedger, so a ledger must have only one writer. Once an entry can not
+// be acknowledged by Qa Poras and no Pora can replace the failed ones, the DistributedLedger is broken: the entries
+// after the LAC fail with ErrNotEnoughReplicas, and so do the following appends.
+//
+// The metadata is kept in the memory of the writer only. The writer is expected to store it wherever the readers can
+// find it, e.g. in the MetadataStore, from Metadata once the ledger is created and from the listener set by
+// SetMetadataListener whenever it changes. A reader opens the ledger from the stored metadata with
+// OpenDistributedLedger.
+type DistributedLedger struct {
+	pool *PoraPool
+	// ensembleChangeLock serializes the ensemble changes.
+	ensembleChangeLock sync.Mutex
+	// isReadOnly is set if the ledger is opened by OpenDistributedLedger, which can not append.
+	isReadOnly bool
+
+	// lock protects the fields below.
+	lock             sync.Mutex
+	metadata         DistributedLedgerMetadata
+	nextEntryID      int
+	lastAddConfirmed int
+	// pendingAppends are the appends not confirmed yet, in ascending order of entry ID.
+	pendingAppends []*pendingAppend
+	// failedPoras are the Poras replaced in the ensemble, which are not chosen again.
+	failedPoras map[string]bool
+	// err is set once the ledger is broken.
+	err error
+	// metadataListener is called with the metadata whenever it changes.
+	metadataListener func(DistributedLedgerMetadata)
+}
+
+// pendingAppend is an entry being appended to its write set.
+type pendingAppend struct {
+	entryID int
+	payload []byte
+	// ctx is the context of the appends to the Poras, which is not canceled with the context of Append, so that the
+	// entry is still replicated to the whole write set after Append returns.
+	ctx context.Context
+	// ackedBy and failedBy are the Poras which have acknowledged the entry and failed to, among which only those in
+	// the current write set of the entry count.
+	ackedBy  map[string]bool
+	failedBy map[string]bool
+	isAcked  bool
+	// done is closed when the entry is confirmed or failed, and isDone is set. err is set before done is closed.
+	done   chan struct{}
+	isDone bool
+	err    error
+}
+
+// CreateDistributedLedger creates a ledger on an ensemble of ensembleSize Poras chosen from pool, and returns the
+// DistributedLedger that appends each entry to writeQuorumSize of them and waits for ackQuorumSize acks. The quorum
+// sizes must satisfy 1 <= ackQuorumSize <= writeQuorumSize <= ensembleSize, or ErrInvalidQuorum is returned. The Poras
+// failing to create the ledger are skipped, and ErrNotEnoughReplicas is returned if fewer than ensembleSize Poras
+// created it.
+func CreateDistributedLedger(ctx context.Context, ledgerID uint64, pool *PoraPool, ensembleSize int, writeQuorumSize int, ackQuorumSize int) (*DistributedLedger, error) {
+	if ackQuorumSize < 1 || ackQuorumSize > writeQuorumSize || writeQuorumSize > ensembleSize {
+		return nil, fmt.Errorf("%w: E=%d, Qw=%d, Qa=%d", ErrInvalidQuorum, ensembleSize, writeQuorumSize, ackQuorumSize)
+	}
+	d := &DistributedLedger{
+		pool: pool,
+		metadata: DistributedLedgerMetadata{
+			LedgerID:        ledgerID,
+			WriteQuorumSize: writeQuorumSize,
+			AckQuorumSize:   ackQuorumSize,
+			LastEntryID:     -1,
+		},
+		lastAddConfirmed: -1,
+		failedPoras:      make(map[string]bool),
+	}
+	ensemble := make([]string, 0, ensembleSize)
+	var err error
+	for _, addr := range pool.Addrs() {
+		if len(ensemble) == ensembleSize {
+			break
+		}
+		if err = d.createReplica(ctx, addr); err != nil {
+			if errors.Is(err, ErrLedgerExisted) {
+				return nil, err
+			}
+			continue
+		}
+		ensemble = append(ensemble, addr)
+	}
+	if len(ensemble) < ensembleSize {
+		return nil, fmt.Errorf("%w: created on %d Poras: %w", ErrNotEnoughReplicas, len(ensemble), err)
+	}
+	d.metadata.Segments = []EnsembleSegment{{FirstEntryID: 0, Poras: ensemble}}
+	return d, nil
+}
+
+// OpenDistributedLedger opens a ledger written by another DistributedLedger for reading from its metadata, which is
+// expected to be the latest one stored by the writer. The entries up to the LastEntryID of a closed ledger can be
+// read. The LAC of a ledger which is not closed is not in its metadata, so only the entries before its last segment,
+// which were confirmed before the ensemble changed, can be read. Append and Close fail with ErrLedgerClosed, since a
+// ledger has only one writer.
+func OpenDistributedLedger(pool *PoraPool, metadata DistributedLedgerMetadata) (*DistributedLedger, error) {
+	if len(metadata.Segments) == 0 || metadata.AckQuorumSize < 1 || metadata.AckQuorumSize > metadata.WriteQuorumSize {
+		return nil, fmt.Errorf("%w: ledger %d with %d segments, Qw=%d, Qa=%d", ErrInvalidQuorum, metadata.LedgerID,
+			len(metadata.Segments), metadata.WriteQuorumSize, metadata.AckQuorumSize)
+	}
+	for _, segment := range metadata.Segments {
+		if metadata.WriteQuorumSize > len(segment.Poras) {
+			return nil, fmt.Errorf("%w: ledger %d with E=%d, Qw=%d", ErrInvalidQuorum, metadata.LedgerID, len(segment.Poras), metadata.WriteQuorumSize)
+		}
+	}
+	d := &DistributedLedger{
+		pool:        pool,
+		isReadOnly:  true,
+		metadata:    metadata.clone(),
+		failedPoras: make(map[string]bool),
+	}
+	if metadata.IsClosed {
+		d.lastAddConfirmed = metadata.LastEntryID
+	} else {
+		d.lastAddConfirmed = metadata.Segments[len(metadata.Segments)-1].FirstEntryID - 1
+	}
+	d.nextEntryID = d.lastAddConfirmed + 1
+	return d, nil
+}
+
+// createReplica creates the ledger on the Pora at addr.
+func (d *DistributedLedger) createReplica(ctx context.Context, addr string) error {
+	client, err := d.pool.Client(addr)
+	if err != nil {
+		return err
+	}
+	return client.CreateLedger(ctx, d.metadata.LedgerID)
+}
+
+// LedgerID returns the ID of the ledger.
+func (d *DistributedLedger) LedgerID() uint64 {
+	return d.metadata.LedgerID
+}
+
+// Metadata returns a copy of the metadata of the ledger.
+func (d *DistributedLedger) Metadata() DistributedLedgerMetadata {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.metadata.clone()
+}
+
+// SetMetadataListener sets the function called with the metadata whenever it changes, i.e. when the ensemble changes
+// and when the ledger is closed. It is called right after an ensemble change, before the pending entries are appended
+// to the new Pora.
+func (d *DistributedLedger) SetMetadataListener(listener func(DistributedLedgerMetadata)) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.metadataListener = listener
+}
+
+// notifyMetadataChange calls the metadata listener with the current metadata, if there is one.
+func (d *DistributedLedger) notifyMetadataChange() {
+	d.lock.Lock()
+	listener := d.metadataListener
+	metadata := d.metadata.clone()
+	d.lock.Unlock()
+	if listener != nil {
+		listener(metadata)
+	}
+}
+
+// LastAddConfirmed returns the last entry ID confirmed by the ack quorum, which is -1 if there is none. All the
+// entries up to it are confirmed.
+func (d *DistributedLedger) LastAddConfirmed() int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.lastAddConfirmed
+}
+
+// Append appends an entry to its write set and returns its entry ID once it is confirmed, which means that it and
+// all the entries before it are acknowledged by the ack quorum.
+//
+// If ctx is done before the entry is confirmed, a *DurabilityUnknownError carrying the entry ID is returned. The entry
+// is still being appended to the Poras, and may be confirmed later.
+func (d *DistributedLedger) Append(ctx context.Context, payload []byte) (int, error) {
+	d.lock.Lock()
+	if d.err != nil {
+		d.lock.Unlock()
+		return -1, d.err
+	}
+	if d.metadata.IsClosed || d.isReadOnly {
+		d.lock.Unlock()
+		return -1, ErrLedgerClosed
+	}
+	pending := &pendingAppend{
+		entryID:  d.nextEntryID,
+		payload:  payload,
+		ctx:      context.WithoutCancel(ctx),
+		ackedBy:  make(map[string]bool),
+		failedBy: make(map[string]bool),
+		done:     make(chan struct{}),
+	}
+	d.nextEntryID++
+	d.pendingAppends = append(d.pendingAppends, pending)
+	writeSet := d.metadata.WriteSet(pending.entryID)
+	d.lock.Unlock()
+
+	for _, addr := range writeSet {
+		go d.appendToReplica(pending, addr)
+	}
+
+	select {
+	case <-pending.done:
+		if pending.err != nil {
+			return -1, pending.err
+		}
+		return pending.entryID, nil
+	case <-ctx.Done():
+		return -1, &DurabilityUnknownError{FirstEntryID: pending.entryID, LastEntryID: pending.entryID, Err: ctx.Err()}
+	}
+}
+
+// appendToReplica appends the entry to the Pora at addr, retrying a few times. If the Pora keeps failing, it is
+// replaced in the ensemble.
+func (d *DistributedLedger) appendToReplica(pending *pendingAppend, addr string) {
+	client, err := d.pool.Client(addr)
+	for attempt := 0; err == nil && attempt < maxReplicaAppendAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(replicaAppendRetryInterval)
+		}
+		ctx, cancel := context.WithTimeout(pending.ctx, replicaAppendTimeout)
+		err = client.AppendEntryWithID(ctx, d.metadata.LedgerID, pending.entryID, pending.payload)
+		cancel()
+		if err == nil {
+			d.ack(pending, addr)
+			return
+		}
+		if errors.Is(err, ErrEntryIDConflict) {
+			// Another entry with the ID exists, so retrying or another Pora does not help.
+			break
+		}
+	}
+	if !errors.Is(err, ErrEntryIDConflict) && d.replacePora(pending.ctx, addr) {
+		return
+	}
+	d.failReplica(pending, addr, err)
+}
+
+// ack counts the ack of the entry from the Pora at addr if the Pora is still in the write set of the entry, and
+// confirms the acknowledged entries following the LAC.
+func (d *DistributedLedger) ack(pending *pendingAppend, addr string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if pending.isDone {
+		return
+	}
+	writeSet := d.metadata.WriteSet(pending.entryID)
+	if !slices.Contains(writeSet, addr) {
+		return
+	}
+	pending.ackedBy[addr] = true
+	pending.isAcked = countPoras(pending.ackedBy, writeSet) >= d.metadata.AckQuorumSize
+	for len(d.pendingAppends) > 0 && d.pendingAppends[0].isAcked {
+		confirmed := d.pendingAppends[0]
+		d.lastAddConfirmed = confirmed.entryID
+		confirmed.isDone = true
+		close(confirmed.done)
+		d.pendingAppends = d.pendingAppends[1:]
+	}
+}
+
+// failReplica counts the failure of the entry on the Pora at addr if the Pora is still in the write set of the entry.
+// The ledger is broken if the rest of the write set is fewer than the ack quorum.
+func (d *DistributedLedger) failReplica(pending *pendingAppend, addr string, err error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if pending.isDone {
+		return
+	}
+	writeSet := d.metadata.WriteSet(pending.entryID)
+	if !slices.Contains(writeSet, addr) {
+		return
+	}
+	pending.failedBy[addr] = true
+	if len(writeSet)-countPoras(pending.failedBy, writeSet) >= d.metadata.AckQuorumSize {
+		return
+	}
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: entry %d: %w", ErrNotEnoughReplicas, pending.entryID, err)
+	}
+	for _, failed := range d.pendingAppends {
+		failed.err = d.err
+		failed.isDone = true
+		close(failed.done)
+	}
+	d.pendingAppends = nil
+}
+
+// replacePora replaces the failed Pora at failedAddr in the current ensemble by another Pora of the pool. The new
+// segment starts from the entry after the LAC, and the pending entries whose write sets now include the new Pora are
+// appended to it. It returns true if failedAddr is no longer in the current ensemble.
+func (d *DistributedLedger) replacePora(ctx context.Context, failedAddr string) bool {
+	d.ensembleChangeLock.Lock()
+	defer d.ensembleChangeLock.Unlock()
+
+	d.lock.Lock()
+	if d.err != nil || d.metadata.IsClosed {
+		d.lock.Unlock()
+		return false
+	}
+	ensemble := slices.Clone(d.metadata.Segments[len(d.metadata.Segments)-1].Poras)
+	index := slices.Index(ensemble, failedAddr)
+	if index < 0 {
+		// The Pora is replaced already, and the pending entries are appended to the new one.
+		d.lock.Unlock()
+		return true
+	}
+	d.failedPoras[failedAddr] = true
+	excluded := make(map[string]bool, len(d.failedPoras)+len(ensemble))
+	for addr := range d.failedPoras {
+		excluded[addr] = true
+	}
+	for _, addr := range ensemble {
+		excluded[addr] = true
+	}
+	d.lock.Unlock()
+
+	replacement := ""
+	for _, addr := range d.pool.Addrs() {
+		if excluded[addr] {
+			continue
+		}
+		createCtx, cancel := context.WithTimeout(ctx, replicaAppendTimeout)
+		err := d.createReplica(createCtx, addr)
+		cancel()
+		if err == nil || errors.Is(err, ErrLedgerExisted) {
+			replacement = addr
+			break
+		}
+	}
+	if replacement == "" {
+		return false
+	}
+
+	d.lock.Lock()
+	if d.err != nil || d.metadata.IsClosed {
+		d.lock.Unlock()
+		return false
+	}
+	ensemble[index] = replacement
+	firstEntryID := d.lastAddConfirmed + 1
+	lastSegment := &d.metadata.Segments[len(d.metadata.Segments)-1]
+	if lastSegment.FirstEntryID == firstEntryID {
+		lastSegment.Poras = ensemble
+	} else {
+		d.metadata.Segments = append(d.metadata.Segments, EnsembleSegment{FirstEntryID: firstEntryID, Poras: ensemble})
+	}
+	// The acks of the failed Pora no longer count for the pending entries, which are all in the new segment.
+	resends := make([]*pendingAppend, 0, len(d.pendingAppends))
+	for _, pending := range d.pendingAppends {
+		writeSet := d.metadata.WriteSet(pending.entryID)
+		pending.isAcked = countPoras(pending.ackedBy, writeSet) >= d.metadata.AckQuorumSize
+		if slices.Contains(writeSet, replacement) {
+			resends = append(resends, pending)
+		}
+	}
+	d.lock.Unlock()
+
+	d.notifyMetadataChange()
+	for _, pending := range resends {
+		go d.appendToReplica(pending, replacement)
+	}
+	return true
+}
+
+// countPoras returns the number of the Poras of writeSet in poras.
+func countPoras(poras map[string]bool, writeSet []string) int {
+	count := 0
+	for _, addr := range writeSet {
+		if poras[addr] {
+			count++
+		}
+	}
+	return count
+}
+
+// Read reads a confirmed entry. The Poras of the write set of the entry are tried in turn until one of them returns
+// the entry. ErrEntryNotFound is returned if the entry is after the LAC.
+func (d *DistributedLedger) Read(ctx context.Context, entryID int) ([]byte, error) {
+	if entryID < 0 {
+		return nil, ErrInvalidEntryID
+	}
+	d.lock.Lock()
+	lastAddConfirmed := d.lastAddConfirmed
+	writeSet := d.metadata.WriteSet(entryID)
+	d.lock.Unlock()
+	if entryID > lastAddConfirmed {
+		return nil, ErrEntryNotFound
+	}
+	var err error
+	for _, addr := range writeSet {
+		var client *PorageClient
+		if client, err = d.pool.Client(addr); err != nil {
+			continue
+		}
+		var payload []byte
+		payload, err = client.GetEntryFromLedger(ctx, d.metadata.LedgerID, entryID)
+		if err == nil {
+			return payload, nil
+		}
+	}
+	return nil, err
+}
+
+// ReadEntries reads the confirmed entries in [fromEntryID, toEntryID] in ascending order of entry ID. The range of
+// each segment is read concurrently from the fewest Poras of its ensemble holding all the entries between them, and
+// the entries gathered from them are reassembled in order. The entries missing in those Poras are read from the
+// others of their write sets.
+func (d *DistributedLedger) ReadEntries(ctx context.Context, fromEntryID int, toEntryID int) ([]*LedgerEntry, error) {
+	if fromEntryID < 0 {
+		return nil, ErrInvalidEntryID
+	}
+	metadata := d.Metadata()
+	toEntryID = min(toEntryID, d.LastAddConfirmed())
+	if fromEntryID > toEntryID {
+		return nil, nil
+	}
+
+	gathered := make(map[int]*LedgerEntry, toEntryID-fromEntryID+1)
+	for i, segment := range metadata.Segments {
+		segmentFromEntryID := max(fromEntryID, segment.FirstEntryID)
+		segmentToEntryID := toEntryID
+		if i+1 < len(metadata.Segments) {
+			segmentToEntryID = min(toEntryID, metadata.Segments[i+1].FirstEntryID-1)
+		}
+		if segmentFromEntryID > segmentToEntryID {
+			continue
+		}
+		d.gatherEntries(ctx, segment.Poras, segmentFromEntryID, segmentToEntryID, gathered)
+	}
+
+	entries := make([]*LedgerEntry, 0, toEntryID-fromEntryID+1)
+	for entryID := fromEntryID; entryID <= toEntryID; entryID++ {
+		if entry, ok := gathered[entryID]; ok {
+			entries = append(entries, entry)
+			continue
+		}
+		payload, err := d.Read(ctx, entryID)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &LedgerEntry{EntryID: entryID, Payload: payload})
+	}
+	return entries, nil
+}
+
+// gatherEntries reads [fromEntryID, toEntryID] concurrently from the Poras of ensemble covering the range into
+// gathered. The failed Poras are skipped.
+func (d *DistributedLedger) gatherEntries(ctx context.Context, ensemble []string, fromEntryID int, toEntryID int, gathered map[int]*LedgerEntry) {
+	// The (k mod E)-th Pora holds the entries e with e mod E in [k-Qw+1, k], so every Qw-th Pora from the last one of
+	// the write set of fromEntryID covers all the entries.
+	writeQuorumSize := d.metadata.WriteQuorumSize
+	nReplicas := (len(ensemble) + writeQuorumSize - 1) / writeQuorumSize
+	found := make([][]*LedgerEntry, nReplicas)
+	wg := sync.WaitGroup{}
+	for i := 0; i < nReplicas; i++ {
+		client, err := d.pool.Client(ensemble[(fromEntryID+writeQuorumSize-1+i*writeQuorumSize)%len(ensemble)])
+		if err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			found[i], _ = client.ReadEntries(ctx, d.metadata.LedgerID, fromEntryID, toEntryID, 0)
+		}()
+	}
+	wg.Wait()
+	for _, replicaEntries := range found {
+		for _, entry := range replicaEntries {
+			gathered[entry.EntryID] = entry
+		}
+	}
+}
+
+// Close waits for the pending appends and closes the ledger on the Poras of the current ensemble, after which no entry
+// can be appended. The LAC is returned, and recorded in the metadata as the last entry ID. ErrNotEnoughReplicas is
+// returned if fewer than the ack quorum of Poras closed the ledger.
+func (d *DistributedLedger) Close(ctx context.Context) (int, error) {
+	d.lock.Lock()
+	if d.isReadOnly {
+		d.lock.Unlock()
+		return -1, ErrLedgerClosed
+	}
+	d.metadata.IsClosed = true
+	var lastPending *pendingAppend
+	if len(d.pendingAppends) > 0 {
+		lastPending = d.pendingAppends[len(d.pendingAppends)-1]
+	}
+	d.lock.Unlock()
+	if lastPending != nil {
+		select {
+		case <-lastPending.done:
+		case <-ctx.Done():
+			return -1, ctx.Err()
+		}
+	}
+
+	d.lock.Lock()
+	d.metadata.LastEntryID = d.lastAddConfirmed
+	lastAddConfirmed := d.lastAddConfirmed
+	ensemble := slices.Clone(d.metadata.Segments[len(d.metadata.Segments)-1].Poras)
+	d.lock.Unlock()
+	d.notifyMetadataChange()
+
+	nClosed := 0
+	var err error
+	for _, addr := range ensemble {
+		client, closeErr := d.pool.Client(addr)
+		if closeErr == nil {
+			_, closeErr = client.CloseLedger(ctx, d.metadata.LedgerID)
+		}
+		if closeErr != nil {
+			err = closeErr
+			continue
+		}
+		nClosed++
+	}
+	if nClosed < d.metadata.AckQuorumSize {
+		return lastAddConfirmed, fmt.Errorf("%w: closed on %d Poras: %w", ErrNotEnoughReplicas, nClosed, err)
+	}
+	return lastAddConfirmed, nil
+}
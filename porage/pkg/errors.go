@@ -1,6 +1,15 @@
 package pkg
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
 
 var (
 	// ErrLedgerExisted is the error when the ledger already exists.
@@ -9,4 +18,149 @@ var (
 	ErrLedgerNotFound = errors.New("ledger not found")
 	// ErrEntryNotFound is the error when the entry is not found.
 	ErrEntryNotFound = errors.New("entry not found")
+	// ErrEntryTrimmed is the error when the entry is below the low-water mark of the ledger, which is moved by
+	// TrimLedger. Unlike ErrEntryNotFound, the entry existed but can not be read anymore.
+	ErrEntryTrimmed = errors.New("entry trimmed")
+	// ErrLedgerFenced is the error when an entry is appended to a fenced ledger.
+	ErrLedgerFenced = errors.New("ledger fenced")
+	// ErrLedgerClosed is the error when an entry is appended to a closed ledger.
+	ErrLedgerClosed = errors.New("ledger closed")
+	// ErrEntryIDConflict is the error when an entry is appended with an existing entry ID but a different payload.
+	ErrEntryIDConflict = errors.New("entry id conflict")
+	// ErrInvalidEntryID is the error when the entry ID is negative.
+	ErrInvalidEntryID = errors.New("invalid entry id")
+	// ErrInvalidRetentionPolicy is the error when a limit of the retention policy of a ledger is negative.
+	ErrInvalidRetentionPolicy = errors.New("invalid retention policy")
+	// ErrServerBusy is the error when the Pora is too busy to accept the request. The request can be retried later.
+	ErrServerBusy = errors.New("server busy")
+	// ErrDataCorrupted is the error when the data stored in the Pora fails the checksum verification.
+	ErrDataCorrupted = errors.New("data corrupted")
+	// ErrServerNotReady is the error when the Pora has not finished its recovery. The request can be retried later.
+	ErrServerNotReady = errors.New("server not ready")
+	// ErrDurabilityUnknown is the error when it is unknown whether the appended entries are persisted. It is wrapped
+	// by *DurabilityUnknownError.
+	ErrDurabilityUnknown = errors.New("durability unknown")
+	// ErrInvalidQuorum is the error when the ensemble and the quorum sizes of a DistributedLedger are inconsistent.
+	// It is returned by the client only.
+	ErrInvalidQuorum = errors.New("invalid quorum")
+	// ErrNotEnoughReplicas is the error when an entry of a DistributedLedger can not be acknowledged by the ack quorum
+	// of Poras. It is returned by the client only.
+	ErrNotEnoughReplicas = errors.New("not enough replicas")
+	// ErrEntryLost is the error when no Pora holds a confirmed entry of a DistributedLedger, so the entry can not be
+	// re-replicated. It is returned by the client only.
+	ErrEntryLost = errors.New("entry lost")
 )
+
+// DurabilityUnknownError is the error when an append is canceled or its deadline is exceeded after the entries are
+// accepted but before their journal commit completes. The entries in [FirstEntryID, LastEntryID] may or may not be
+// persisted, so a writer should read them back or retry them with AppendEntryWithID, which is idempotent.
+//
+// It wraps both ErrDurabilityUnknown and Err, which is context.Canceled or context.DeadlineExceeded.
+type DurabilityUnknownError struct {
+	FirstEntryID int
+	LastEntryID  int
+	Err          error
+}
+
+func (e *DurabilityUnknownError) Error() string {
+	return fmt.Sprintf("%v: entries [%d, %d]: %v", ErrDurabilityUnknown, e.FirstEntryID, e.LastEntryID, e.Err)
+}
+
+func (e *DurabilityUnknownError) Unwrap() []error {
+	return []error{ErrDurabilityUnknown, e.Err}
+}
+
+// ErrorDomain is the domain of the ErrorInfo details attached to the errors returned by Pora.
+const ErrorDomain = "porage"
+
+// The reasons of the ErrorInfo details attached to the errors returned by Pora. Each reason identifies one of the
+// sentinel errors above.
+const (
+	ReasonLedgerExisted          = "LEDGER_EXISTED"
+	ReasonLedgerNotFound         = "LEDGER_NOT_FOUND"
+	ReasonEntryNotFound          = "ENTRY_NOT_FOUND"
+	ReasonEntryTrimmed           = "ENTRY_TRIMMED"
+	ReasonLedgerFenced           = "LEDGER_FENCED"
+	ReasonLedgerClosed           = "LEDGER_CLOSED"
+	ReasonEntryIDConflict        = "ENTRY_ID_CONFLICT"
+	ReasonInvalidEntryID         = "INVALID_ENTRY_ID"
+	ReasonInvalidRetentionPolicy = "INVALID_RETENTION_POLICY"
+	ReasonServerBusy             = "SERVER_BUSY"
+	ReasonDataCorrupted          = "DATA_CORRUPTED"
+	ReasonServerNotReady         = "SERVER_NOT_READY"
+	// ReasonDurabilityUnknown comes with the metadata MetadataFirstEntryID and MetadataLastEntryID.
+	ReasonDurabilityUnknown = "DURABILITY_UNKNOWN"
+)
+
+// The metadata keys of the ErrorInfo details.
+const (
+	MetadataFirstEntryID = "first_entry_id"
+	MetadataLastEntryID  = "last_entry_id"
+)
+
+var reasonErrors = map[string]error{
+	ReasonLedgerExisted:          ErrLedgerExisted,
+	ReasonLedgerNotFound:         ErrLedgerNotFound,
+	ReasonEntryNotFound:          ErrEntryNotFound,
+	ReasonEntryTrimmed:           ErrEntryTrimmed,
+	ReasonLedgerFenced:           ErrLedgerFenced,
+	ReasonLedgerClosed:           ErrLedgerClosed,
+	ReasonEntryIDConflict:        ErrEntryIDConflict,
+	ReasonInvalidEntryID:         ErrInvalidEntryID,
+	ReasonInvalidRetentionPolicy: ErrInvalidRetentionPolicy,
+	ReasonServerBusy:             ErrServerBusy,
+	ReasonDataCorrupted:          ErrDataCorrupted,
+	ReasonServerNotReady:         ErrServerNotReady,
+}
+
+// Error is an error returned by Pora with a known reason. It wraps the sentinel error of the reason, so that
+// errors.Is works with the sentinel errors, and keeps the gRPC status for status.Code and status.Convert.
+type Error struct {
+	status *status.Status
+	err    error
+}
+
+func (e *Error) Error() string {
+	return e.status.Message()
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// GRPCStatus returns the gRPC status of the error.
+func (e *Error) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// fromRPCError converts an error returned by a gRPC call into an *Error if it carries the ErrorInfo details of a
+// known reason. Other errors are returned as they are.
+func fromRPCError(err error) error {
+	rpcStatus, ok := status.FromError(err)
+	if !ok || err == nil {
+		return err
+	}
+	for _, detail := range rpcStatus.Details() {
+		errorInfo, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || errorInfo.GetDomain() != ErrorDomain {
+			continue
+		}
+		if errorInfo.GetReason() == ReasonDurabilityUnknown {
+			return &Error{status: rpcStatus, err: fromDurabilityUnknownErrorInfo(rpcStatus, errorInfo)}
+		}
+		if sentinelErr, ok := reasonErrors[errorInfo.GetReason()]; ok {
+			return &Error{status: rpcStatus, err: sentinelErr}
+		}
+	}
+	return err
+}
+
+func fromDurabilityUnknownErrorInfo(rpcStatus *status.Status, errorInfo *errdetails.ErrorInfo) *DurabilityUnknownError {
+	durabilityUnknownErr := &DurabilityUnknownError{Err: context.Canceled}
+	if rpcStatus.Code() == codes.DeadlineExceeded {
+		durabilityUnknownErr.Err = context.DeadlineExceeded
+	}
+	durabilityUnknownErr.FirstEntryID, _ = strconv.Atoi(errorInfo.GetMetadata()[MetadataFirstEntryID])
+	durabilityUnknownErr.LastEntryID, _ = strconv.Atoi(errorInfo.GetMetadata()[MetadataLastEntryID])
+	return durabilityUnknownErr
+}
@@ -0,0 +1,94 @@
+// Package metadata provides the MetadataStore keeping the metadata of a Porage cluster, such as the registrations of
+// the Poras and the metadata of the distributed ledgers. The LocalStore keeps it on a single node for tests and local
+// runs, and the EtcdStore keeps it in etcd.
+package metadata
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrKeyNotFound is the error when the key does not exist.
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrVersionConflict is the error when the version of the key does not match the one expected by CompareAndSwap.
+	ErrVersionConflict = errors.New("version conflict")
+	// ErrLeaseNotFound is the error when the lease does not exist, which may have expired or been revoked.
+	ErrLeaseNotFound = errors.New("lease not found")
+	// ErrInvalidLeaseTTL is the error when the TTL of a lease is not positive.
+	ErrInvalidLeaseTTL = errors.New("invalid lease ttl")
+	// ErrStoreClosed is the error when the MetadataStore is closed.
+	ErrStoreClosed = errors.New("metadata store closed")
+)
+
+// LeaseID identifies a lease. The keys put with a lease are deleted when the lease expires or is revoked.
+type LeaseID int64
+
+// NoLease is the LeaseID of the keys which are not attached to a lease.
+const NoLease LeaseID = 0
+
+// KeyValue is a key with its value. The Version of a key is 1 when it is created and is increased by each put, and a
+// key which does not exist has the version 0.
+type KeyValue struct {
+	Key     string
+	Value   []byte
+	Version int64
+	LeaseID LeaseID
+}
+
+// EventType is the type of a change of a key.
+type EventType int
+
+const (
+	// EventPut is the event when a key is created or updated.
+	EventPut EventType = iota
+	// EventDelete is the event when a key is deleted, including by the expiry or the revocation of its lease.
+	EventDelete
+)
+
+// String returns the name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case EventPut:
+		return "PUT"
+	case EventDelete:
+		return "DELETE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Event is a change of a key. The KeyValue of a delete event only has the key.
+type Event struct {
+	Type EventType
+	KeyValue
+}
+
+// MetadataStore is a key-value store with versioned keys, watches and leases, on which the metadata of a Porage
+// cluster is kept. The keys are strings ordered lexicographically, and are usually grouped by a prefix such as
+// "/poras/".
+type MetadataStore interface {
+	// Get returns the key with its value. ErrKeyNotFound is returned if it does not exist.
+	Get(ctx context.Context, key string) (*KeyValue, error)
+	// List returns the keys with prefix in ascending order.
+	List(ctx context.Context, prefix string) ([]*KeyValue, error)
+	// Put creates or updates the key, attached to the lease unless leaseID is NoLease, and returns its new version.
+	Put(ctx context.Context, key string, value []byte, leaseID LeaseID) (int64, error)
+	// CompareAndSwap puts the key only if its version is expectedVersion, where 0 means that the key must not exist,
+	// and returns its new version. ErrVersionConflict is returned otherwise.
+	CompareAndSwap(ctx context.Context, key string, value []byte, expectedVersion int64, leaseID LeaseID) (int64, error)
+	// Delete deletes the key. Deleting a key which does not exist succeeds.
+	Delete(ctx context.Context, key string) error
+	// Watch returns the changes of the keys with prefix after the call, in the order they are made. The channel is
+	// closed when ctx is done or the MetadataStore is closed.
+	Watch(ctx context.Context, prefix string) (<-chan Event, error)
+	// GrantLease creates a lease which expires after ttl unless it is renewed by KeepAlive.
+	GrantLease(ctx context.Context, ttl time.Duration) (LeaseID, error)
+	// KeepAlive renews the lease for its ttl. ErrLeaseNotFound is returned if it has expired or been revoked.
+	KeepAlive(ctx context.Context, leaseID LeaseID) error
+	// RevokeLease revokes the lease and deletes the keys attached to it.
+	RevokeLease(ctx context.Context, leaseID LeaseID) error
+	// Close closes the MetadataStore and the watches. The leases are left to expire.
+	Close() error
+}
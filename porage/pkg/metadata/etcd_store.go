@@ -0,0 +1,161 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// EtcdStore is a MetadataStore kept in etcd. The versions of the keys are the versions of etcd, and the leases are
+// the leases of etcd, whose TTLs are rounded up to seconds.
+type EtcdStore struct {
+	client *clientv3.Client
+}
+
+// NewEtcdStore connects to the etcd cluster at endpoints, waiting for dialTimeout at most.
+func NewEtcdStore(endpoints []string, dialTimeout time.Duration) (*EtcdStore, error) {
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   endpoints,
+		DialTimeout: dialTimeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &EtcdStore{client: client}, nil
+}
+
+// Get returns the key with its value.
+func (s *EtcdStore) Get(ctx context.Context, key string) (*KeyValue, error) {
+	response, err := s.client.Get(ctx, key)
+	if err != nil {
+		return nil, toEtcdStoreError(err)
+	}
+	if len(response.Kvs) == 0 {
+		return nil, ErrKeyNotFound
+	}
+	kv := response.Kvs[0]
+	return &KeyValue{Key: string(kv.Key), Value: kv.Value, Version: kv.Version, LeaseID: LeaseID(kv.Lease)}, nil
+}
+
+// List returns the keys with prefix in ascending order.
+func (s *EtcdStore) List(ctx context.Context, prefix string) ([]*KeyValue, error) {
+	response, err := s.client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	if err != nil {
+		return nil, toEtcdStoreError(err)
+	}
+	keyValues := make([]*KeyValue, 0, len(response.Kvs))
+	for _, kv := range response.Kvs {
+		keyValues = append(keyValues, &KeyValue{Key: string(kv.Key), Value: kv.Value, Version: kv.Version, LeaseID: LeaseID(kv.Lease)})
+	}
+	return keyValues, nil
+}
+
+// Put creates or updates the key and returns its new version.
+func (s *EtcdStore) Put(ctx context.Context, key string, value []byte, leaseID LeaseID) (int64, error) {
+	response, err := s.client.Put(ctx, key, string(value), clientv3.WithLease(clientv3.LeaseID(leaseID)), clientv3.WithPrevKV())
+	if err != nil {
+		return 0, toEtcdStoreError(err)
+	}
+	if response.PrevKv == nil {
+		return 1, nil
+	}
+	return response.PrevKv.Version + 1, nil
+}
+
+// CompareAndSwap puts the key only if its version is expectedVersion.
+func (s *EtcdStore) CompareAndSwap(ctx context.Context, key string, value []byte, expectedVersion int64, leaseID LeaseID) (int64, error) {
+	response, err := s.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.Version(key), "=", expectedVersion)).
+		Then(clientv3.OpPut(key, string(value), clientv3.WithLease(clientv3.LeaseID(leaseID)))).
+		Commit()
+	if err != nil {
+		return 0, toEtcdStoreError(err)
+	}
+	if !response.Succeeded {
+		return 0, ErrVersionConflict
+	}
+	return expectedVersion + 1, nil
+}
+
+// Delete deletes the key.
+func (s *EtcdStore) Delete(ctx context.Context, key string) error {
+	_, err := s.client.Delete(ctx, key)
+	return toEtcdStoreError(err)
+}
+
+// Watch returns the changes of the keys with prefix after the call.
+func (s *EtcdStore) Watch(ctx context.Context, prefix string) (<-chan Event, error) {
+	// The revision of the watch is fixed before returning, so that no change after the call is missed.
+	response, err := s.client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
+	if err != nil {
+		return nil, toEtcdStoreError(err)
+	}
+	watchCh := s.client.Watch(ctx, prefix, clientv3.WithPrefix(), clientv3.WithRev(response.Header.Revision+1))
+	eventCh := make(chan Event)
+	go func() {
+		defer close(eventCh)
+		for watchResponse := range watchCh {
+			if watchResponse.Err() != nil {
+				return
+			}
+			for _, etcdEvent := range watchResponse.Events {
+				event := Event{Type: EventPut, KeyValue: KeyValue{
+					Key:     string(etcdEvent.Kv.Key),
+					Value:   etcdEvent.Kv.Value,
+					Version: etcdEvent.Kv.Version,
+					LeaseID: LeaseID(etcdEvent.Kv.Lease),
+				}}
+				if etcdEvent.Type == clientv3.EventTypeDelete {
+					event = Event{Type: EventDelete, KeyValue: KeyValue{Key: string(etcdEvent.Kv.Key)}}
+				}
+				select {
+				case eventCh <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return eventCh, nil
+}
+
+// GrantLease creates a lease which expires after ttl, rounded up to seconds, unless it is renewed.
+func (s *EtcdStore) GrantLease(ctx context.Context, ttl time.Duration) (LeaseID, error) {
+	if ttl <= 0 {
+		return NoLease, ErrInvalidLeaseTTL
+	}
+	response, err := s.client.Grant(ctx, int64(math.Ceil(ttl.Seconds())))
+	if err != nil {
+		return NoLease, toEtcdStoreError(err)
+	}
+	return LeaseID(response.ID), nil
+}
+
+// KeepAlive renews the lease for its ttl.
+func (s *EtcdStore) KeepAlive(ctx context.Context, leaseID LeaseID) error {
+	_, err := s.client.KeepAliveOnce(ctx, clientv3.LeaseID(leaseID))
+	return toEtcdStoreError(err)
+}
+
+// RevokeLease revokes the lease and deletes the keys attached to it.
+func (s *EtcdStore) RevokeLease(ctx context.Context, leaseID LeaseID) error {
+	_, err := s.client.Revoke(ctx, clientv3.LeaseID(leaseID))
+	return toEtcdStoreError(err)
+}
+
+// Close closes the connection to etcd and the watches.
+func (s *EtcdStore) Close() error {
+	return s.client.Close()
+}
+
+// toEtcdStoreError converts an error of etcd into the errors of the MetadataStore.
+func toEtcdStoreError(err error) error {
+	if errors.Is(err, rpctypes.ErrLeaseNotFound) {
+		return ErrLeaseNotFound
+	}
+	return err
+}
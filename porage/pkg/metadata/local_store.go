@@ -0,0 +1,429 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LocalStore is a MetadataStore on a single node, which keeps the keys in memory and optionally persists them to a
+// file. The keys attached to a lease are not persisted, as the leases do not survive a restart.
+type LocalStore struct {
+	// filePath is the file persisting the keys, which is empty for a LocalStore in memory only.
+	filePath string
+
+	lock        sync.Mutex
+	keyValues   map[string]*KeyValue
+	leases      map[LeaseID]*localLease
+	nextLeaseID LeaseID
+	watchers    map[*localWatcher]struct{}
+	isClosed    bool
+}
+
+// localLease is a lease of a LocalStore, which expires at its deadline unless it is renewed.
+type localLease struct {
+	ttl      time.Duration
+	deadline time.Time
+	timer    *time.Timer
+	keys     map[string]struct{}
+}
+
+// localWatcher queues the events of the keys with prefix until they are sent to the channel of the watch.
+type localWatcher struct {
+	prefix string
+
+	lock     sync.Mutex
+	pending  []Event
+	notifyCh chan struct{}
+	closedCh chan struct{}
+}
+
+// persistedKeyValue is a key persisted in the file of a LocalStore.
+type persistedKeyValue struct {
+	Key     string `json:"key"`
+	Value   []byte `json:"value"`
+	Version int64  `json:"version"`
+}
+
+// NewLocalStore creates a LocalStore in memory only.
+func NewLocalStore() *LocalStore {
+	return &LocalStore{
+		keyValues:   make(map[string]*KeyValue),
+		leases:      make(map[LeaseID]*localLease),
+		nextLeaseID: 1,
+		watchers:    make(map[*localWatcher]struct{}),
+	}
+}
+
+// OpenLocalStore opens a LocalStore persisting the keys to the file at filePath, which is created if it does not
+// exist.
+func OpenLocalStore(filePath string) (*LocalStore, error) {
+	s := NewLocalStore()
+	s.filePath = filePath
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	persisted := make([]persistedKeyValue, 0)
+	if err := json.Unmarshal(data, &persisted); err != nil {
+		return nil, err
+	}
+	for _, keyValue := range persisted {
+		s.keyValues[keyValue.Key] = &KeyValue{Key: keyValue.Key, Value: keyValue.Value, Version: keyValue.Version}
+	}
+	return s, nil
+}
+
+// Get returns the key with its value.
+func (s *LocalStore) Get(ctx context.Context, key string) (*KeyValue, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.isClosed {
+		return nil, ErrStoreClosed
+	}
+	keyValue, ok := s.keyValues[key]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return cloneKeyValue(keyValue), nil
+}
+
+// List returns the keys with prefix in ascending order.
+func (s *LocalStore) List(ctx context.Context, prefix string) ([]*KeyValue, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.isClosed {
+		return nil, ErrStoreClosed
+	}
+	keyValues := make([]*KeyValue, 0)
+	for key, keyValue := range s.keyValues {
+		if strings.HasPrefix(key, prefix) {
+			keyValues = append(keyValues, cloneKeyValue(keyValue))
+		}
+	}
+	sort.Slice(keyValues, func(i, j int) bool { return keyValues[i].Key < keyValues[j].Key })
+	return keyValues, nil
+}
+
+// Put creates or updates the key and returns its new version.
+func (s *LocalStore) Put(ctx context.Context, key string, value []byte, leaseID LeaseID) (int64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.isClosed {
+		return 0, ErrStoreClosed
+	}
+	return s.putLocked(key, value, leaseID)
+}
+
+// CompareAndSwap puts the key only if its version is expectedVersion.
+func (s *LocalStore) CompareAndSwap(ctx context.Context, key string, value []byte, expectedVersion int64, leaseID LeaseID) (int64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.isClosed {
+		return 0, ErrStoreClosed
+	}
+	version := int64(0)
+	if keyValue, ok := s.keyValues[key]; ok {
+		version = keyValue.Version
+	}
+	if version != expectedVersion {
+		return 0, ErrVersionConflict
+	}
+	return s.putLocked(key, value, leaseID)
+}
+
+// Delete deletes the key.
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.isClosed {
+		return ErrStoreClosed
+	}
+	return s.deleteLocked(key)
+}
+
+// Watch returns the changes of the keys with prefix after the call.
+func (s *LocalStore) Watch(ctx context.Context, prefix string) (<-chan Event, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.isClosed {
+		return nil, ErrStoreClosed
+	}
+	watcher := &localWatcher{
+		prefix:   prefix,
+		notifyCh: make(chan struct{}, 1),
+		closedCh: make(chan struct{}),
+	}
+	s.watchers[watcher] = struct{}{}
+	eventCh := make(chan Event)
+	go s.runWatcher(ctx, watcher, eventCh)
+	return eventCh, nil
+}
+
+// runWatcher sends the events queued by the watcher to eventCh, until ctx is done or the LocalStore is closed.
+func (s *LocalStore) runWatcher(ctx context.Context, watcher *localWatcher, eventCh chan<- Event) {
+	defer close(eventCh)
+	defer func() {
+		s.lock.Lock()
+		delete(s.watchers, watcher)
+		s.lock.Unlock()
+	}()
+	for {
+		watcher.lock.Lock()
+		events := watcher.pending
+		watcher.pending = nil
+		watcher.lock.Unlock()
+		for _, event := range events {
+			select {
+			case eventCh <- event:
+			case <-ctx.Done():
+				return
+			case <-watcher.closedCh:
+				return
+			}
+		}
+		select {
+		case <-watcher.notifyCh:
+		case <-ctx.Done():
+			return
+		case <-watcher.closedCh:
+			return
+		}
+	}
+}
+
+// GrantLease creates a lease which expires after ttl unless it is renewed.
+func (s *LocalStore) GrantLease(ctx context.Context, ttl time.Duration) (LeaseID, error) {
+	if ttl <= 0 {
+		return NoLease, ErrInvalidLeaseTTL
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.isClosed {
+		return NoLease, ErrStoreClosed
+	}
+	leaseID := s.nextLeaseID
+	s.nextLeaseID++
+	s.leases[leaseID] = &localLease{
+		ttl:      ttl,
+		deadline: time.Now().Add(ttl),
+		timer:    time.AfterFunc(ttl, func() { s.expireLease(leaseID) }),
+		keys:     make(map[string]struct{}),
+	}
+	return leaseID, nil
+}
+
+// KeepAlive renews the lease for its ttl.
+func (s *LocalStore) KeepAlive(ctx context.Context, leaseID LeaseID) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.isClosed {
+		return ErrStoreClosed
+	}
+	lease, ok := s.leases[leaseID]
+	if !ok {
+		return ErrLeaseNotFound
+	}
+	lease.deadline = time.Now().Add(lease.ttl)
+	lease.timer.Reset(lease.ttl)
+	return nil
+}
+
+// RevokeLease revokes the lease and deletes the keys attached to it.
+func (s *LocalStore) RevokeLease(ctx context.Context, leaseID LeaseID) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.isClosed {
+		return ErrStoreClosed
+	}
+	if _, ok := s.leases[leaseID]; !ok {
+		return ErrLeaseNotFound
+	}
+	return s.revokeLeaseLocked(leaseID)
+}
+
+// expireLease revokes the lease if it has not been renewed since its timer was set.
+func (s *LocalStore) expireLease(leaseID LeaseID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	lease, ok := s.leases[leaseID]
+	if s.isClosed || !ok || time.Now().Before(lease.deadline) {
+		return
+	}
+	s.revokeLeaseLocked(leaseID)
+}
+
+// Close closes the LocalStore and the watches.
+func (s *LocalStore) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.isClosed {
+		return nil
+	}
+	s.isClosed = true
+	for _, lease := range s.leases {
+		lease.timer.Stop()
+	}
+	for watcher := range s.watchers {
+		close(watcher.closedCh)
+	}
+	return nil
+}
+
+// putLocked puts the key, persisting it unless it is attached to a lease.
+func (s *LocalStore) putLocked(key string, value []byte, leaseID LeaseID) (int64, error) {
+	if _, ok := s.leases[leaseID]; leaseID != NoLease && !ok {
+		return 0, ErrLeaseNotFound
+	}
+	oldKeyValue, isExisting := s.keyValues[key]
+	keyValue := &KeyValue{Key: key, Value: slices.Clone(value), Version: 1, LeaseID: leaseID}
+	if isExisting {
+		keyValue.Version = oldKeyValue.Version + 1
+	}
+	s.keyValues[key] = keyValue
+	if leaseID == NoLease || (isExisting && oldKeyValue.LeaseID == NoLease) {
+		if err := s.saveLocked(); err != nil {
+			s.restoreLocked(key, oldKeyValue, isExisting)
+			return 0, err
+		}
+	}
+	if isExisting && oldKeyValue.LeaseID != NoLease {
+		if lease, ok := s.leases[oldKeyValue.LeaseID]; ok {
+			delete(lease.keys, key)
+		}
+	}
+	if leaseID != NoLease {
+		s.leases[leaseID].keys[key] = struct{}{}
+	}
+	s.notifyLocked(Event{Type: EventPut, KeyValue: *cloneKeyValue(keyValue)})
+	return keyValue.Version, nil
+}
+
+// deleteLocked deletes the key, persisting the deletion unless it was attached to a lease.
+func (s *LocalStore) deleteLocked(key string) error {
+	oldKeyValue, ok := s.keyValues[key]
+	if !ok {
+		return nil
+	}
+	delete(s.keyValues, key)
+	if oldKeyValue.LeaseID == NoLease {
+		if err := s.saveLocked(); err != nil {
+			s.restoreLocked(key, oldKeyValue, true)
+			return err
+		}
+	} else if lease, ok := s.leases[oldKeyValue.LeaseID]; ok {
+		delete(lease.keys, key)
+	}
+	s.notifyLocked(Event{Type: EventDelete, KeyValue: KeyValue{Key: key}})
+	return nil
+}
+
+// restoreLocked restores the key after its change failed to be persisted.
+func (s *LocalStore) restoreLocked(key string, oldKeyValue *KeyValue, isExisting bool) {
+	if isExisting {
+		s.keyValues[key] = oldKeyValue
+	} else {
+		delete(s.keyValues, key)
+	}
+}
+
+// revokeLeaseLocked deletes the lease and the keys attached to it.
+func (s *LocalStore) revokeLeaseLocked(leaseID LeaseID) error {
+	lease := s.leases[leaseID]
+	lease.timer.Stop()
+	delete(s.leases, leaseID)
+	keys := make([]string, 0, len(lease.keys))
+	for key := range lease.keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := s.deleteLocked(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyLocked queues the event to the watchers of its key.
+func (s *LocalStore) notifyLocked(event Event) {
+	for watcher := range s.watchers {
+		if !strings.HasPrefix(event.Key, watcher.prefix) {
+			continue
+		}
+		watcher.lock.Lock()
+		watcher.pending = append(watcher.pending, event)
+		watcher.lock.Unlock()
+		select {
+		case watcher.notifyCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// saveLocked persists the keys which are not attached to a lease, if the LocalStore has a file.
+func (s *LocalStore) saveLocked() error {
+	if s.filePath == "" {
+		return nil
+	}
+	persisted := make([]persistedKeyValue, 0, len(s.keyValues))
+	for _, keyValue := range s.keyValues {
+		if keyValue.LeaseID == NoLease {
+			persisted = append(persisted, persistedKeyValue{Key: keyValue.Key, Value: keyValue.Value, Version: keyValue.Version})
+		}
+	}
+	sort.Slice(persisted, func(i, j int) bool { return persisted[i].Key < persisted[j].Key })
+	data, err := json.Marshal(persisted)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomically(s.filePath, data)
+}
+
+// writeFileAtomically replaces the file with data through a temporary file, so that a crash leaves either the old or
+// the new content.
+func writeFileAtomically(filePath string, data []byte) error {
+	tempFilePath := filePath + ".tmp"
+	file, err := os.Create(tempFilePath)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tempFilePath, filePath); err != nil {
+		return err
+	}
+
+	dir, err := os.Open(path.Dir(filePath))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
+
+// cloneKeyValue returns a copy of the key, which does not share its value.
+func cloneKeyValue(keyValue *KeyValue) *KeyValue {
+	cloned := *keyValue
+	cloned.Value = slices.Clone(keyValue.Value)
+	return &cloned
+}
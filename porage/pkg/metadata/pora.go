@@ -0,0 +1,49 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// PoraKeyPrefix is the prefix of the keys of the registered Poras, which are followed by their addresses.
+const PoraKeyPrefix = "/poras/"
+
+// PoraKey returns the key of the registration of the Pora at addr.
+func PoraKey(addr string) string {
+	return PoraKeyPrefix + addr
+}
+
+// PoraInfo is the registration of a Pora with its load. It is put by the Pora under a lease, which the Pora renews
+// periodically with its current load, so the registration is deleted once the Pora stops or fails to renew it.
+type PoraInfo struct {
+	// Addr is the gRPC address of the Pora.
+	Addr string `json:"addr"`
+	// Capacity is the storage capacity (in bytes) of the Pora.
+	Capacity int64 `json:"capacity"`
+	// DiskUsage is the size (in bytes) of the files stored by the Pora.
+	DiskUsage int64 `json:"disk_usage"`
+	// LedgerCount is the number of the ledgers on the Pora.
+	LedgerCount int `json:"ledger_count"`
+	// WriteThroughput is the payload size (in bytes per second) appended to the Pora since the last renewal.
+	WriteThroughput float64 `json:"write_throughput"`
+	// UpdateTime is the time of the last renewal.
+	UpdateTime time.Time `json:"update_time"`
+}
+
+// ListPoras returns the Poras registered in store in ascending order of address.
+func ListPoras(ctx context.Context, store MetadataStore) ([]*PoraInfo, error) {
+	keyValues, err := store.List(ctx, PoraKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+	poras := make([]*PoraInfo, 0, len(keyValues))
+	for _, keyValue := range keyValues {
+		pora := &PoraInfo{}
+		if err := json.Unmarshal(keyValue.Value, pora); err != nil {
+			return nil, err
+		}
+		poras = append(poras, pora)
+	}
+	return poras, nil
+}
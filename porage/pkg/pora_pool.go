@@ -0,0 +1,57 @@
+package pkg
+
+import (
+	"slices"
+	"sync"
+)
+
+// PoraPool is a set of Poras with their clients, from which the ensembles of the DistributedLedgers are chosen. The
+// clients are created when they are first used and shared by the ledgers.
+type PoraPool struct {
+	addrs []string
+
+	lock    sync.Mutex
+	clients map[string]*PorageClient
+}
+
+// NewPoraPool creates a PoraPool of the Poras at addrs. The Poras are chosen for the ensembles in the order of addrs.
+func NewPoraPool(addrs []string) *PoraPool {
+	return &PoraPool{
+		addrs:   slices.Clone(addrs),
+		clients: make(map[string]*PorageClient),
+	}
+}
+
+// Addrs returns the addresses of the Poras in the pool.
+func (p *PoraPool) Addrs() []string {
+	return slices.Clone(p.addrs)
+}
+
+// Client returns the client of the Pora at addr, which does not have to be in the pool.
+func (p *PoraPool) Client(addr string) (*PorageClient, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if client, ok := p.clients[addr]; ok {
+		return client, nil
+	}
+	client, err := NewPorageClient(addr)
+	if err != nil {
+		return nil, err
+	}
+	p.clients[addr] = client
+	return client, nil
+}
+
+// Close closes the clients of the pool.
+func (p *PoraPool) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	var err error
+	for addr, client := range p.clients {
+		if closeErr := client.Close(); closeErr != nil {
+			err = closeErr
+		}
+		delete(p.clients, addr)
+	}
+	return err
+}
@@ -2,13 +2,141 @@ package pkg
 
 import (
 	"context"
+	"io"
 	pb "porage/proto"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
+// LedgerEntry is an entry in a ledger.
+type LedgerEntry struct {
+	EntryID int
+	Payload []byte
+}
+
+// RetentionPolicy limits the entries kept in a ledger. The oldest entries beyond any of the limits are trimmed by
+// Pora, and a closed ledger is deleted once all its entries are beyond the limits. A zero limit means no limit.
+type RetentionPolicy struct {
+	// MaxAge is how long an entry is kept after it is appended. It is rounded down to seconds.
+	MaxAge time.Duration
+	// MaxBytes is the total payload size of the entries kept.
+	MaxBytes int64
+	// MaxEntries is the number of the entries kept.
+	MaxEntries int64
+}
+
+// CreateLedgerOptions are the options of a new ledger.
+type CreateLedgerOptions struct {
+	// RetentionPolicy is the retention policy of the ledger. The zero value means no retention policy.
+	RetentionPolicy RetentionPolicy
+	// Properties are the custom properties of the ledger, such as its owner or purpose.
+	Properties map[string]string
+}
+
+// LedgerState is the state of a ledger.
+type LedgerState int
+
+const (
+	// LedgerStateOpen is the state of a ledger that accepts appends.
+	LedgerStateOpen LedgerState = iota
+	// LedgerStateFenced is the state of a ledger that rejects appends because it is fenced.
+	LedgerStateFenced
+	// LedgerStateClosed is the state of a ledger that is closed. It can only be read.
+	LedgerStateClosed
+)
+
+func (s LedgerState) String() string {
+	switch s {
+	case LedgerStateOpen:
+		return "open"
+	case LedgerStateFenced:
+		return "fenced"
+	case LedgerStateClosed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// LedgerInfo is the metadata of a ledger. The entries are counted when they are flushed by the Pora.
+type LedgerInfo struct {
+	CreationTime    time.Time
+	State           LedgerState
+	Properties      map[string]string
+	RetentionPolicy RetentionPolicy
+	// EntryCount is the number of the entries which are not trimmed.
+	EntryCount int64
+	// ByteSize is the total payload size of the entries which are not trimmed.
+	ByteSize int64
+	// LastAppendTime is the time at which the last entry is appended. It is zero if there is no entry.
+	LastAppendTime time.Time
+	// FirstEntryID is the first entry which can be read. It is -1 if there is no entry.
+	FirstEntryID int
+}
+
+// WorkerStatus is the description and the progress of a worker of Pora.
+type WorkerStatus struct {
+	Description string
+	// LastHeartbeat is the last time the worker handled its channels.
+	LastHeartbeat time.Time
+	// Processed is the number of items processed by the worker. What an item is depends on the worker.
+	Processed uint64
+	// QueueDepth is the number of messages waiting in the channel of the worker.
+	QueueDepth int
+	// Stuck is true if the worker has made no progress within the watchdog window of Pora.
+	Stuck bool
+}
+
+// LedgerTailer reads the entries of a ledger as they are appended.
+type LedgerTailer struct {
+	stream  pb.PorageService_TailLedgerClient
+	entries []*LedgerEntry
+}
+
+// Next returns the next entry of the ledger, blocking until it is appended. io.EOF is returned once the ledger is
+// closed and all its entries are returned.
+func (t *LedgerTailer) Next() (*LedgerEntry, error) {
+	for len(t.entries) == 0 {
+		response, err := t.stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		t.entries = fromPbLedgerEntries(response.GetEntries())
+	}
+	entry := t.entries[0]
+	t.entries = t.entries[1:]
+	return entry, nil
+}
+
+// EntryAppender appends batches of entries to ledgers in a stream. The batches can be sent without waiting for
+// the responses of the previous ones, and the entry IDs of the batches are received in the order they are sent.
+type EntryAppender struct {
+	stream pb.PorageService_AppendEntriesStreamClient
+}
+
+// Send sends a batch of entries to be appended to a ledger.
+func (a *EntryAppender) Send(ledgerID uint64, payloads [][]byte) error {
+	return a.stream.Send(&pb.AppendEntriesOnLedgerRequest{LedgerId: ledgerID, Payloads: payloads})
+}
+
+// Recv returns the entry IDs assigned to the next batch sent, blocking until the batch is committed. io.EOF is
+// returned once all the batches are received after CloseSend.
+func (a *EntryAppender) Recv() ([]int, error) {
+	response, err := a.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	return fromPbEntryIDs(response.GetEntryIds()), nil
+}
+
+// CloseSend tells the server that no more batches will be sent.
+func (a *EntryAppender) CloseSend() error {
+	return a.stream.CloseSend()
+}
+
 // PorageClient is the client struct for Porage.
 type PorageClient struct {
 	connection *grpc.ClientConn
@@ -20,6 +148,8 @@ func NewPorageClient(serverAddr string) (*PorageClient, error) {
 	var opts []grpc.DialOption
 
 	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	opts = append(opts, grpc.WithChainUnaryInterceptor(unaryErrorInterceptor))
+	opts = append(opts, grpc.WithChainStreamInterceptor(streamErrorInterceptor))
 	conn, err := grpc.NewClient(serverAddr, opts...)
 	if err != nil {
 		return nil, err
@@ -43,19 +173,106 @@ func (c *PorageClient) CreateLedger(ctx context.Context, ledgerID uint64) error
 	return err
 }
 
+// CreateLedgerWithOptions creates a new ledger with a retention policy and custom properties.
+func (c *PorageClient) CreateLedgerWithOptions(ctx context.Context, ledgerID uint64, options CreateLedgerOptions) error {
+	request := &pb.CreateLedgerRequest{
+		LedgerId:        ledgerID,
+		RetentionPolicy: toPbRetentionPolicy(options.RetentionPolicy),
+		Properties:      options.Properties,
+	}
+	_, err := c.rpcClient.CreateLedger(ctx, request)
+	return err
+}
+
 // AppendEntryOnLedger appends an entry to a ledger.
+//
+// If the deadline of ctx is exceeded or ctx is canceled after the entry is accepted by the Pora, the returned error
+// is a *DurabilityUnknownError carrying the entry ID, and the entry may or may not be persisted. An error with
+// codes.DeadlineExceeded or codes.Canceled but without the entry ID means that the Pora did not report the result in
+// time, so the durability of the entry is unknown as well.
 func (c *PorageClient) AppendEntryOnLedger(ctx context.Context, ledgerID uint64, payload []byte) (int, error) {
 	response, err := c.rpcClient.AppendEntryOnLedger(ctx, &pb.AppendEntryOnLedgerRequest{LedgerId: ledgerID, Payload: payload})
 	return int(response.GetEntryId()), err
 }
 
+// AppendEntriesOnLedger appends a batch of entries to a ledger with a single journal commit. The entry IDs assigned
+// to the entries are returned in the order of the payloads.
+// The deadline and the cancellation of ctx are handled in the same way as AppendEntryOnLedger.
+func (c *PorageClient) AppendEntriesOnLedger(ctx context.Context, ledgerID uint64, payloads [][]byte) ([]int, error) {
+	response, err := c.rpcClient.AppendEntriesOnLedger(ctx, &pb.AppendEntriesOnLedgerRequest{LedgerId: ledgerID, Payloads: payloads})
+	if err != nil {
+		return nil, err
+	}
+	return fromPbEntryIDs(response.GetEntryIds()), nil
+}
+
+// AppendEntriesStream starts a stream to append batches of entries. The stream stops when ctx is done.
+func (c *PorageClient) AppendEntriesStream(ctx context.Context) (*EntryAppender, error) {
+	stream, err := c.rpcClient.AppendEntriesStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &EntryAppender{stream: stream}, nil
+}
+
+// AppendEntryWithID appends an entry with the given entry ID to a ledger. It is safe to retry: appending the same
+// entry again succeeds once the entry is persisted, while appending a different payload with an existing entry ID
+// fails.
+func (c *PorageClient) AppendEntryWithID(ctx context.Context, ledgerID uint64, entryID int, payload []byte) error {
+	_, err := c.rpcClient.AppendEntryWithID(ctx, &pb.AppendEntryWithIDRequest{LedgerId: ledgerID, EntryId: int64(entryID), Payload: payload})
+	return err
+}
+
 // GetEntryFromLedger gets an entry from a ledger.
 func (c *PorageClient) GetEntryFromLedger(ctx context.Context, ledgerID uint64, entryID int) ([]byte, error) {
 	response, err := c.rpcClient.GetEntryFromLedger(ctx, &pb.GetEntryFromLedgerRequest{LedgerId: ledgerID, EntryId: int64(entryID)})
 	return response.GetPayload(), err
 }
 
-// DeleteLedger closes a ledger.
+// ReadEntries reads the entries in [fromEntryID, toEntryID] of a ledger in ascending order of entry ID. Missing
+// entries are skipped. The total payload size of the entries does not exceed maxBytes unless maxBytes is not
+// positive, except that the first entry is always returned.
+func (c *PorageClient) ReadEntries(ctx context.Context, ledgerID uint64, fromEntryID int, toEntryID int, maxBytes int) ([]*LedgerEntry, error) {
+	stream, err := c.rpcClient.ReadEntries(ctx, &pb.ReadEntriesRequest{
+		LedgerId:    ledgerID,
+		FromEntryId: int64(fromEntryID),
+		ToEntryId:   int64(toEntryID),
+		MaxBytes:    int64(maxBytes),
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*LedgerEntry, 0)
+	for {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fromPbLedgerEntries(response.GetEntries())...)
+	}
+}
+
+// TailLedger starts a tailing read of a ledger from fromEntryID. The entries are returned by the LedgerTailer once
+// their journal commits complete. The tailing read stops when ctx is done.
+func (c *PorageClient) TailLedger(ctx context.Context, ledgerID uint64, fromEntryID int) (*LedgerTailer, error) {
+	stream, err := c.rpcClient.TailLedger(ctx, &pb.TailLedgerRequest{LedgerId: ledgerID, FromEntryId: int64(fromEntryID)})
+	if err != nil {
+		return nil, err
+	}
+	return &LedgerTailer{stream: stream}, nil
+}
+
+// CloseLedger seals a ledger so that it can only be read. The last entry ID persisted in the ledger is returned,
+// which is -1 if there is no entry.
+func (c *PorageClient) CloseLedger(ctx context.Context, ledgerID uint64) (int, error) {
+	response, err := c.rpcClient.CloseLedger(ctx, &pb.CloseLedgerRequest{LedgerId: ledgerID})
+	return int(response.GetLastEntryId()), err
+}
+
+// DeleteLedger deletes a ledger with all its entries.
 func (c *PorageClient) DeleteLedger(ctx context.Context, ledgerID uint64) error {
 	_, err := c.rpcClient.DeleteLedger(ctx, &pb.DeleteLedgerRequest{LedgerId: ledgerID})
 	return err
@@ -80,8 +297,118 @@ func (c *PorageClient) GetWorkerDescriptions(ctx context.Context) (map[string]st
 	return workerDescriptions, nil
 }
 
+// ListWorkers lists the workers of Pora with their progress.
+func (c *PorageClient) ListWorkers(ctx context.Context) (map[string]*WorkerStatus, error) {
+	response, err := c.rpcClient.ListWorkers(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	workerStatuses := make(map[string]*WorkerStatus)
+	for workerName, description := range response.GetWorkers() {
+		workerStatuses[workerName] = &WorkerStatus{
+			Description:   description.GetDescription(),
+			LastHeartbeat: description.GetLastHeartbeat().AsTime(),
+			Processed:     description.GetProcessed(),
+			QueueDepth:    int(description.GetQueueDepth()),
+			Stuck:         description.GetStuck(),
+		}
+	}
+	return workerStatuses, nil
+}
+
 // GetLedgerLength gets the length of a ledger.
 func (c *PorageClient) GetLedgerLength(ctx context.Context, ledgerID uint64) (int, error) {
 	response, err := c.rpcClient.LedgerLength(ctx, &pb.LedgerLengthRequest{LedgerId: ledgerID})
 	return int(response.GetLength()), err
 }
+
+// FenceLedger fences a ledger so that all the following appends are rejected. The last entry ID persisted in the
+// ledger is returned, which is -1 if there is no entry.
+func (c *PorageClient) FenceLedger(ctx context.Context, ledgerID uint64) (int, error) {
+	response, err := c.rpcClient.FenceLedger(ctx, &pb.FenceLedgerRequest{LedgerId: ledgerID})
+	return int(response.GetLastEntryId()), err
+}
+
+// TrimLedger trims the entries of a ledger up to untilEntryID, inclusive. Reading a trimmed entry fails with
+// ErrEntryTrimmed afterwards.
+func (c *PorageClient) TrimLedger(ctx context.Context, ledgerID uint64, untilEntryID int) error {
+	_, err := c.rpcClient.TrimLedger(ctx, &pb.TrimLedgerRequest{LedgerId: ledgerID, UntilEntryId: int64(untilEntryID)})
+	return err
+}
+
+// GetLedgerInfo gets the metadata of a ledger.
+func (c *PorageClient) GetLedgerInfo(ctx context.Context, ledgerID uint64) (*LedgerInfo, error) {
+	response, err := c.rpcClient.GetLedgerInfo(ctx, &pb.GetLedgerInfoRequest{LedgerId: ledgerID})
+	if err != nil {
+		return nil, err
+	}
+	info := &LedgerInfo{
+		CreationTime: response.GetCreationTime().AsTime(),
+		State:        LedgerState(response.GetState()),
+		Properties:   response.GetProperties(),
+		RetentionPolicy: RetentionPolicy{
+			MaxAge:     time.Duration(response.GetRetentionPolicy().GetMaxAgeSeconds()) * time.Second,
+			MaxBytes:   response.GetRetentionPolicy().GetMaxBytes(),
+			MaxEntries: response.GetRetentionPolicy().GetMaxEntries(),
+		},
+		EntryCount:   response.GetEntryCount(),
+		ByteSize:     response.GetByteSize(),
+		FirstEntryID: int(response.GetFirstEntryId()),
+	}
+	if response.GetLastAppendTime() != nil {
+		info.LastAppendTime = response.GetLastAppendTime().AsTime()
+	}
+	return info, nil
+}
+
+// unaryErrorInterceptor converts the errors of the unary calls into the exported errors.
+func unaryErrorInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return fromRPCError(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+// streamErrorInterceptor converts the errors of the streaming calls into the exported errors.
+func streamErrorInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, fromRPCError(err)
+	}
+	return &errorConvertingClientStream{ClientStream: stream}, nil
+}
+
+// errorConvertingClientStream converts the errors of the messages in a stream into the exported errors. io.EOF is
+// returned as it is.
+type errorConvertingClientStream struct {
+	grpc.ClientStream
+}
+
+func (s *errorConvertingClientStream) SendMsg(m any) error {
+	return fromRPCError(s.ClientStream.SendMsg(m))
+}
+
+func (s *errorConvertingClientStream) RecvMsg(m any) error {
+	return fromRPCError(s.ClientStream.RecvMsg(m))
+}
+
+func toPbRetentionPolicy(retentionPolicy RetentionPolicy) *pb.RetentionPolicy {
+	return &pb.RetentionPolicy{
+		MaxAgeSeconds: int64(retentionPolicy.MaxAge / time.Second),
+		MaxBytes:      retentionPolicy.MaxBytes,
+		MaxEntries:    retentionPolicy.MaxEntries,
+	}
+}
+
+func fromPbLedgerEntries(pbEntries []*pb.LedgerEntry) []*LedgerEntry {
+	entries := make([]*LedgerEntry, 0, len(pbEntries))
+	for _, entry := range pbEntries {
+		entries = append(entries, &LedgerEntry{EntryID: int(entry.GetEntryId()), Payload: entry.GetPayload()})
+	}
+	return entries
+}
+
+func fromPbEntryIDs(pbEntryIDs []int64) []int {
+	entryIDs := make([]int, 0, len(pbEntryIDs))
+	for _, entryID := range pbEntryIDs {
+		entryIDs = append(entryIDs, int(entryID))
+	}
+	return entryIDs
+}
@@ -20,13 +20,22 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PorageService_CreateLedger_FullMethodName        = "/porageservice.PorageService/CreateLedger"
-	PorageService_AppendEntryOnLedger_FullMethodName = "/porageservice.PorageService/AppendEntryOnLedger"
-	PorageService_GetEntryFromLedger_FullMethodName  = "/porageservice.PorageService/GetEntryFromLedger"
-	PorageService_DeleteLedger_FullMethodName        = "/porageservice.PorageService/DeleteLedger"
-	PorageService_LedgerLength_FullMethodName        = "/porageservice.PorageService/LedgerLength"
-	PorageService_ListLedgers_FullMethodName         = "/porageservice.PorageService/ListLedgers"
-	PorageService_ListWorkers_FullMethodName         = "/porageservice.PorageService/ListWorkers"
+	PorageService_CreateLedger_FullMethodName          = "/porageservice.PorageService/CreateLedger"
+	PorageService_AppendEntryOnLedger_FullMethodName   = "/porageservice.PorageService/AppendEntryOnLedger"
+	PorageService_AppendEntriesOnLedger_FullMethodName = "/porageservice.PorageService/AppendEntriesOnLedger"
+	PorageService_AppendEntriesStream_FullMethodName   = "/porageservice.PorageService/AppendEntriesStream"
+	PorageService_AppendEntryWithID_FullMethodName     = "/porageservice.PorageService/AppendEntryWithID"
+	PorageService_GetEntryFromLedger_FullMethodName    = "/porageservice.PorageService/GetEntryFromLedger"
+	PorageService_ReadEntries_FullMethodName           = "/porageservice.PorageService/ReadEntries"
+	PorageService_TailLedger_FullMethodName            = "/porageservice.PorageService/TailLedger"
+	PorageService_CloseLedger_FullMethodName           = "/porageservice.PorageService/CloseLedger"
+	PorageService_DeleteLedger_FullMethodName          = "/porageservice.PorageService/DeleteLedger"
+	PorageService_LedgerLength_FullMethodName          = "/porageservice.PorageService/LedgerLength"
+	PorageService_ListLedgers_FullMethodName           = "/porageservice.PorageService/ListLedgers"
+	PorageService_ListWorkers_FullMethodName           = "/porageservice.PorageService/ListWorkers"
+	PorageService_FenceLedger_FullMethodName           = "/porageservice.PorageService/FenceLedger"
+	PorageService_TrimLedger_FullMethodName            = "/porageservice.PorageService/TrimLedger"
+	PorageService_GetLedgerInfo_FullMethodName         = "/porageservice.PorageService/GetLedgerInfo"
 )
 
 // PorageServiceClient is the client API for PorageService service.
@@ -37,9 +46,28 @@ type PorageServiceClient interface {
 	CreateLedger(ctx context.Context, in *CreateLedgerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// AppendEntryOnLedger appends an entry to the ledger.
 	AppendEntryOnLedger(ctx context.Context, in *AppendEntryOnLedgerRequest, opts ...grpc.CallOption) (*AppendEntryOnLedgerResponse, error)
+	// AppendEntriesOnLedger appends a batch of entries to the ledger. The entries are committed by the journal together
+	// and the entry IDs assigned to them are returned in the order of the payloads.
+	AppendEntriesOnLedger(ctx context.Context, in *AppendEntriesOnLedgerRequest, opts ...grpc.CallOption) (*AppendEntriesOnLedgerResponse, error)
+	// AppendEntriesStream appends the batches of entries sent in the stream. The batches are appended in the order
+	// they are sent, and a response with the assigned entry IDs is returned for each batch in the same order, so the
+	// client can send the following batches without waiting for the responses.
+	AppendEntriesStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AppendEntriesOnLedgerRequest, AppendEntriesOnLedgerResponse], error)
+	// AppendEntryWithID appends an entry with the entry ID assigned by the client to the ledger. Appending the same
+	// entry again has no effect, while appending a different payload with an existing entry ID fails.
+	AppendEntryWithID(ctx context.Context, in *AppendEntryWithIDRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// GetEntryFromLedger retrieves an entry from the ledger.
 	GetEntryFromLedger(ctx context.Context, in *GetEntryFromLedgerRequest, opts ...grpc.CallOption) (*GetEntryFromLedgerResponse, error)
-	// DeleteLedger closes a ledger.
+	// ReadEntries reads the entries in [from_entry_id, to_entry_id] of the ledger in ascending order of entry ID.
+	// Missing entries are skipped. The total payload size of the entries does not exceed max_bytes unless max_bytes
+	// is not positive, except that the first entry is always returned.
+	ReadEntries(ctx context.Context, in *ReadEntriesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReadEntriesResponse], error)
+	// TailLedger streams the entries of the ledger from from_entry_id once their journal commits complete. The
+	// stream ends when the ledger is closed and all its entries are streamed, and fails when the ledger is deleted.
+	TailLedger(ctx context.Context, in *TailLedgerRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TailLedgerResponse], error)
+	// CloseLedger seals a ledger so that it can only be read.
+	CloseLedger(ctx context.Context, in *CloseLedgerRequest, opts ...grpc.CallOption) (*CloseLedgerResponse, error)
+	// DeleteLedger deletes a ledger with all its entries.
 	DeleteLedger(ctx context.Context, in *DeleteLedgerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// LedgerLength returns the length of the ledger.
 	LedgerLength(ctx context.Context, in *LedgerLengthRequest, opts ...grpc.CallOption) (*LedgerLengthResponse, error)
@@ -47,6 +75,12 @@ type PorageServiceClient interface {
 	ListLedgers(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListLedgersResponse, error)
 	// ListWorkers lists all the workers.
 	ListWorkers(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListWorkersResponse, error)
+	// FenceLedger fences a ledger so that all the following appends are rejected.
+	FenceLedger(ctx context.Context, in *FenceLedgerRequest, opts ...grpc.CallOption) (*FenceLedgerResponse, error)
+	// TrimLedger trims the entries of a ledger up to an entry ID, so that they can not be read any more.
+	TrimLedger(ctx context.Context, in *TrimLedgerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetLedgerInfo returns the metadata of a ledger with its custom properties.
+	GetLedgerInfo(ctx context.Context, in *GetLedgerInfoRequest, opts ...grpc.CallOption) (*GetLedgerInfoResponse, error)
 }
 
 type porageServiceClient struct {
@@ -77,6 +111,39 @@ func (c *porageServiceClient) AppendEntryOnLedger(ctx context.Context, in *Appen
 	return out, nil
 }
 
+func (c *porageServiceClient) AppendEntriesOnLedger(ctx context.Context, in *AppendEntriesOnLedgerRequest, opts ...grpc.CallOption) (*AppendEntriesOnLedgerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AppendEntriesOnLedgerResponse)
+	err := c.cc.Invoke(ctx, PorageService_AppendEntriesOnLedger_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *porageServiceClient) AppendEntriesStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[AppendEntriesOnLedgerRequest, AppendEntriesOnLedgerResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PorageService_ServiceDesc.Streams[0], PorageService_AppendEntriesStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AppendEntriesOnLedgerRequest, AppendEntriesOnLedgerResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PorageService_AppendEntriesStreamClient = grpc.BidiStreamingClient[AppendEntriesOnLedgerRequest, AppendEntriesOnLedgerResponse]
+
+func (c *porageServiceClient) AppendEntryWithID(ctx context.Context, in *AppendEntryWithIDRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PorageService_AppendEntryWithID_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *porageServiceClient) GetEntryFromLedger(ctx context.Context, in *GetEntryFromLedgerRequest, opts ...grpc.CallOption) (*GetEntryFromLedgerResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetEntryFromLedgerResponse)
@@ -87,6 +154,54 @@ func (c *porageServiceClient) GetEntryFromLedger(ctx context.Context, in *GetEnt
 	return out, nil
 }
 
+func (c *porageServiceClient) ReadEntries(ctx context.Context, in *ReadEntriesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReadEntriesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PorageService_ServiceDesc.Streams[1], PorageService_ReadEntries_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ReadEntriesRequest, ReadEntriesResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PorageService_ReadEntriesClient = grpc.ServerStreamingClient[ReadEntriesResponse]
+
+func (c *porageServiceClient) TailLedger(ctx context.Context, in *TailLedgerRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TailLedgerResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PorageService_ServiceDesc.Streams[2], PorageService_TailLedger_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TailLedgerRequest, TailLedgerResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PorageService_TailLedgerClient = grpc.ServerStreamingClient[TailLedgerResponse]
+
+func (c *porageServiceClient) CloseLedger(ctx context.Context, in *CloseLedgerRequest, opts ...grpc.CallOption) (*CloseLedgerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloseLedgerResponse)
+	err := c.cc.Invoke(ctx, PorageService_CloseLedger_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *porageServiceClient) DeleteLedger(ctx context.Context, in *DeleteLedgerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -127,6 +242,36 @@ func (c *porageServiceClient) ListWorkers(ctx context.Context, in *emptypb.Empty
 	return out, nil
 }
 
+func (c *porageServiceClient) FenceLedger(ctx context.Context, in *FenceLedgerRequest, opts ...grpc.CallOption) (*FenceLedgerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FenceLedgerResponse)
+	err := c.cc.Invoke(ctx, PorageService_FenceLedger_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *porageServiceClient) TrimLedger(ctx context.Context, in *TrimLedgerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, PorageService_TrimLedger_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *porageServiceClient) GetLedgerInfo(ctx context.Context, in *GetLedgerInfoRequest, opts ...grpc.CallOption) (*GetLedgerInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLedgerInfoResponse)
+	err := c.cc.Invoke(ctx, PorageService_GetLedgerInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PorageServiceServer is the server API for PorageService service.
 // All implementations must embed UnimplementedPorageServiceServer
 // for forward compatibility.
@@ -135,9 +280,28 @@ type PorageServiceServer interface {
 	CreateLedger(context.Context, *CreateLedgerRequest) (*emptypb.Empty, error)
 	// AppendEntryOnLedger appends an entry to the ledger.
 	AppendEntryOnLedger(context.Context, *AppendEntryOnLedgerRequest) (*AppendEntryOnLedgerResponse, error)
+	// AppendEntriesOnLedger appends a batch of entries to the ledger. The entries are committed by the journal together
+	// and the entry IDs assigned to them are returned in the order of the payloads.
+	AppendEntriesOnLedger(context.Context, *AppendEntriesOnLedgerRequest) (*AppendEntriesOnLedgerResponse, error)
+	// AppendEntriesStream appends the batches of entries sent in the stream. The batches are appended in the order
+	// they are sent, and a response with the assigned entry IDs is returned for each batch in the same order, so the
+	// client can send the following batches without waiting for the responses.
+	AppendEntriesStream(grpc.BidiStreamingServer[AppendEntriesOnLedgerRequest, AppendEntriesOnLedgerResponse]) error
+	// AppendEntryWithID appends an entry with the entry ID assigned by the client to the ledger. Appending the same
+	// entry again has no effect, while appending a different payload with an existing entry ID fails.
+	AppendEntryWithID(context.Context, *AppendEntryWithIDRequest) (*emptypb.Empty, error)
 	// GetEntryFromLedger retrieves an entry from the ledger.
 	GetEntryFromLedger(context.Context, *GetEntryFromLedgerRequest) (*GetEntryFromLedgerResponse, error)
-	// DeleteLedger closes a ledger.
+	// ReadEntries reads the entries in [from_entry_id, to_entry_id] of the ledger in ascending order of entry ID.
+	// Missing entries are skipped. The total payload size of the entries does not exceed max_bytes unless max_bytes
+	// is not positive, except that the first entry is always returned.
+	ReadEntries(*ReadEntriesRequest, grpc.ServerStreamingServer[ReadEntriesResponse]) error
+	// TailLedger streams the entries of the ledger from from_entry_id once their journal commits complete. The
+	// stream ends when the ledger is closed and all its entries are streamed, and fails when the ledger is deleted.
+	TailLedger(*TailLedgerRequest, grpc.ServerStreamingServer[TailLedgerResponse]) error
+	// CloseLedger seals a ledger so that it can only be read.
+	CloseLedger(context.Context, *CloseLedgerRequest) (*CloseLedgerResponse, error)
+	// DeleteLedger deletes a ledger with all its entries.
 	DeleteLedger(context.Context, *DeleteLedgerRequest) (*emptypb.Empty, error)
 	// LedgerLength returns the length of the ledger.
 	LedgerLength(context.Context, *LedgerLengthRequest) (*LedgerLengthResponse, error)
@@ -145,6 +309,12 @@ type PorageServiceServer interface {
 	ListLedgers(context.Context, *emptypb.Empty) (*ListLedgersResponse, error)
 	// ListWorkers lists all the workers.
 	ListWorkers(context.Context, *emptypb.Empty) (*ListWorkersResponse, error)
+	// FenceLedger fences a ledger so that all the following appends are rejected.
+	FenceLedger(context.Context, *FenceLedgerRequest) (*FenceLedgerResponse, error)
+	// TrimLedger trims the entries of a ledger up to an entry ID, so that they can not be read any more.
+	TrimLedger(context.Context, *TrimLedgerRequest) (*emptypb.Empty, error)
+	// GetLedgerInfo returns the metadata of a ledger with its custom properties.
+	GetLedgerInfo(context.Context, *GetLedgerInfoRequest) (*GetLedgerInfoResponse, error)
 	mustEmbedUnimplementedPorageServiceServer()
 }
 
@@ -161,9 +331,27 @@ func (UnimplementedPorageServiceServer) CreateLedger(context.Context, *CreateLed
 func (UnimplementedPorageServiceServer) AppendEntryOnLedger(context.Context, *AppendEntryOnLedgerRequest) (*AppendEntryOnLedgerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AppendEntryOnLedger not implemented")
 }
+func (UnimplementedPorageServiceServer) AppendEntriesOnLedger(context.Context, *AppendEntriesOnLedgerRequest) (*AppendEntriesOnLedgerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AppendEntriesOnLedger not implemented")
+}
+func (UnimplementedPorageServiceServer) AppendEntriesStream(grpc.BidiStreamingServer[AppendEntriesOnLedgerRequest, AppendEntriesOnLedgerResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method AppendEntriesStream not implemented")
+}
+func (UnimplementedPorageServiceServer) AppendEntryWithID(context.Context, *AppendEntryWithIDRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AppendEntryWithID not implemented")
+}
 func (UnimplementedPorageServiceServer) GetEntryFromLedger(context.Context, *GetEntryFromLedgerRequest) (*GetEntryFromLedgerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetEntryFromLedger not implemented")
 }
+func (UnimplementedPorageServiceServer) ReadEntries(*ReadEntriesRequest, grpc.ServerStreamingServer[ReadEntriesResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ReadEntries not implemented")
+}
+func (UnimplementedPorageServiceServer) TailLedger(*TailLedgerRequest, grpc.ServerStreamingServer[TailLedgerResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method TailLedger not implemented")
+}
+func (UnimplementedPorageServiceServer) CloseLedger(context.Context, *CloseLedgerRequest) (*CloseLedgerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseLedger not implemented")
+}
 func (UnimplementedPorageServiceServer) DeleteLedger(context.Context, *DeleteLedgerRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteLedger not implemented")
 }
@@ -176,6 +364,15 @@ func (UnimplementedPorageServiceServer) ListLedgers(context.Context, *emptypb.Em
 func (UnimplementedPorageServiceServer) ListWorkers(context.Context, *emptypb.Empty) (*ListWorkersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListWorkers not implemented")
 }
+func (UnimplementedPorageServiceServer) FenceLedger(context.Context, *FenceLedgerRequest) (*FenceLedgerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FenceLedger not implemented")
+}
+func (UnimplementedPorageServiceServer) TrimLedger(context.Context, *TrimLedgerRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TrimLedger not implemented")
+}
+func (UnimplementedPorageServiceServer) GetLedgerInfo(context.Context, *GetLedgerInfoRequest) (*GetLedgerInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLedgerInfo not implemented")
+}
 func (UnimplementedPorageServiceServer) mustEmbedUnimplementedPorageServiceServer() {}
 func (UnimplementedPorageServiceServer) testEmbeddedByValue()                       {}
 
@@ -233,6 +430,49 @@ func _PorageService_AppendEntryOnLedger_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PorageService_AppendEntriesOnLedger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppendEntriesOnLedgerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PorageServiceServer).AppendEntriesOnLedger(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PorageService_AppendEntriesOnLedger_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PorageServiceServer).AppendEntriesOnLedger(ctx, req.(*AppendEntriesOnLedgerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PorageService_AppendEntriesStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PorageServiceServer).AppendEntriesStream(&grpc.GenericServerStream[AppendEntriesOnLedgerRequest, AppendEntriesOnLedgerResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PorageService_AppendEntriesStreamServer = grpc.BidiStreamingServer[AppendEntriesOnLedgerRequest, AppendEntriesOnLedgerResponse]
+
+func _PorageService_AppendEntryWithID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AppendEntryWithIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PorageServiceServer).AppendEntryWithID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PorageService_AppendEntryWithID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PorageServiceServer).AppendEntryWithID(ctx, req.(*AppendEntryWithIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PorageService_GetEntryFromLedger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetEntryFromLedgerRequest)
 	if err := dec(in); err != nil {
@@ -251,6 +491,46 @@ func _PorageService_GetEntryFromLedger_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PorageService_ReadEntries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadEntriesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PorageServiceServer).ReadEntries(m, &grpc.GenericServerStream[ReadEntriesRequest, ReadEntriesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PorageService_ReadEntriesServer = grpc.ServerStreamingServer[ReadEntriesResponse]
+
+func _PorageService_TailLedger_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailLedgerRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PorageServiceServer).TailLedger(m, &grpc.GenericServerStream[TailLedgerRequest, TailLedgerResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PorageService_TailLedgerServer = grpc.ServerStreamingServer[TailLedgerResponse]
+
+func _PorageService_CloseLedger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseLedgerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PorageServiceServer).CloseLedger(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PorageService_CloseLedger_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PorageServiceServer).CloseLedger(ctx, req.(*CloseLedgerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PorageService_DeleteLedger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteLedgerRequest)
 	if err := dec(in); err != nil {
@@ -323,6 +603,60 @@ func _PorageService_ListWorkers_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PorageService_FenceLedger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FenceLedgerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PorageServiceServer).FenceLedger(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PorageService_FenceLedger_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PorageServiceServer).FenceLedger(ctx, req.(*FenceLedgerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PorageService_TrimLedger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TrimLedgerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PorageServiceServer).TrimLedger(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PorageService_TrimLedger_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PorageServiceServer).TrimLedger(ctx, req.(*TrimLedgerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PorageService_GetLedgerInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLedgerInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PorageServiceServer).GetLedgerInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PorageService_GetLedgerInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PorageServiceServer).GetLedgerInfo(ctx, req.(*GetLedgerInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PorageService_ServiceDesc is the grpc.ServiceDesc for PorageService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -338,10 +672,22 @@ var PorageService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AppendEntryOnLedger",
 			Handler:    _PorageService_AppendEntryOnLedger_Handler,
 		},
+		{
+			MethodName: "AppendEntriesOnLedger",
+			Handler:    _PorageService_AppendEntriesOnLedger_Handler,
+		},
+		{
+			MethodName: "AppendEntryWithID",
+			Handler:    _PorageService_AppendEntryWithID_Handler,
+		},
 		{
 			MethodName: "GetEntryFromLedger",
 			Handler:    _PorageService_GetEntryFromLedger_Handler,
 		},
+		{
+			MethodName: "CloseLedger",
+			Handler:    _PorageService_CloseLedger_Handler,
+		},
 		{
 			MethodName: "DeleteLedger",
 			Handler:    _PorageService_DeleteLedger_Handler,
@@ -358,7 +704,36 @@ var PorageService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListWorkers",
 			Handler:    _PorageService_ListWorkers_Handler,
 		},
+		{
+			MethodName: "FenceLedger",
+			Handler:    _PorageService_FenceLedger_Handler,
+		},
+		{
+			MethodName: "TrimLedger",
+			Handler:    _PorageService_TrimLedger_Handler,
+		},
+		{
+			MethodName: "GetLedgerInfo",
+			Handler:    _PorageService_GetLedgerInfo_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "AppendEntriesStream",
+			Handler:       _PorageService_AppendEntriesStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ReadEntries",
+			Handler:       _PorageService_ReadEntries_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "TailLedger",
+			Handler:       _PorageService_TailLedger_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "service.proto",
 }
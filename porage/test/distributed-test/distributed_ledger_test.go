@@ -0,0 +1,619 @@
+package distributedtest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	entrylogger "porage/internal/entry_logger"
+	"porage/internal/index"
+	"porage/internal/journal"
+	"porage/internal/ledger"
+	"porage/internal/memtable"
+	"porage/internal/pkg"
+	porage "porage/pkg"
+	"porage/test/utilities"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Test Scenario:
+//  1. Quorum appends on an ensemble of local Poras, confirmed in order of entry ID.
+//  2. Appends acknowledged by the ack quorum without waiting for a slow Pora.
+//  3. Reads falling back across the Poras missing some entries.
+//  4. Appends failing once fewer than the ack quorum of Poras are up, which breaks the ledger.
+//  5. Close, which waits for the pending appends and returns the LastAddConfirmed, and reads from the stored metadata.
+//  6. Striped placement on an ensemble wider than the write quorum, with the reads reassembled across the Poras.
+//  7. Ensemble change replacing a failed Pora by a spare one of the pool, with the entries read across the segments
+//     and the changed metadata reported to the listener.
+//  8. Auto-recovery copying the entries of a lost Pora onto its replacement in closed and open ledgers.
+
+const (
+	ensembleSize    = 3
+	writeQuorumSize = 3
+	ackQuorumSize   = 2
+	dataDir         = "./_data"
+)
+
+// TestMain starts the local storage shared by the local Poras of all the tests.
+func TestMain(m *testing.M) {
+	os.RemoveAll(dataDir)
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	memtable.Startup(&config.Memtable)
+	index.Startup(&config.IndexFile)
+	entrylogger.Startup(&config.EntryLogger)
+	journal.Startup(&config.Journal)
+	ledger.Startup(config)
+	code := m.Run()
+	journal.Stop()
+	ledger.Stop()
+	entrylogger.Stop()
+	index.Stop()
+	os.Exit(code)
+}
+
+func TestCreateDistributedLedger(t *testing.T) {
+	utilities.Logger.Logf("TestCreateDistributedLedger: Start.")
+	const ledgerID = uint64(1)
+	poras, pool := startEnsemble(t, ensembleSize)
+	defer stopEnsemble(poras, pool)
+	ctx := context.Background()
+
+	// Check: the quorum sizes must satisfy 1 <= Qa <= Qw <= E.
+	for _, quorum := range [][3]int{{ensembleSize, ensembleSize, 0}, {ensembleSize, ensembleSize - 1, ensembleSize}, {ensembleSize, ensembleSize + 1, 1}} {
+		_, err := porage.CreateDistributedLedger(ctx, ledgerID, pool, quorum[0], quorum[1], quorum[2])
+		if !errors.Is(err, porage.ErrInvalidQuorum) {
+			t.Fatalf("Expected %v with E=%d, Qw=%d and Qa=%d, got %v.", porage.ErrInvalidQuorum, quorum[0], quorum[1], quorum[2], err)
+		}
+	}
+
+	// Check: the ledger is created on all the Poras.
+	distributedLedger, err := porage.CreateDistributedLedger(ctx, ledgerID, pool, ensembleSize, writeQuorumSize, ackQuorumSize)
+	utilities.Logger.FatalIfErr(err, "Failed to create distributed ledger: %v", err)
+	if lastAddConfirmed := distributedLedger.LastAddConfirmed(); lastAddConfirmed != -1 {
+		t.Fatalf("Expected LastAddConfirmed -1, got %d.", lastAddConfirmed)
+	}
+	if _, err := porage.CreateDistributedLedger(ctx, ledgerID, pool, ensembleSize, writeQuorumSize, ackQuorumSize); !errors.Is(err, porage.ErrLedgerExisted) {
+		t.Fatalf("Expected %v, got %v.", porage.ErrLedgerExisted, err)
+	}
+
+	utilities.Logger.Logf("TestCreateDistributedLedger: %s", color.HiGreenString("PASS"))
+}
+
+func TestDistributedLedgerAppend(t *testing.T) {
+	utilities.Logger.Logf("TestDistributedLedgerAppend: Start.")
+	const ledgerID = uint64(2)
+	const nEntries = 1000
+	const nGoroutines = 10
+	const slowPoraDelay = 2 * time.Second
+	poras, pool := startEnsemble(t, ensembleSize)
+	defer stopEnsemble(poras, pool)
+	ctx := context.Background()
+
+	distributedLedger, err := porage.CreateDistributedLedger(ctx, ledgerID, pool, ensembleSize, writeQuorumSize, ackQuorumSize)
+	utilities.Logger.FatalIfErr(err, "Failed to create distributed ledger: %v", err)
+
+	// Check: the concurrent appends are assigned distinct entry IDs, and are replicated on all the Poras.
+	utilities.Logger.Logf("Testing concurrent appends.")
+	payloads := make(map[int][]byte)
+	payloadsLock := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for goroutineID := 0; goroutineID < nGoroutines; goroutineID++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < nEntries/nGoroutines; i++ {
+				payload := []byte(fmt.Sprintf("Goroutine %d, Entry %d", goroutineID, i))
+				entryID, err := distributedLedger.Append(ctx, payload)
+				utilities.Logger.FatalIfErr(err, "Failed to append entry: %v", err)
+				if lastAddConfirmed := distributedLedger.LastAddConfirmed(); lastAddConfirmed < entryID {
+					panic(fmt.Sprintf("Expected LastAddConfirmed at least %d, got %d.", entryID, lastAddConfirmed))
+				}
+				payloadsLock.Lock()
+				payloads[entryID] = payload
+				payloadsLock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(payloads) != nEntries || distributedLedger.LastAddConfirmed() != nEntries-1 {
+		t.Fatalf("Expected %d entries confirmed, got %d entries with LastAddConfirmed %d.", nEntries, len(payloads), distributedLedger.LastAddConfirmed())
+	}
+	expectDistributedLedgerEntries(t, distributedLedger, payloads)
+	for poraID, pora := range poras {
+		if entryIDs := pora.EntryIDs(ledgerID); len(entryIDs) != nEntries {
+			t.Fatalf("Expected %d entries on Pora %d, got %d.", nEntries, poraID, len(entryIDs))
+		}
+	}
+
+	// Check: the appends are acknowledged without waiting for a slow Pora.
+	utilities.Logger.Logf("Testing appends with a slow Pora.")
+	poras[0].SetAppendDelay(slowPoraDelay)
+	start := time.Now()
+	entryID, err := distributedLedger.Append(ctx, []byte("Slow Pora"))
+	utilities.Logger.FatalIfErr(err, "Failed to append entry: %v", err)
+	if elapsed := time.Since(start); elapsed >= slowPoraDelay {
+		t.Fatalf("Expected the append to be acknowledged before the slow Pora, took %v.", elapsed)
+	}
+	if entryID != nEntries {
+		t.Fatalf("Expected entry ID %d, got %d.", nEntries, entryID)
+	}
+
+	utilities.Logger.Logf("TestDistributedLedgerAppend: %s", color.HiGreenString("PASS"))
+}
+
+func TestDistributedLedgerFailure(t *testing.T) {
+	utilities.Logger.Logf("TestDistributedLedgerFailure: Start.")
+	const ledgerID = uint64(3)
+	const nEntriesPerRound = 100
+	const retriesOverDelay = time.Second
+	poras, pool := startEnsemble(t, ensembleSize)
+	defer stopEnsemble(poras, pool)
+	ctx := context.Background()
+
+	distributedLedger, err := porage.CreateDistributedLedger(ctx, ledgerID, pool, ensembleSize, writeQuorumSize, ackQuorumSize)
+	utilities.Logger.FatalIfErr(err, "Failed to create distributed ledger: %v", err)
+
+	// Check: the entries are confirmed with one Pora down in each round, and are read from the Poras holding them.
+	utilities.Logger.Logf("Testing appends and reads with one Pora down.")
+	payloads := make(map[int][]byte)
+	for downPoraID := range poras {
+		poras[downPoraID].SetDown(true)
+		for i := 0; i < nEntriesPerRound; i++ {
+			payload := []byte(fmt.Sprintf("Pora %d down, Entry %d", downPoraID, i))
+			entryID, err := distributedLedger.Append(ctx, payload)
+			utilities.Logger.FatalIfErr(err, "Failed to append entry: %v", err)
+			payloads[entryID] = payload
+		}
+		// Wait until the retries to the down Pora are over, as there is no other Pora to replace it.
+		time.Sleep(retriesOverDelay)
+		poras[downPoraID].SetDown(false)
+		if entryIDs := poras[downPoraID].EntryIDs(ledgerID); len(entryIDs) != len(payloads)-nEntriesPerRound {
+			t.Fatalf("Expected the entries of the round to be missing on Pora %d, got %d entries.", downPoraID, len(entryIDs))
+		}
+	}
+	expectDistributedLedgerEntries(t, distributedLedger, payloads)
+	poras[1].SetDown(true)
+	expectDistributedLedgerEntries(t, distributedLedger, payloads)
+	poras[1].SetDown(false)
+
+	// Check: an append fails without the ack quorum, after which the ledger is broken.
+	utilities.Logger.Logf("Testing appends without the ack quorum.")
+	lastAddConfirmed := distributedLedger.LastAddConfirmed()
+	poras[0].SetDown(true)
+	poras[1].SetDown(true)
+	if _, err := distributedLedger.Append(ctx, []byte("No quorum")); !errors.Is(err, porage.ErrNotEnoughReplicas) {
+		t.Fatalf("Expected %v, got %v.", porage.ErrNotEnoughReplicas, err)
+	}
+	poras[0].SetDown(false)
+	poras[1].SetDown(false)
+	if _, err := distributedLedger.Append(ctx, []byte("Broken")); !errors.Is(err, porage.ErrNotEnoughReplicas) {
+		t.Fatalf("Expected %v after the ledger is broken, got %v.", porage.ErrNotEnoughReplicas, err)
+	}
+	if distributedLedger.LastAddConfirmed() != lastAddConfirmed {
+		t.Fatalf("Expected LastAddConfirmed %d, got %d.", lastAddConfirmed, distributedLedger.LastAddConfirmed())
+	}
+	if _, err := distributedLedger.Read(ctx, lastAddConfirmed+1); !errors.Is(err, porage.ErrEntryNotFound) {
+		t.Fatalf("Expected %v after LastAddConfirmed, got %v.", porage.ErrEntryNotFound, err)
+	}
+
+	// Check: the broken ledger can still be closed and read.
+	closedLastAddConfirmed, err := distributedLedger.Close(ctx)
+	utilities.Logger.FatalIfErr(err, "Failed to close distributed ledger: %v", err)
+	if closedLastAddConfirmed != lastAddConfirmed {
+		t.Fatalf("Expected LastAddConfirmed %d, got %d.", lastAddConfirmed, closedLastAddConfirmed)
+	}
+	expectDistributedLedgerEntries(t, distributedLedger, payloads)
+
+	utilities.Logger.Logf("TestDistributedLedgerFailure: %s", color.HiGreenString("PASS"))
+}
+
+func TestDistributedLedgerClose(t *testing.T) {
+	utilities.Logger.Logf("TestDistributedLedgerClose: Start.")
+	const ledgerID = uint64(4)
+	const nEntries = 100
+	const slowPoraDelay = 500 * time.Millisecond
+	poras, pool := startEnsemble(t, ensembleSize)
+	defer stopEnsemble(poras, pool)
+	ctx := context.Background()
+
+	distributedLedger, err := porage.CreateDistributedLedger(ctx, ledgerID, pool, ensembleSize, writeQuorumSize, ackQuorumSize)
+	utilities.Logger.FatalIfErr(err, "Failed to create distributed ledger: %v", err)
+	storedMetadata := newMetadataStorage(distributedLedger)
+
+	// Check: Close waits for the pending appends, which are all confirmed.
+	utilities.Logger.Logf("Testing close with pending appends.")
+	poras[0].SetAppendDelay(slowPoraDelay)
+	poras[1].SetAppendDelay(slowPoraDelay)
+	wg := sync.WaitGroup{}
+	for i := 0; i < nEntries; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := distributedLedger.Append(ctx, []byte(fmt.Sprintf("Entry %d", i)))
+			if err != nil && !errors.Is(err, porage.ErrLedgerClosed) {
+				panic(fmt.Sprintf("Failed to append entry: %v", err))
+			}
+		}()
+	}
+	time.Sleep(slowPoraDelay / 2)
+	lastAddConfirmed, err := distributedLedger.Close(ctx)
+	utilities.Logger.FatalIfErr(err, "Failed to close distributed ledger: %v", err)
+	wg.Wait()
+	if lastAddConfirmed != distributedLedger.LastAddConfirmed() || lastAddConfirmed < 0 {
+		t.Fatalf("Expected LastAddConfirmed %d, got %d.", distributedLedger.LastAddConfirmed(), lastAddConfirmed)
+	}
+
+	// Check: no entry can be appended after Close.
+	if _, err := distributedLedger.Append(ctx, []byte("Closed")); !errors.Is(err, porage.ErrLedgerClosed) {
+		t.Fatalf("Expected %v, got %v.", porage.ErrLedgerClosed, err)
+	}
+	for poraID, pora := range poras {
+		client, err := pool.Client(pora.Addr())
+		utilities.Logger.FatalIfErr(err, "Failed to start Porage client: %v", err)
+		if err := client.AppendEntryWithID(ctx, ledgerID, lastAddConfirmed+1, []byte("Closed")); !errors.Is(err, porage.ErrLedgerClosed) {
+			t.Fatalf("Expected %v on Pora %d, got %v.", porage.ErrLedgerClosed, poraID, err)
+		}
+	}
+
+	// Check: the closed ledger is read up to its LastEntryID from the stored metadata, but not appended.
+	utilities.Logger.Logf("Testing reads from the stored metadata.")
+	metadata := storedMetadata.load()
+	if !metadata.IsClosed || metadata.LastEntryID != lastAddConfirmed {
+		t.Fatalf("Expected the stored metadata closed at entry %d, got %+v.", lastAddConfirmed, metadata)
+	}
+	reader, err := porage.OpenDistributedLedger(pool, metadata)
+	utilities.Logger.FatalIfErr(err, "Failed to open distributed ledger: %v", err)
+	if reader.LastAddConfirmed() != lastAddConfirmed {
+		t.Fatalf("Expected LastAddConfirmed %d, got %d.", lastAddConfirmed, reader.LastAddConfirmed())
+	}
+	entries, err := reader.ReadEntries(ctx, 0, nEntries)
+	utilities.Logger.FatalIfErr(err, "Failed to read entries: %v", err)
+	if len(entries) != lastAddConfirmed+1 {
+		t.Fatalf("Expected %d entries, got %d.", lastAddConfirmed+1, len(entries))
+	}
+	if _, err := reader.Append(ctx, []byte("Reader")); !errors.Is(err, porage.ErrLedgerClosed) {
+		t.Fatalf("Expected %v, got %v.", porage.ErrLedgerClosed, err)
+	}
+
+	utilities.Logger.Logf("TestDistributedLedgerClose: %s", color.HiGreenString("PASS"))
+}
+
+func TestStripedDistributedLedger(t *testing.T) {
+	utilities.Logger.Logf("TestStripedDistributedLedger: Start.")
+	const ledgerID = uint64(5)
+	const stripedEnsembleSize = 5
+	const stripedWriteQuorumSize = 3
+	const nEntries = 1000
+	poras, pool := startEnsemble(t, stripedEnsembleSize)
+	defer stopEnsemble(poras, pool)
+	ctx := context.Background()
+
+	distributedLedger, err := porage.CreateDistributedLedger(ctx, ledgerID, pool, stripedEnsembleSize, stripedWriteQuorumSize, ackQuorumSize)
+	utilities.Logger.FatalIfErr(err, "Failed to create distributed ledger: %v", err)
+
+	// Check: each entry is placed on the Qw Poras in a round-robin from the (entryID mod E)-th one.
+	utilities.Logger.Logf("Testing striped placement.")
+	payloads := make(map[int][]byte)
+	for i := 0; i < nEntries; i++ {
+		payload := []byte(fmt.Sprintf("Striped Entry %d", i))
+		entryID, err := distributedLedger.Append(ctx, payload)
+		utilities.Logger.FatalIfErr(err, "Failed to append entry: %v", err)
+		payloads[entryID] = payload
+	}
+	for poraID, pora := range poras {
+		expectedEntryIDs := make([]int, 0, nEntries*stripedWriteQuorumSize/stripedEnsembleSize)
+		for entryID := 0; entryID < nEntries; entryID++ {
+			if (poraID-entryID%stripedEnsembleSize+stripedEnsembleSize)%stripedEnsembleSize < stripedWriteQuorumSize {
+				expectedEntryIDs = append(expectedEntryIDs, entryID)
+			}
+		}
+		if entryIDs := pora.EntryIDs(ledgerID); !slices.Equal(entryIDs, expectedEntryIDs) {
+			t.Fatalf("Expected %d entries on Pora %d, got %d.", len(expectedEntryIDs), poraID, len(entryIDs))
+		}
+	}
+	expectDistributedLedgerEntries(t, distributedLedger, payloads)
+
+	// Check: the reads are routed to the other Poras of the write sets while some Poras are down.
+	utilities.Logger.Logf("Testing striped reads with Poras down.")
+	for downPoraID := range poras {
+		poras[downPoraID].SetDown(true)
+		poras[(downPoraID+1)%stripedEnsembleSize].SetDown(true)
+		expectDistributedLedgerEntries(t, distributedLedger, payloads)
+		poras[downPoraID].SetDown(false)
+		poras[(downPoraID+1)%stripedEnsembleSize].SetDown(false)
+	}
+
+	// Check: a batch read starting at any entry reassembles the entries of the different Poras.
+	for fromEntryID := 0; fromEntryID < stripedEnsembleSize; fromEntryID++ {
+		entries, err := distributedLedger.ReadEntries(ctx, fromEntryID, fromEntryID+2*stripedEnsembleSize)
+		utilities.Logger.FatalIfErr(err, "Failed to read entries: %v", err)
+		if len(entries) != 2*stripedEnsembleSize+1 {
+			t.Fatalf("Expected %d entries from %d, got %d.", 2*stripedEnsembleSize+1, fromEntryID, len(entries))
+		}
+		for i, entry := range entries {
+			if entry.EntryID != fromEntryID+i || string(entry.Payload) != string(payloads[entry.EntryID]) {
+				t.Fatalf("Expected entry %d, got entry %d with payload %s.", fromEntryID+i, entry.EntryID, entry.Payload)
+			}
+		}
+	}
+
+	utilities.Logger.Logf("TestStripedDistributedLedger: %s", color.HiGreenString("PASS"))
+}
+
+func TestDistributedLedgerEnsembleChange(t *testing.T) {
+	utilities.Logger.Logf("TestDistributedLedgerEnsembleChange: Start.")
+	const ledgerID = uint64(6)
+	const poolSize = 5
+	const nEntriesPerRound = 100
+	const nGoroutines = 10
+	poras, pool := startEnsemble(t, poolSize)
+	defer stopEnsemble(poras, pool)
+	ctx := context.Background()
+
+	// Every Pora of the write set must ack, so no entry is confirmed until the failed Pora is replaced.
+	distributedLedger, err := porage.CreateDistributedLedger(ctx, ledgerID, pool, ensembleSize, writeQuorumSize, writeQuorumSize)
+	utilities.Logger.FatalIfErr(err, "Failed to create distributed ledger: %v", err)
+	storedMetadata := newMetadataStorage(distributedLedger)
+	metadata := distributedLedger.Metadata()
+	if len(metadata.Segments) != 1 || !slices.Equal(metadata.Segments[0].Poras, []string{poras[0].Addr(), poras[1].Addr(), poras[2].Addr()}) {
+		t.Fatalf("Expected the ensemble of the first %d Poras, got %v.", ensembleSize, metadata.Segments)
+	}
+	payloads := make(map[int][]byte)
+	for i := 0; i < nEntriesPerRound; i++ {
+		payload := []byte(fmt.Sprintf("Before, Entry %d", i))
+		entryID, err := distributedLedger.Append(ctx, payload)
+		utilities.Logger.FatalIfErr(err, "Failed to append entry: %v", err)
+		payloads[entryID] = payload
+	}
+
+	// Check: the appends go on while a Pora of the ensemble is down, which is replaced by a spare one.
+	utilities.Logger.Logf("Testing appends with a Pora replaced.")
+	poras[1].SetDown(true)
+	payloadsLock := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for goroutineID := 0; goroutineID < nGoroutines; goroutineID++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < nEntriesPerRound/nGoroutines; i++ {
+				payload := []byte(fmt.Sprintf("After, Goroutine %d, Entry %d", goroutineID, i))
+				entryID, err := distributedLedger.Append(ctx, payload)
+				utilities.Logger.FatalIfErr(err, "Failed to append entry: %v", err)
+				payloadsLock.Lock()
+				payloads[entryID] = payload
+				payloadsLock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if lastAddConfirmed := distributedLedger.LastAddConfirmed(); lastAddConfirmed != 2*nEntriesPerRound-1 {
+		t.Fatalf("Expected LastAddConfirmed %d, got %d.", 2*nEntriesPerRound-1, lastAddConfirmed)
+	}
+	metadata = distributedLedger.Metadata()
+	if len(metadata.Segments) != 2 {
+		t.Fatalf("Expected 2 segments, got %v.", metadata.Segments)
+	}
+	firstEntryID := metadata.Segments[1].FirstEntryID
+	if firstEntryID != nEntriesPerRound || !slices.Equal(metadata.Segments[1].Poras, []string{poras[0].Addr(), poras[3].Addr(), poras[2].Addr()}) {
+		t.Fatalf("Expected Pora 1 replaced by Pora 3 from entry %d, got %v.", nEntriesPerRound, metadata.Segments)
+	}
+
+	// Check: the ensemble change is stored, and the entries before the last segment are read from it.
+	storedSegments := storedMetadata.load().Segments
+	if len(storedSegments) != 2 || storedSegments[1].FirstEntryID != firstEntryID || !slices.Equal(storedSegments[1].Poras, metadata.Segments[1].Poras) {
+		t.Fatalf("Expected the stored segments %v, got %v.", metadata.Segments, storedSegments)
+	}
+	reader, err := porage.OpenDistributedLedger(pool, storedMetadata.load())
+	utilities.Logger.FatalIfErr(err, "Failed to open distributed ledger: %v", err)
+	if reader.LastAddConfirmed() != firstEntryID-1 {
+		t.Fatalf("Expected LastAddConfirmed %d of an open ledger, got %d.", firstEntryID-1, reader.LastAddConfirmed())
+	}
+	readerPayloads := make(map[int][]byte)
+	for entryID := 0; entryID < firstEntryID; entryID++ {
+		readerPayloads[entryID] = payloads[entryID]
+	}
+	expectDistributedLedgerEntries(t, reader, readerPayloads)
+
+	// Check: the entries of each segment are on its ensemble only.
+	for poraID, expectedEntryIDs := range map[int][2]int{0: {0, 2 * nEntriesPerRound}, 1: {0, firstEntryID}, 2: {0, 2 * nEntriesPerRound}, 3: {firstEntryID, 2 * nEntriesPerRound}, 4: {0, 0}} {
+		entryIDs := poras[poraID].EntryIDs(ledgerID)
+		if len(entryIDs) != expectedEntryIDs[1]-expectedEntryIDs[0] || (len(entryIDs) > 0 && entryIDs[0] != expectedEntryIDs[0]) {
+			t.Fatalf("Expected entries [%d, %d) on Pora %d, got %d entries.", expectedEntryIDs[0], expectedEntryIDs[1], poraID, len(entryIDs))
+		}
+	}
+	expectDistributedLedgerEntries(t, distributedLedger, payloads)
+	poras[1].SetDown(false)
+	expectDistributedLedgerEntries(t, distributedLedger, payloads)
+
+	// Check: the ledger is broken once no spare Pora is left to replace a failed one.
+	utilities.Logger.Logf("Testing appends without a spare Pora.")
+	poras[0].SetDown(true)
+	poras[3].SetDown(true)
+	if _, err := distributedLedger.Append(ctx, []byte("No spare Pora")); !errors.Is(err, porage.ErrNotEnoughReplicas) {
+		t.Fatalf("Expected %v, got %v.", porage.ErrNotEnoughReplicas, err)
+	}
+	poras[0].SetDown(false)
+	poras[3].SetDown(false)
+	if lastAddConfirmed := distributedLedger.LastAddConfirmed(); lastAddConfirmed != 2*nEntriesPerRound-1 {
+		t.Fatalf("Expected LastAddConfirmed %d, got %d.", 2*nEntriesPerRound-1, lastAddConfirmed)
+	}
+	expectDistributedLedgerEntries(t, distributedLedger, payloads)
+
+	utilities.Logger.Logf("TestDistributedLedgerEnsembleChange: %s", color.HiGreenString("PASS"))
+}
+
+func TestAutoRecovery(t *testing.T) {
+	utilities.Logger.Logf("TestAutoRecovery: Start.")
+	const closedLedgerID = uint64(7)
+	const openLedgerID = uint64(8)
+	const poolSize = 5
+	const stripedEnsembleSize = 4
+	const stripedWriteQuorumSize = 2
+	const nEntries = 100
+	poras, pool := startEnsemble(t, poolSize)
+	defer stopEnsemble(poras, pool)
+	ctx := context.Background()
+	autoRecovery := porage.NewAutoRecovery(pool)
+
+	appendEntries := func(distributedLedger *porage.DistributedLedger) {
+		for i := 0; i < nEntries; i++ {
+			_, err := distributedLedger.Append(ctx, []byte(fmt.Sprintf("Ledger %d, Entry %d", distributedLedger.LedgerID(), i)))
+			utilities.Logger.FatalIfErr(err, "Failed to append entry: %v", err)
+		}
+	}
+	closedLedger, err := porage.CreateDistributedLedger(ctx, closedLedgerID, pool, ensembleSize, writeQuorumSize, ackQuorumSize)
+	utilities.Logger.FatalIfErr(err, "Failed to create distributed ledger: %v", err)
+	appendEntries(closedLedger)
+	_, err = closedLedger.Close(ctx)
+	utilities.Logger.FatalIfErr(err, "Failed to close distributed ledger: %v", err)
+	openLedger, err := porage.CreateDistributedLedger(ctx, openLedgerID, pool, stripedEnsembleSize, stripedWriteQuorumSize, stripedWriteQuorumSize)
+	utilities.Logger.FatalIfErr(err, "Failed to create distributed ledger: %v", err)
+	appendEntries(openLedger)
+
+	// Check: the entries of the lost Pora of a closed ledger are copied onto the replacement, which is closed.
+	utilities.Logger.Logf("Testing recovery of a closed ledger.")
+	poras[1].SetDown(true)
+	metadata, nCopied, err := autoRecovery.RecoverLedger(ctx, closedLedger.Metadata(), poras[1].Addr())
+	utilities.Logger.FatalIfErr(err, "Failed to recover ledger: %v", err)
+	if nCopied != nEntries || !slices.Equal(metadata.Segments[0].Poras, []string{poras[0].Addr(), poras[3].Addr(), poras[2].Addr()}) {
+		t.Fatalf("Expected %d entries copied onto Pora 3, got %d entries with segments %v.", nEntries, nCopied, metadata.Segments)
+	}
+	if entryIDs := poras[3].EntryIDs(closedLedgerID); len(entryIDs) != nEntries {
+		t.Fatalf("Expected %d entries on Pora 3, got %d.", nEntries, len(entryIDs))
+	}
+	client, err := pool.Client(poras[3].Addr())
+	utilities.Logger.FatalIfErr(err, "Failed to start Porage client: %v", err)
+	if err := client.AppendEntryWithID(ctx, closedLedgerID, nEntries, []byte("Closed")); !errors.Is(err, porage.ErrLedgerClosed) {
+		t.Fatalf("Expected %v on the replacement, got %v.", porage.ErrLedgerClosed, err)
+	}
+
+	// Check: the recovery is idempotent, and the recovered metadata no longer includes the lost Pora.
+	_, nCopied, err = autoRecovery.RecoverLedger(ctx, closedLedger.Metadata(), poras[1].Addr())
+	utilities.Logger.FatalIfErr(err, "Failed to recover ledger again: %v", err)
+	if nCopied != 0 {
+		t.Fatalf("Expected no entry copied again, got %d.", nCopied)
+	}
+	if _, nCopied, err = autoRecovery.RecoverLedger(ctx, metadata, poras[1].Addr()); err != nil || nCopied != 0 {
+		t.Fatalf("Expected nothing to recover in the recovered metadata, got %d entries copied and %v.", nCopied, err)
+	}
+	poras[1].SetDown(false)
+
+	// Check: only the entries of the lost Pora of a striped open ledger are copied onto the replacement.
+	utilities.Logger.Logf("Testing recovery of a striped open ledger.")
+	poras[0].SetDown(true)
+	metadata, nCopied, err = autoRecovery.RecoverLedger(ctx, openLedger.Metadata(), poras[0].Addr())
+	utilities.Logger.FatalIfErr(err, "Failed to recover ledger: %v", err)
+	expectedEntryIDs := make([]int, 0, nEntries*stripedWriteQuorumSize/stripedEnsembleSize)
+	for entryID := 0; entryID < nEntries; entryID++ {
+		if slices.Contains(metadata.WriteSet(entryID), poras[4].Addr()) {
+			expectedEntryIDs = append(expectedEntryIDs, entryID)
+		}
+	}
+	if entryIDs := poras[4].EntryIDs(openLedgerID); nCopied != len(expectedEntryIDs) || !slices.Equal(entryIDs, expectedEntryIDs) {
+		t.Fatalf("Expected %d entries copied onto Pora 4, got %d entries copied and %d entries on it.", len(expectedEntryIDs), nCopied, len(entryIDs))
+	}
+	poras[0].SetDown(false)
+
+	// Check: the recovery fails without a spare Pora in the pool.
+	smallPool := porage.NewPoraPool([]string{poras[0].Addr(), poras[1].Addr(), poras[2].Addr()})
+	defer smallPool.Close()
+	if _, _, err := porage.NewAutoRecovery(smallPool).RecoverLedger(ctx, closedLedger.Metadata(), poras[1].Addr()); !errors.Is(err, porage.ErrNotEnoughReplicas) {
+		t.Fatalf("Expected %v, got %v.", porage.ErrNotEnoughReplicas, err)
+	}
+
+	utilities.Logger.Logf("TestAutoRecovery: %s", color.HiGreenString("PASS"))
+}
+
+// expectDistributedLedgerEntries checks that all the entries are read from the distributed ledger one by one and in
+// a batch.
+func expectDistributedLedgerEntries(t *testing.T, distributedLedger *porage.DistributedLedger, payloads map[int][]byte) {
+	ctx := context.Background()
+	entryIDs := make([]int, 0, len(payloads))
+	for entryID := range payloads {
+		entryIDs = append(entryIDs, entryID)
+	}
+	slices.Sort(entryIDs)
+	for _, entryID := range entryIDs {
+		payload, err := distributedLedger.Read(ctx, entryID)
+		utilities.Logger.FatalIfErr(err, "Failed to read entry %d: %v", entryID, err)
+		if string(payload) != string(payloads[entryID]) {
+			t.Fatalf("Expected payload %s of entry %d, got %s.", payloads[entryID], entryID, payload)
+		}
+	}
+	entries, err := distributedLedger.ReadEntries(ctx, 0, entryIDs[len(entryIDs)-1])
+	utilities.Logger.FatalIfErr(err, "Failed to read entries: %v", err)
+	if len(entries) != len(entryIDs) {
+		t.Fatalf("Expected %d entries, got %d.", len(entryIDs), len(entries))
+	}
+	for i, entry := range entries {
+		if entry.EntryID != entryIDs[i] || string(entry.Payload) != string(payloads[entry.EntryID]) {
+			t.Fatalf("Expected entry %d with payload %s, got entry %d with payload %s.", entryIDs[i], payloads[entryIDs[i]], entry.EntryID, entry.Payload)
+		}
+	}
+}
+
+// metadataStorage keeps the metadata of a DistributedLedger in JSON, as a MetadataStore would.
+type metadataStorage struct {
+	lock sync.Mutex
+	data []byte
+}
+
+// newMetadataStorage stores the metadata of the ledger and its changes.
+func newMetadataStorage(distributedLedger *porage.DistributedLedger) *metadataStorage {
+	storage := &metadataStorage{}
+	storage.store(distributedLedger.Metadata())
+	distributedLedger.SetMetadataListener(storage.store)
+	return storage
+}
+
+func (s *metadataStorage) store(metadata porage.DistributedLedgerMetadata) {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		panic(err)
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.data = data
+}
+
+func (s *metadataStorage) load() porage.DistributedLedgerMetadata {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	metadata := porage.DistributedLedgerMetadata{}
+	if err := json.Unmarshal(s.data, &metadata); err != nil {
+		panic(err)
+	}
+	return metadata
+}
+
+// startEnsemble starts size local Poras with a pool of them.
+func startEnsemble(t *testing.T, size int) ([]*localPora, *porage.PoraPool) {
+	poras := make([]*localPora, 0, size)
+	addrs := make([]string, 0, size)
+	for i := 0; i < size; i++ {
+		pora, err := startLocalPora()
+		if err != nil {
+			t.Fatalf("Failed to start Pora: %v", err)
+		}
+		poras = append(poras, pora)
+		addrs = append(addrs, pora.Addr())
+	}
+	return poras, porage.NewPoraPool(addrs)
+}
+
+// stopEnsemble stops the Poras and the clients of the pool.
+func stopEnsemble(poras []*localPora, pool *porage.PoraPool) {
+	pool.Close()
+	for _, pora := range poras {
+		pora.Stop()
+	}
+}
@@ -0,0 +1,166 @@
+package distributedtest_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"porage/internal/control"
+	"porage/internal/server"
+	pb "porage/proto"
+	"porage/test/utilities"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// localPoraLedgerIDBits is the number of the low bits of the ledger IDs in the local storage which are the ledger IDs
+// of a local Pora, and the high bits are the ID of the local Pora.
+const localPoraLedgerIDBits = 32
+
+// errPoraDown is the error returned by a local Pora which is set down.
+var errPoraDown = errors.New("pora down")
+
+// lastLocalPoraID is the ID of the last local Pora started in the process.
+var lastLocalPoraID atomic.Uint64
+
+// localPora is a Pora serving the gRPC service of Porage on a local port with the handlers of a real Pora, and
+// keeping its ledgers in the local storage of the process, which must be started beforehand. Several of them can run
+// in a process, unlike a PoraServer owning the local storage, so they are used to test the clients replicating ledgers
+// on several Poras.
+//
+// The ledgers of the local Poras are told apart in the local storage by the ID of their local Pora in the high bits of
+// their IDs, so the ledger IDs given by the clients must be below 1<<32.
+type localPora struct {
+	addr          string
+	poraID        uint64
+	grpcServer    *grpc.Server
+	ledgerControl *control.LedgerControl
+	// isDown makes all the RPCs fail with codes.Unavailable, as if the Pora crashed.
+	isDown atomic.Bool
+	// appendDelay delays the appends, as if the Pora is slow.
+	appendDelay atomic.Int64
+}
+
+// startLocalPora starts a local Pora listening on a random local port.
+func startLocalPora() (*localPora, error) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return nil, err
+	}
+	pora := &localPora{
+		addr:          listener.Addr().String(),
+		poraID:        lastLocalPoraID.Add(1),
+		ledgerControl: control.NewLedgerControl(),
+	}
+	pora.grpcServer = server.NewLocalGRPCServer(pora.ledgerControl, pora.unaryInterceptor, pora.streamInterceptor)
+	go pora.grpcServer.Serve(listener)
+	return pora, nil
+}
+
+// Addr returns the address of the local Pora.
+func (p *localPora) Addr() string {
+	return p.addr
+}
+
+// Stop stops the local Pora. Its ledgers are kept in the local storage.
+func (p *localPora) Stop() {
+	p.grpcServer.Stop()
+}
+
+// SetDown makes all the following RPCs fail if isDown is true, or recovers the local Pora otherwise.
+func (p *localPora) SetDown(isDown bool) {
+	p.isDown.Store(isDown)
+}
+
+// SetAppendDelay delays the following appends by delay.
+func (p *localPora) SetAppendDelay(delay time.Duration) {
+	p.appendDelay.Store(int64(delay))
+}
+
+// EntryIDs returns the IDs of the entries of a ledger in ascending order.
+func (p *localPora) EntryIDs(ledgerID uint64) []int {
+	ledger := p.ledgerControl.GetLedger(p.localLedgerID(ledgerID))
+	if ledger == nil {
+		return nil
+	}
+	entries, err := ledger.ReadEntries(ledger.LowWaterMark(), math.MaxInt, 0)
+	utilities.Logger.FatalIfErr(err, "Failed to read entries of ledger %d: %v", ledgerID, err)
+	entryIDs := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		entryIDs = append(entryIDs, entry.EntryID)
+	}
+	return entryIDs
+}
+
+// localLedgerID returns the ID in the local storage of a ledger of the local Pora.
+func (p *localPora) localLedgerID(ledgerID uint64) uint64 {
+	return p.poraID<<localPoraLedgerIDBits | ledgerID
+}
+
+// toLocalLedgerID replaces the ledger ID of a request with its ID in the local storage.
+func (p *localPora) toLocalLedgerID(request any) {
+	message, ok := request.(proto.Message)
+	if !ok {
+		return
+	}
+	reflectMessage := message.ProtoReflect()
+	field := reflectMessage.Descriptor().Fields().ByName("ledger_id")
+	if field == nil {
+		return
+	}
+	reflectMessage.Set(field, protoreflect.ValueOfUint64(p.localLedgerID(reflectMessage.Get(field).Uint())))
+}
+
+// unaryInterceptor fails the unary calls while the local Pora is down, delays the appends, and translates the ledger
+// IDs between the clients and the local storage.
+func (p *localPora) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if p.isDown.Load() {
+		return nil, status.Error(codes.Unavailable, errPoraDown.Error())
+	}
+	if _, ok := req.(*pb.AppendEntryWithIDRequest); ok {
+		if delay := time.Duration(p.appendDelay.Load()); delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	p.toLocalLedgerID(req)
+	response, err := handler(ctx, req)
+	if listResponse, ok := response.(*pb.ListLedgersResponse); ok {
+		for i, ledgerID := range listResponse.LedgerIds {
+			listResponse.LedgerIds[i] = ledgerID & (1<<localPoraLedgerIDBits - 1)
+		}
+	}
+	return response, err
+}
+
+// streamInterceptor fails the streaming calls while the local Pora is down, and translates the ledger IDs of the
+// requests into those in the local storage.
+func (p *localPora) streamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if p.isDown.Load() {
+		return status.Error(codes.Unavailable, errPoraDown.Error())
+	}
+	return handler(srv, &localPoraServerStream{ServerStream: stream, pora: p})
+}
+
+// localPoraServerStream translates the ledger IDs of the requests received on a stream of a local Pora.
+type localPoraServerStream struct {
+	grpc.ServerStream
+	pora *localPora
+}
+
+func (s *localPoraServerStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	s.pora.toLocalLedgerID(m)
+	return nil
+}
@@ -2,25 +2,43 @@ package e2e_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"porage/internal/pkg"
 	"porage/internal/server"
 	porage "porage/pkg"
+	"porage/pkg/metadata"
 	"porage/test/utilities"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
 const (
 	serverConfigFilePath              = "./config.toml"
 	ledgerID                          = uint64(0)
+	batchLedgerID                     = uint64(1)
+	retentionLedgerID                 = uint64(2)
+	registrationLedgerID              = uint64(3)
+	retentionMaxEntries               = 10
+	nBatches                          = 100
+	batchSize                         = 100
 	nIterations                       = 100000
 	nNewEntryAfterRecover             = 100
 	rwLogFrequency                    = nIterations / 10
 	dataDir                           = "./_data"
 	getterGoroutineAssignedEntryCount = 100
+	waitTimeout                       = 30 * time.Second
 )
 
 var (
@@ -29,6 +47,8 @@ var (
 	poraServer   *server.PoraServer   = nil
 	porageClient *porage.PorageClient = nil
 	expectedDB                        = sync.Map{}
+	// metadataStore is the MetadataStore in which the Pora registers itself, instead of an etcd cluster.
+	metadataStore = metadata.NewLocalStore()
 )
 
 func TestE2E(t *testing.T) {
@@ -50,12 +70,19 @@ func TestE2E(t *testing.T) {
 	// Test logic
 	ctx := context.Background()
 	testCreateLedger(ctx)
+	testErrorCodes(ctx)
 	testAppendEntry(ctx)
 	testGetEntry(ctx)
 	waitForEntryLoggerFlush()
 	testGetLedgerLength(ctx)
+	testReadEntries(ctx)
+	testAppendEntries(ctx)
+	testMetrics()
+	testHealth(ctx)
+	testRegistration(ctx)
 
 	poraServer.Stop()
+	testDeregistration(ctx)
 	err = startPorageServerInBackground()
 	utilities.Logger.FatalIfErr(err, "Failed to start Porage server")
 	testAppendEntryAfterRecovery(ctx)
@@ -64,7 +91,17 @@ func TestE2E(t *testing.T) {
 	testGetLedgerLengthAfterRecovery(ctx)
 
 	testListWorkers(ctx)
-	testCloseEntry(ctx)
+	tailResult := startTailLedger(ctx)
+	testCloseLedger(ctx)
+	testTailLedgerAfterClose(tailResult)
+
+	poraServer.Stop()
+	err = startPorageServerInBackground()
+	utilities.Logger.FatalIfErr(err, "Failed to start Porage server")
+	testClosedLedgerAfterRecovery(ctx)
+	testTrimLedger(ctx)
+	testRetentionPolicy(ctx)
+	testDeleteLedger(ctx)
 }
 
 func startPorageServerInBackground() error {
@@ -74,7 +111,7 @@ func startPorageServerInBackground() error {
 		if err != nil {
 			return err
 		}
-		poraServer = server.NewPorageServer(serverConfig)
+		poraServer = server.NewPorageServerWithMetadataStore(serverConfig, metadataStore)
 		serverAddr = fmt.Sprintf("localhost:%d", serverConfig.Server.GRPCPort)
 	}
 	go poraServer.Start()
@@ -94,6 +131,31 @@ func testCreateLedger(ctx context.Context) {
 	}
 }
 
+// testErrorCodes checks that the errors are returned with the gRPC status codes and converted into the exported
+// errors.
+func testErrorCodes(ctx context.Context) {
+	utilities.Logger.Logf("Testing error codes")
+	err := porageClient.CreateLedger(ctx, ledgerID)
+	expectError(err, porage.ErrLedgerExisted, codes.AlreadyExists)
+	_, err = porageClient.AppendEntryOnLedger(ctx, ledgerID+1000, generatePayloadWithEntryID(0))
+	expectError(err, porage.ErrLedgerNotFound, codes.NotFound)
+	_, err = porageClient.GetEntryFromLedger(ctx, ledgerID, 0)
+	expectError(err, porage.ErrEntryNotFound, codes.NotFound)
+	err = porageClient.AppendEntryWithID(ctx, ledgerID, -1, generatePayloadWithEntryID(0))
+	expectError(err, porage.ErrInvalidEntryID, codes.InvalidArgument)
+	_, err = porageClient.ReadEntries(ctx, ledgerID+1000, 0, 0, 0)
+	expectError(err, porage.ErrLedgerNotFound, codes.NotFound)
+	err = porageClient.CreateLedgerWithOptions(ctx, ledgerID+1000, porage.CreateLedgerOptions{RetentionPolicy: porage.RetentionPolicy{MaxEntries: -1}})
+	expectError(err, porage.ErrInvalidRetentionPolicy, codes.InvalidArgument)
+}
+
+func expectError(err error, expectedErr error, expectedCode codes.Code) {
+	if !errors.Is(err, expectedErr) || status.Code(err) != expectedCode {
+		msg := fmt.Sprintf("Failed to get the expected error. Expected: %v(%v), Got: %v(%v)", expectedErr, expectedCode, err, status.Code(err))
+		panic(msg)
+	}
+}
+
 func testAppendEntry(ctx context.Context) {
 	utilities.Logger.Logf("Testing AppendEntry")
 	wg := sync.WaitGroup{}
@@ -196,20 +258,394 @@ func testGetLedgerLength(ctx context.Context) {
 	}
 }
 
+func testReadEntries(ctx context.Context) {
+	utilities.Logger.Logf("Testing ReadEntries")
+	startTime := time.Now()
+	entries, err := porageClient.ReadEntries(ctx, ledgerID, 0, nIterations-1, 0)
+	utilities.Logger.FatalIfErr(err, "Failed to read entries")
+	if len(entries) != nIterations {
+		msg := fmt.Sprintf("Failed to read entries. Expected: %d entries, Got: %d", nIterations, len(entries))
+		panic(msg)
+	}
+	for i, entry := range entries {
+		expectedPayload, _ := expectedDB.Load(i)
+		if entry.EntryID != i || string(entry.Payload) != string(expectedPayload.([]byte)) {
+			msg := fmt.Sprintf("Failed to read entries. Expected: %d(%s), Got: %d(%s)", i, expectedPayload, entry.EntryID, entry.Payload)
+			panic(msg)
+		}
+	}
+	utilities.Logger.Logf("ReadEntries done, elapsed time: %v", time.Since(startTime))
+
+	maxBytes := 10 * len(generatePayloadWithEntryID(0))
+	entries, err = porageClient.ReadEntries(ctx, ledgerID, 0, nIterations-1, maxBytes)
+	utilities.Logger.FatalIfErr(err, "Failed to read entries with max bytes")
+	if len(entries) != 10 {
+		msg := fmt.Sprintf("Failed to read entries with max bytes. Expected: 10 entries, Got: %d", len(entries))
+		panic(msg)
+	}
+}
+
+// testAppendEntries appends batches to another ledger with the batch append and the streaming append, and deletes
+// the ledger after checking the entries.
+func testAppendEntries(ctx context.Context) {
+	utilities.Logger.Logf("Testing AppendEntries")
+	err := porageClient.CreateLedger(ctx, batchLedgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to create ledger")
+	startTime := time.Now()
+	for batch := 0; batch < nBatches; batch++ {
+		entryIDs, err := porageClient.AppendEntriesOnLedger(ctx, batchLedgerID, generateBatchPayloads(batch))
+		utilities.Logger.FatalIfErr(err, "Failed to append entries")
+		expectBatchEntryIDs(batch, entryIDs)
+	}
+	utilities.Logger.Logf("AppendEntries done, elapsed time: %v", time.Since(startTime))
+
+	utilities.Logger.Logf("Testing AppendEntriesStream")
+	startTime = time.Now()
+	appender, err := porageClient.AppendEntriesStream(ctx)
+	utilities.Logger.FatalIfErr(err, "Failed to start append stream")
+	go func() {
+		for batch := nBatches; batch < 2*nBatches; batch++ {
+			err := appender.Send(batchLedgerID, generateBatchPayloads(batch))
+			utilities.Logger.FatalIfErr(err, "Failed to send entries")
+		}
+		err := appender.CloseSend()
+		utilities.Logger.FatalIfErr(err, "Failed to close append stream")
+	}()
+	for batch := nBatches; ; batch++ {
+		entryIDs, err := appender.Recv()
+		if err == io.EOF {
+			if batch != 2*nBatches {
+				msg := fmt.Sprintf("Failed to append entries in stream. Expected: %d batches, Got: %d", nBatches, batch-nBatches)
+				panic(msg)
+			}
+			break
+		}
+		utilities.Logger.FatalIfErr(err, "Failed to receive appended entries")
+		expectBatchEntryIDs(batch, entryIDs)
+	}
+	utilities.Logger.Logf("AppendEntriesStream done, elapsed time: %v", time.Since(startTime))
+
+	entries, err := porageClient.ReadEntries(ctx, batchLedgerID, 0, 2*nBatches*batchSize-1, 0)
+	utilities.Logger.FatalIfErr(err, "Failed to read entries")
+	if len(entries) != 2*nBatches*batchSize {
+		msg := fmt.Sprintf("Failed to read appended batches. Expected: %d entries, Got: %d", 2*nBatches*batchSize, len(entries))
+		panic(msg)
+	}
+	for i, entry := range entries {
+		if entry.EntryID != i || string(entry.Payload) != string(generatePayloadWithEntryID(i)) {
+			msg := fmt.Sprintf("Failed to read appended batches. Expected: %d, Got: %d(%s)", i, entry.EntryID, entry.Payload)
+			panic(msg)
+		}
+	}
+	err = porageClient.DeleteLedger(ctx, batchLedgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to delete ledger")
+}
+
+func generateBatchPayloads(batch int) [][]byte {
+	payloads := make([][]byte, 0, batchSize)
+	for i := 0; i < batchSize; i++ {
+		payloads = append(payloads, generatePayloadWithEntryID(batch*batchSize+i))
+	}
+	return payloads
+}
+
+func expectBatchEntryIDs(batch int, entryIDs []int) {
+	if len(entryIDs) != batchSize {
+		msg := fmt.Sprintf("Failed to append batch %d. Expected: %d entry IDs, Got: %d", batch, batchSize, len(entryIDs))
+		panic(msg)
+	}
+	for i, entryID := range entryIDs {
+		if entryID != batch*batchSize+i {
+			msg := fmt.Sprintf("Failed to append batch %d. Expected entry ID: %d, Got: %d", batch, batch*batchSize+i, entryID)
+			panic(msg)
+		}
+	}
+}
+
+// testMetrics checks that the metrics of the appends and the reads above are served on the HTTP port.
+func testMetrics() {
+	utilities.Logger.Logf("Testing metrics")
+	response, err := http.Get(fmt.Sprintf("http://localhost:%d/metrics", serverConfig.Server.Port))
+	utilities.Logger.FatalIfErr(err, "Failed to get metrics")
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	utilities.Logger.FatalIfErr(err, "Failed to read metrics")
+	expectedSamples := []string{
+		"porage_journal_group_commit_duration_seconds_count ",
+		"porage_journal_group_commit_entries_count ",
+		"porage_journal_fsync_duration_seconds_count ",
+		"porage_journal_message_buffer_depth ",
+		"porage_entry_logger_fsync_duration_seconds_count ",
+		fmt.Sprintf("porage_ledger_message_buffer_depth{ledger=\"%d\"} ", ledgerID),
+		fmt.Sprintf("porage_ledger_appended_entries_total{ledger=\"%d\"} %d\n", ledgerID, nIterations),
+		fmt.Sprintf("porage_ledger_read_entries_total{ledger=\"%d\"} ", ledgerID),
+		"porage_memtable_lookups_total{result=\"hit\"} ",
+		"porage_index_operation_duration_seconds_count{operation=\"get\"} ",
+		"porage_recovery_duration_seconds ",
+	}
+	for _, expectedSample := range expectedSamples {
+		if !strings.Contains(string(body), expectedSample) {
+			msg := fmt.Sprintf("Failed to get metrics. Expected sample: %q, Got:\n%s", expectedSample, body)
+			panic(msg)
+		}
+	}
+}
+
+// testHealth checks that the Pora is reported live and ready by the HTTP probes and the gRPC health service after the
+// recovery.
+func testHealth(ctx context.Context) {
+	utilities.Logger.Logf("Testing health")
+	for _, probe := range []string{"healthz", "readyz"} {
+		response, err := http.Get(fmt.Sprintf("http://localhost:%d/%s", serverConfig.Server.Port, probe))
+		utilities.Logger.FatalIfErr(err, "Failed to get %s", probe)
+		response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			msg := fmt.Sprintf("Failed to get %s. Expected status: %d, Got: %d", probe, http.StatusOK, response.StatusCode)
+			panic(msg)
+		}
+	}
+
+	conn, err := grpc.NewClient(serverAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	utilities.Logger.FatalIfErr(err, "Failed to connect to health service")
+	defer conn.Close()
+	for _, service := range []string{"", "porageservice.PorageService"} {
+		response, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		utilities.Logger.FatalIfErr(err, "Failed to check health of %q", service)
+		if response.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			msg := fmt.Sprintf("Failed to check health of %q. Expected status: SERVING, Got: %v", service, response.GetStatus())
+			panic(msg)
+		}
+	}
+}
+
+// testRegistration checks that the Pora is registered with its load, which is updated by the heartbeat worker.
+func testRegistration(ctx context.Context) {
+	utilities.Logger.Logf("Testing registration")
+	poraInfo := getRegisteredPora(ctx)
+	if poraInfo.Addr != serverAddr || poraInfo.LedgerCount < 1 || poraInfo.DiskUsage <= 0 || poraInfo.Capacity <= 0 {
+		msg := fmt.Sprintf("Failed to register. Expected Pora %s with ledgers and disk usage, Got: %+v", serverAddr, poraInfo)
+		panic(msg)
+	}
+
+	// The load is updated by the heartbeats, so the entries are appended until a heartbeat reports the new ledger, next
+	// to ledgerID, with a write throughput.
+	err := porageClient.CreateLedger(ctx, registrationLedgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to create ledger")
+	defer func() {
+		err := porageClient.DeleteLedger(ctx, registrationLedgerID)
+		utilities.Logger.FatalIfErr(err, "Failed to delete ledger")
+	}()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		_, err := porageClient.AppendEntriesOnLedger(ctx, registrationLedgerID, generateBatchPayloads(batchSize))
+		utilities.Logger.FatalIfErr(err, "Failed to append entries")
+		newPoraInfo := getRegisteredPora(ctx)
+		if newPoraInfo.LedgerCount == 2 && newPoraInfo.WriteThroughput > 0 &&
+			newPoraInfo.UpdateTime.After(poraInfo.UpdateTime) {
+			return
+		}
+		if time.Now().After(deadline) {
+			msg := fmt.Sprintf("Failed to update registration. Expected 2 ledgers with write throughput, Got: %+v", newPoraInfo)
+			panic(msg)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// testDeregistration checks that the Pora is deregistered by Stop.
+func testDeregistration(ctx context.Context) {
+	utilities.Logger.Logf("Testing deregistration")
+	poras, err := metadata.ListPoras(ctx, metadataStore)
+	utilities.Logger.FatalIfErr(err, "Failed to list Poras")
+	if len(poras) != 0 {
+		msg := fmt.Sprintf("Failed to deregister. Expected no Pora, Got: %d", len(poras))
+		panic(msg)
+	}
+}
+
+// getRegisteredPora returns the registration of the Pora, which is the only one in the MetadataStore.
+func getRegisteredPora(ctx context.Context) *metadata.PoraInfo {
+	poras, err := metadata.ListPoras(ctx, metadataStore)
+	utilities.Logger.FatalIfErr(err, "Failed to list Poras")
+	if len(poras) != 1 {
+		msg := fmt.Sprintf("Failed to list Poras. Expected 1 Pora, Got: %d", len(poras))
+		panic(msg)
+	}
+	return poras[0]
+}
+
 func testListWorkers(ctx context.Context) {
 	utilities.Logger.Logf("Testing ListWorkers")
 	workerDescriptions, err := porageClient.GetWorkerDescriptions(ctx)
 	utilities.Logger.FatalIfErr(err, "Failed to list workers")
-	if len(workerDescriptions) != 3 {
-		// Expected: 1. ledger persistence worker; 2. journal worker; 3. journal trim worker
-		panic("Failed to list workers. Expected 3 workers. If there is any missing update in e2e, please update this number.")
+	if len(workerDescriptions) != 5 {
+		// Expected: 1. ledger persistence worker; 2. journal worker; 3. journal trim worker; 4. ledger retention worker;
+		// 5. registry heartbeat worker
+		panic("Failed to list workers. Expected 5 workers. If there is any missing update in e2e, please update this number.")
+	}
+
+	// The entries appended after the recovery are processed by the journal worker and the ledger persistence worker.
+	workerStatuses, err := porageClient.ListWorkers(ctx)
+	utilities.Logger.FatalIfErr(err, "Failed to list workers")
+	if _, ok := workerStatuses["registry_heartbeat_worker"]; !ok {
+		panic("Failed to list workers. Expected registry_heartbeat_worker")
+	}
+	for _, workerName := range []string{"journal_worker", fmt.Sprintf("ledger-%d-persistence-worker", ledgerID)} {
+		workerStatus, ok := workerStatuses[workerName]
+		if !ok || workerStatus.Processed < nNewEntryAfterRecover {
+			msg := fmt.Sprintf("Failed to list workers. Expected %s to process at least %d items, Got: %+v",
+				workerName, nNewEntryAfterRecover, workerStatus)
+			panic(msg)
+		}
+	}
+	for workerName, workerStatus := range workerStatuses {
+		if workerStatus.Stuck || time.Since(workerStatus.LastHeartbeat) > time.Minute {
+			msg := fmt.Sprintf("Failed to list workers. Expected %s to make progress, Got: %+v", workerName, workerStatus)
+			panic(msg)
+		}
 	}
 }
 
-func testCloseEntry(ctx context.Context) {
-	utilities.Logger.Logf("Testing CloseEntry")
-	err := porageClient.DeleteLedger(ctx, ledgerID)
+func testCloseLedger(ctx context.Context) {
+	utilities.Logger.Logf("Testing CloseLedger")
+	lastEntryID, err := porageClient.CloseLedger(ctx, ledgerID)
 	utilities.Logger.FatalIfErr(err, "Failed to close ledger")
+	if lastEntryID != nIterations+nNewEntryAfterRecover-1 {
+		msg := fmt.Sprintf("Failed to close ledger. Expected last entry ID: %d, Got: %d", nIterations+nNewEntryAfterRecover-1, lastEntryID)
+		panic(msg)
+	}
+	expectClosedLedger(ctx)
+}
+
+// startTailLedger tails the ledger from the first entry appended after recovery. The number of the tailed entries
+// is sent to the returned channel after the tailing read ends.
+func startTailLedger(ctx context.Context) chan int {
+	utilities.Logger.Logf("Testing TailLedger")
+	tailer, err := porageClient.TailLedger(ctx, ledgerID, nIterations)
+	utilities.Logger.FatalIfErr(err, "Failed to tail ledger")
+	tailResult := make(chan int, 1)
+	go func() {
+		nTailedEntries := 0
+		for {
+			entry, err := tailer.Next()
+			if err == io.EOF {
+				tailResult <- nTailedEntries
+				return
+			}
+			utilities.Logger.FatalIfErr(err, "Failed to tail ledger")
+			if entry.EntryID != nIterations+nTailedEntries {
+				msg := fmt.Sprintf("Failed to tail ledger. Expected entry: %d, Got: %d", nIterations+nTailedEntries, entry.EntryID)
+				panic(msg)
+			}
+			nTailedEntries++
+		}
+	}()
+	return tailResult
+}
+
+func testTailLedgerAfterClose(tailResult chan int) {
+	utilities.Logger.Logf("Testing TailLedger after CloseLedger")
+	nTailedEntries := <-tailResult
+	if nTailedEntries != nNewEntryAfterRecover {
+		msg := fmt.Sprintf("Failed to tail ledger. Expected: %d entries, Got: %d", nNewEntryAfterRecover, nTailedEntries)
+		panic(msg)
+	}
+}
+
+func testClosedLedgerAfterRecovery(ctx context.Context) {
+	utilities.Logger.Logf("Testing closed ledger after recovery")
+	expectClosedLedger(ctx)
+}
+
+// expectClosedLedger checks that appends to the ledger are rejected while the entries can still be read.
+func expectClosedLedger(ctx context.Context) {
+	_, err := porageClient.AppendEntryOnLedger(ctx, ledgerID, generatePayloadWithEntryID(nIterations+nNewEntryAfterRecover))
+	if !errors.Is(err, porage.ErrLedgerClosed) || status.Code(err) != codes.FailedPrecondition {
+		msg := fmt.Sprintf("Failed to reject append to closed ledger. Got: %v", err)
+		panic(msg)
+	}
+	entryID := nIterations + nNewEntryAfterRecover - 1
+	payload, err := porageClient.GetEntryFromLedger(ctx, ledgerID, entryID)
+	utilities.Logger.FatalIfErr(err, "Failed to get entry from closed ledger")
+	expectedPayload, _ := expectedDB.Load(entryID)
+	if string(payload) != string(expectedPayload.([]byte)) {
+		msg := fmt.Sprintf("Failed to get entry from closed ledger. Expected: %s, Got: %s", expectedPayload, payload)
+		panic(msg)
+	}
+}
+
+// testTrimLedger checks that the trimmed entries of the closed ledger are rejected with OutOfRange, while the later
+// entries can still be read.
+func testTrimLedger(ctx context.Context) {
+	utilities.Logger.Logf("Testing TrimLedger")
+	const untilEntryID = nIterations / 2
+	err := porageClient.TrimLedger(ctx, ledgerID, untilEntryID)
+	utilities.Logger.FatalIfErr(err, "Failed to trim ledger")
+	_, err = porageClient.GetEntryFromLedger(ctx, ledgerID, 0)
+	expectError(err, porage.ErrEntryTrimmed, codes.OutOfRange)
+	_, err = porageClient.GetEntryFromLedger(ctx, ledgerID, untilEntryID)
+	expectError(err, porage.ErrEntryTrimmed, codes.OutOfRange)
+	payload, err := porageClient.GetEntryFromLedger(ctx, ledgerID, untilEntryID+1)
+	utilities.Logger.FatalIfErr(err, "Failed to get entry after the trimmed ones")
+	expectedPayload, _ := expectedDB.Load(untilEntryID + 1)
+	if string(payload) != string(expectedPayload.([]byte)) {
+		msg := fmt.Sprintf("Failed to get entry after the trimmed ones. Expected: %s, Got: %s", expectedPayload, payload)
+		panic(msg)
+	}
+}
+
+// testRetentionPolicy checks that the oldest entries of a ledger beyond its retention policy are trimmed by the Pora,
+// and that the metadata of the ledger reflects the trimmed entries.
+func testRetentionPolicy(ctx context.Context) {
+	utilities.Logger.Logf("Testing retention policy")
+	const nEntries = 100
+	options := porage.CreateLedgerOptions{
+		RetentionPolicy: porage.RetentionPolicy{MaxEntries: retentionMaxEntries},
+		Properties:      map[string]string{"owner": "e2e"},
+	}
+	err := porageClient.CreateLedgerWithOptions(ctx, retentionLedgerID, options)
+	utilities.Logger.FatalIfErr(err, "Failed to create ledger with retention policy")
+	for entryID := 0; entryID < nEntries; entryID++ {
+		_, err := porageClient.AppendEntryOnLedger(ctx, retentionLedgerID, generatePayloadWithEntryID(entryID))
+		utilities.Logger.FatalIfErr(err, "Failed to append entry")
+	}
+	utilities.WaitFor(waitTimeout, "the retention policy to trim the entries", func() (bool, error) {
+		info, err := porageClient.GetLedgerInfo(ctx, retentionLedgerID)
+		if err != nil {
+			return false, err
+		}
+		return info.FirstEntryID >= nEntries-retentionMaxEntries, nil
+	})
+
+	_, err = porageClient.GetEntryFromLedger(ctx, retentionLedgerID, nEntries-retentionMaxEntries-1)
+	expectError(err, porage.ErrEntryTrimmed, codes.OutOfRange)
+	payload, err := porageClient.GetEntryFromLedger(ctx, retentionLedgerID, nEntries-retentionMaxEntries)
+	utilities.Logger.FatalIfErr(err, "Failed to get entry within the retention policy")
+	if string(payload) != string(generatePayloadWithEntryID(nEntries-retentionMaxEntries)) {
+		msg := fmt.Sprintf("Failed to get entry within the retention policy. Got: %s", payload)
+		panic(msg)
+	}
+
+	info, err := porageClient.GetLedgerInfo(ctx, retentionLedgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to get ledger info")
+	if info.State != porage.LedgerStateOpen || info.Properties["owner"] != "e2e" ||
+		info.RetentionPolicy.MaxEntries != retentionMaxEntries || info.EntryCount != retentionMaxEntries ||
+		info.ByteSize < int64(retentionMaxEntries*len(payload)) || info.FirstEntryID != nEntries-retentionMaxEntries ||
+		info.LastAppendTime.Before(info.CreationTime) {
+		msg := fmt.Sprintf("Failed to get the expected ledger info. Got: %+v", info)
+		panic(msg)
+	}
+	_, err = porageClient.GetLedgerInfo(ctx, retentionLedgerID+1000)
+	expectError(err, porage.ErrLedgerNotFound, codes.NotFound)
+
+	err = porageClient.DeleteLedger(ctx, retentionLedgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to delete ledger with retention policy")
+}
+
+func testDeleteLedger(ctx context.Context) {
+	utilities.Logger.Logf("Testing DeleteLedger")
+	err := porageClient.DeleteLedger(ctx, ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to delete ledger")
 
 	ledgerIDList, err := porageClient.ListLedgers(ctx)
 	utilities.Logger.FatalIfErr(err, "Failed to list ledgers")
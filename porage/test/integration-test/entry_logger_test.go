@@ -0,0 +1,417 @@
+package integrationtest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	entrylogger "porage/internal/entry_logger"
+	"porage/internal/ledger"
+	"porage/internal/pkg"
+	"porage/internal/recovery"
+	"porage/test/utilities"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Test Sceanrio:
+//  1. A corrupted entry in the entry logger is rejected with a typed error.
+//  2. An entry logger file written in the legacy format is still readable and writable.
+//  3. The entries of the ledgers flushed together are written to the interleaved entry logs in one sorted batch, and
+//     the entry logs whose ledgers are all deleted are removed by the garbage collection.
+//  4. The entry logs with few live entries are compacted while the ledgers are read, the compacted entry logs are kept
+//     open until their readers are done, and the relocated entries survive the recovery.
+
+func TestEntryLoggerCorruption(t *testing.T) {
+	utilities.Logger.Logf("TestEntryLoggerCorruption: Start.")
+	const ledgerID = uint64(5)
+	const nEntries = 100
+	const corruptedEntryID = nEntries / 2
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	entrylogger.Startup(&config.EntryLogger)
+
+	entryLogger, err := entrylogger.NewEntryLogger(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to create entry logger: %v", err)
+	defer entryLogger.Close()
+	for entryID := 0; entryID < nEntries; entryID++ {
+		err := entryLogger.Write(&pkg.LedgerEntry{EntryID: entryID, Payload: generatePayloadWithEntryID(entryID)})
+		utilities.Logger.FatalIfErr(err, "Failed to write entry: %v", err)
+	}
+	entryMetadata, err := entryLogger.Flush()
+	utilities.Logger.FatalIfErr(err, "Failed to flush entry logger: %v", err)
+
+	// Check: all the entries are read back.
+	utilities.Logger.Logf("Testing read entries.")
+	for _, metadata := range entryMetadata {
+		entry, err := entryLogger.Read(metadata.Offset, metadata.Size)
+		utilities.Logger.FatalIfErr(err, "Failed to read entry: %v", err)
+		expectPayloadEq(t, generatePayloadWithEntryID(metadata.EntryID), entry.Payload)
+	}
+
+	// Check: a corrupted entry is rejected.
+	utilities.Logger.Logf("Testing read corrupted entry.")
+	corruptedMetadata := entryMetadata[corruptedEntryID]
+	file, err := os.OpenFile(makeEntryLoggerFilePath(config, ledgerID), os.O_RDWR, 0644)
+	utilities.Logger.FatalIfErr(err, "Failed to open entry logger file: %v", err)
+	_, err = file.WriteAt([]byte("corrupted"), int64(corruptedMetadata.Offset+corruptedMetadata.Size-len("corrupted")))
+	utilities.Logger.FatalIfErr(err, "Failed to corrupt entry logger file: %v", err)
+	file.Close()
+
+	entry, err := entryLogger.Read(corruptedMetadata.Offset, corruptedMetadata.Size)
+	var corruptionErr *pkg.EntryCorruptionError
+	if entry != nil || !errors.As(err, &corruptionErr) || !errors.Is(err, pkg.ErrEntryCorrupted) {
+		t.Fatalf("Expected an entry corruption error and no entry, got %v and %v.", err, entry)
+	}
+	if corruptionErr.LedgerID != ledgerID || corruptionErr.Offset != int64(corruptedMetadata.Offset) {
+		t.Fatalf("Expected corruption of ledger %d at %d, got ledger %d at %d.", ledgerID, corruptedMetadata.Offset,
+			corruptionErr.LedgerID, corruptionErr.Offset)
+	}
+
+	utilities.Logger.Logf("TestEntryLoggerCorruption: %s", color.HiGreenString("PASS"))
+}
+
+func TestEntryLoggerLegacyFormat(t *testing.T) {
+	utilities.Logger.Logf("TestEntryLoggerLegacyFormat: Start.")
+	const ledgerID = uint64(6)
+	const nEntries = 100
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	entrylogger.Startup(&config.EntryLogger)
+
+	// Generate an entry logger file in the legacy format: [EntryID][Payload] without file header.
+	legacyFile, err := os.Create(makeEntryLoggerFilePath(config, ledgerID))
+	utilities.Logger.FatalIfErr(err, "Failed to create legacy entry logger file: %v", err)
+	legacyMetadata := make([]*entrylogger.EntryMetadata, 0, nEntries)
+	offset := 0
+	for entryID := 0; entryID < nEntries; entryID++ {
+		ledgerEntry := &pkg.LedgerEntry{EntryID: entryID, Payload: generatePayloadWithEntryID(entryID)}
+		data := ledgerEntry.Serialize()
+		_, err := legacyFile.Write(data)
+		utilities.Logger.FatalIfErr(err, "Failed to write legacy entry: %v", err)
+		legacyMetadata = append(legacyMetadata, entrylogger.NewEntryMetadata(entryID, offset, len(data)))
+		offset += len(data)
+	}
+	legacyFile.Close()
+
+	entryLogger, err := entrylogger.NewEntryLogger(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to open legacy entry logger: %v", err)
+	defer entryLogger.Close()
+
+	// Check: the legacy entries are read back.
+	utilities.Logger.Logf("Testing read legacy entries.")
+	for _, metadata := range legacyMetadata {
+		entry, err := entryLogger.Read(metadata.Offset, metadata.Size)
+		utilities.Logger.FatalIfErr(err, "Failed to read legacy entry: %v", err)
+		expectPayloadEq(t, generatePayloadWithEntryID(metadata.EntryID), entry.Payload)
+	}
+
+	// Check: new entries appended to the legacy file are read back.
+	utilities.Logger.Logf("Testing append entries to legacy file.")
+	err = entryLogger.Write(&pkg.LedgerEntry{EntryID: nEntries, Payload: generatePayloadWithEntryID(nEntries)})
+	utilities.Logger.FatalIfErr(err, "Failed to write entry: %v", err)
+	entryMetadata, err := entryLogger.Flush()
+	utilities.Logger.FatalIfErr(err, "Failed to flush entry logger: %v", err)
+	entry, err := entryLogger.Read(entryMetadata[0].Offset, entryMetadata[0].Size)
+	utilities.Logger.FatalIfErr(err, "Failed to read entry: %v", err)
+	expectPayloadEq(t, generatePayloadWithEntryID(nEntries), entry.Payload)
+
+	utilities.Logger.Logf("TestEntryLoggerLegacyFormat: %s", color.HiGreenString("PASS"))
+}
+
+func TestInterleavedEntryLogger(t *testing.T) {
+	utilities.Logger.Logf("TestInterleavedEntryLogger: Start.")
+	// The entries of the deleted ledger fill the first entry logs alone. The other ledgers flush concurrently.
+	const deletedLedgerID = uint64(20)
+	ledgerIDs := []uint64{21, 22}
+	const nEntries = 1000
+	const nEntriesPerFlush = 100
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	config.EntryLogger.Mode = pkg.EntryLoggerModeInterleaved
+	config.EntryLogger.MaxEntryLogSize = 16 << 10
+	config.EntryLogger.GCInterval = 1
+	entrylogger.Startup(&config.EntryLogger)
+	defer entrylogger.Stop()
+
+	deletedEntryLogger, err := entrylogger.NewEntryLogger(deletedLedgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to create entry logger: %v", err)
+	deletedEntryMetadata := writeAndFlushEntries(deletedEntryLogger, nEntries, nEntriesPerFlush)
+
+	// Check: the entries flushed together are adjacent in the entry log and read back with one read.
+	utilities.Logger.Logf("Testing concurrent flushes.")
+	entryLoggers := make(map[uint64]*entrylogger.EntryLogger)
+	entryMetadata := make(map[uint64][][]*entrylogger.EntryMetadata)
+	for _, ledgerID := range ledgerIDs {
+		entryLoggers[ledgerID], err = entrylogger.NewEntryLogger(ledgerID)
+		utilities.Logger.FatalIfErr(err, "Failed to create entry logger: %v", err)
+	}
+	wg := sync.WaitGroup{}
+	lock := sync.Mutex{}
+	for _, ledgerID := range ledgerIDs {
+		wg.Add(1)
+		go func(ledgerID uint64) {
+			defer wg.Done()
+			flushedEntryMetadata := writeAndFlushEntries(entryLoggers[ledgerID], nEntries, nEntriesPerFlush)
+			lock.Lock()
+			entryMetadata[ledgerID] = flushedEntryMetadata
+			lock.Unlock()
+		}(ledgerID)
+	}
+	wg.Wait()
+	for _, ledgerID := range ledgerIDs {
+		expectFlushedEntries(t, entryLoggers[ledgerID], entryMetadata[ledgerID])
+	}
+
+	// Check: an entry of another ledger is rejected.
+	_, err = entryLoggers[ledgerIDs[0]].Read(entryMetadata[ledgerIDs[1]][0][0].Offset, entryMetadata[ledgerIDs[1]][0][0].Size)
+	if !errors.Is(err, pkg.ErrEntryCorrupted) {
+		t.Fatalf("Expected an entry corruption error for an entry of another ledger, got %v.", err)
+	}
+
+	// Check: the entry logs of the deleted ledger are removed, and the entries of the other ledgers are kept.
+	utilities.Logger.Logf("Testing garbage collection.")
+	err = deletedEntryLogger.Delete()
+	utilities.Logger.FatalIfErr(err, "Failed to delete entry logger: %v", err)
+	entrylogger.EnableGC()
+	firstEntryLogFilePath := path.Join(config.EntryLogger.StoragePath, "entry_log_0.log")
+	utilities.WaitFor(waitTimeout, fmt.Sprintf("entry log %s to be removed", firstEntryLogFilePath), func() (bool, error) {
+		_, err := os.Stat(firstEntryLogFilePath)
+		if errors.Is(err, fs.ErrNotExist) {
+			return true, nil
+		}
+		return false, err
+	})
+	if _, err := deletedEntryLogger.Read(deletedEntryMetadata[0][0].Offset, deletedEntryMetadata[0][0].Size); err == nil {
+		t.Fatalf("Expected the entry of the deleted ledger to be removed.")
+	}
+	for _, ledgerID := range ledgerIDs {
+		expectFlushedEntries(t, entryLoggers[ledgerID], entryMetadata[ledgerID])
+	}
+
+	// Check: the entries are read back after the entry logs are reopened.
+	utilities.Logger.Logf("Testing reopen.")
+	entrylogger.Stop()
+	entrylogger.Startup(&config.EntryLogger)
+	for _, ledgerID := range ledgerIDs {
+		entryLogger, err := entrylogger.NewEntryLogger(ledgerID)
+		utilities.Logger.FatalIfErr(err, "Failed to create entry logger: %v", err)
+		expectFlushedEntries(t, entryLogger, entryMetadata[ledgerID])
+	}
+
+	utilities.Logger.Logf("TestInterleavedEntryLogger: %s", color.HiGreenString("PASS"))
+}
+
+func TestEntryLogCompaction(t *testing.T) {
+	utilities.Logger.Logf("TestEntryLogCompaction: Start.")
+	const deletedLedgerID = uint64(23)
+	const ledgerID = uint64(24)
+	const nEntries = 500
+	const nEntriesPerRound = 50
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	config.EntryLogger.Mode = pkg.EntryLoggerModeInterleaved
+	config.EntryLogger.MaxEntryLogSize = 16 << 10
+	config.EntryLogger.GCInterval = 1
+	config.EntryLogger.CompactionInterval = 1
+	config.EntryLogger.CompactionLiveRatio = 0.9
+	config.EntryLogger.CompactionRateLimit = 1 << 20
+	setup(config)
+
+	// The entries of the two ledgers are appended in rounds, each of which is flushed before the next one, so that the
+	// flushes of both ledgers are interleaved in each entry log.
+	ledgers := make([]*ledger.Ledger, 0)
+	for _, id := range []uint64{deletedLedgerID, ledgerID} {
+		thisLedger, err := ledger.NewLedger(id)
+		utilities.Logger.FatalIfErr(err, "Failed to create new ledger: %v", err)
+		ledgers = append(ledgers, thisLedger)
+	}
+	for entryID := 0; entryID < nEntries; entryID++ {
+		for _, thisLedger := range ledgers {
+			_, err := thisLedger.PutEntry(context.Background(), generatePayloadWithEntryID(entryID))
+			utilities.Logger.FatalIfErr(err, "Failed to put entry: %v", err)
+		}
+		if (entryID+1)%nEntriesPerRound == 0 {
+			for _, thisLedger := range ledgers {
+				waitForIndexedEntries(thisLedger, entryID+1)
+			}
+		}
+	}
+	sizeBeforeCompaction := entryLogsSize(config.EntryLogger.StoragePath)
+
+	// Check: the entry logs are compacted after a ledger is deleted, while the other ledger is read.
+	utilities.Logger.Logf("Testing compaction.")
+	thisLedger := ledgers[1]
+	err = ledgers[0].Delete()
+	utilities.Logger.FatalIfErr(err, "Failed to delete ledger: %v", err)
+	// A reader which began before the compaction keeps the compacted entry logs open.
+	endRead := entrylogger.BeginRead()
+	entrylogger.EnableGC()
+	stopReading := make(chan struct{})
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		for entryID := 0; ; entryID = (entryID + 1) % nEntries {
+			select {
+			case <-stopReading:
+				return
+			default:
+			}
+			entry, err := thisLedger.GetEntry(entryID)
+			utilities.Logger.FatalIfErr(err, "Failed to get entry during compaction: %v", err)
+			expectPayloadEq(t, generatePayloadWithEntryID(entryID), entry.Payload)
+		}
+	}()
+	workerDescriptions := entrylogger.GetWorkerDescriptions()
+	compactionWorker := workerDescriptions["entry_log_compaction_worker"]
+	utilities.WaitFor(waitTimeout, "the entry logs to be compacted", func() (bool, error) {
+		return compactionWorker.Processed() > 0, nil
+	})
+	// Wait for another round of the garbage collection, which would close the retired entry logs if no reader were
+	// left.
+	waitForWorkerRound(workerDescriptions["entry_log_gc_worker"])
+	close(stopReading)
+	<-readerDone
+	if countOpenRemovedEntryLogs() == 0 {
+		t.Fatalf("Expected the compacted entry logs to be kept open for the unfinished reader.")
+	}
+	endRead()
+	utilities.WaitFor(waitTimeout, "the compacted entry logs to be closed after the reader is done", func() (bool, error) {
+		return countOpenRemovedEntryLogs() == 0, nil
+	})
+	sizeAfterCompaction := entryLogsSize(config.EntryLogger.StoragePath)
+	if sizeAfterCompaction*4 > sizeBeforeCompaction*3 {
+		t.Fatalf("Expected the entry logs to shrink from %d bytes, got %d bytes.", sizeBeforeCompaction, sizeAfterCompaction)
+	}
+	entries, err := thisLedger.ReadEntries(0, nEntries-1, 0)
+	utilities.Logger.FatalIfErr(err, "Failed to read entries: %v", err)
+	if len(entries) != nEntries {
+		t.Fatalf("Expected %d entries, got %d.", nEntries, len(entries))
+	}
+	for entryID, entry := range entries {
+		expectPayloadEq(t, generatePayloadWithEntryID(entryID), entry.Payload)
+	}
+
+	// Check: the relocated entries survive the recovery.
+	utilities.Logger.Logf("Testing recovery after compaction.")
+	clean()
+	setup(config)
+	defer clean()
+	recoveredLedgers, err := recovery.Recover()
+	utilities.Logger.FatalIfErr(err, "Failed to recover ledgers: %v", err)
+	if len(recoveredLedgers) != 1 {
+		t.Fatalf("Expected 1 recovered ledger, got %d.", len(recoveredLedgers))
+	}
+	for entryID := 0; entryID < nEntries; entryID++ {
+		entry, err := recoveredLedgers[0].GetEntry(entryID)
+		utilities.Logger.FatalIfErr(err, "Failed to get entry: %v", err)
+		expectPayloadEq(t, generatePayloadWithEntryID(entryID), entry.Payload)
+	}
+
+	utilities.Logger.Logf("TestEntryLogCompaction: %s", color.HiGreenString("PASS"))
+}
+
+// countOpenRemovedEntryLogs returns the number of the interleaved entry logs which are removed but still open by this
+// process.
+func countOpenRemovedEntryLogs() int {
+	fdDir := "/proc/self/fd"
+	dirEntries, err := os.ReadDir(fdDir)
+	utilities.Logger.FatalIfErr(err, "Failed to read file descriptors: %v", err)
+	n := 0
+	for _, dirEntry := range dirEntries {
+		target, err := os.Readlink(path.Join(fdDir, dirEntry.Name()))
+		if err != nil {
+			continue
+		}
+		if strings.Contains(path.Base(target), "entry_log_") && strings.HasSuffix(target, " (deleted)") {
+			n++
+		}
+	}
+	return n
+}
+
+// waitForWorkerRound waits until the worker wakes up twice, so that it runs a whole round after the call.
+func waitForWorkerRound(workerDescription *pkg.WorkerDescription) {
+	for i := 0; i < 2; i++ {
+		since := time.Now()
+		utilities.WaitFor(waitTimeout, "a round of the worker", func() (bool, error) {
+			return workerDescription.LastHeartbeat().After(since), nil
+		})
+	}
+}
+
+// entryLogsSize returns the total size of the interleaved entry logs in the storage path.
+func entryLogsSize(storagePath string) int64 {
+	dirEntries, err := os.ReadDir(storagePath)
+	utilities.Logger.FatalIfErr(err, "Failed to read entry logger directory: %v", err)
+	size := int64(0)
+	for _, dirEntry := range dirEntries {
+		fileInfo, err := dirEntry.Info()
+		utilities.Logger.FatalIfErr(err, "Failed to stat entry log: %v", err)
+		size += fileInfo.Size()
+	}
+	return size
+}
+
+// writeAndFlushEntries writes nEntries entries to the entry logger and flushes them every nEntriesPerFlush entries.
+// The metadata of each flush is returned.
+func writeAndFlushEntries(entryLogger *entrylogger.EntryLogger, nEntries int, nEntriesPerFlush int) [][]*entrylogger.EntryMetadata {
+	flushedEntryMetadata := make([][]*entrylogger.EntryMetadata, 0)
+	for entryID := 0; entryID < nEntries; entryID++ {
+		err := entryLogger.Write(&pkg.LedgerEntry{EntryID: entryID, Payload: generatePayloadWithEntryID(entryID)})
+		utilities.Logger.FatalIfErr(err, "Failed to write entry: %v", err)
+		if (entryID+1)%nEntriesPerFlush == 0 {
+			entryMetadata, err := entryLogger.Flush()
+			utilities.Logger.FatalIfErr(err, "Failed to flush entry logger: %v", err)
+			flushedEntryMetadata = append(flushedEntryMetadata, entryMetadata)
+		}
+	}
+	return flushedEntryMetadata
+}
+
+// expectFlushedEntries reads back the entries of each flush with one read, which expects them to be adjacent.
+func expectFlushedEntries(t *testing.T, entryLogger *entrylogger.EntryLogger, flushedEntryMetadata [][]*entrylogger.EntryMetadata) {
+	for _, entryMetadata := range flushedEntryMetadata {
+		sizes := make([]int, 0, len(entryMetadata))
+		for _, metadata := range entryMetadata {
+			sizes = append(sizes, metadata.Size)
+		}
+		entries, err := entryLogger.ReadBatch(entryMetadata[0].Offset, sizes)
+		utilities.Logger.FatalIfErr(err, "Failed to read flushed entries: %v", err)
+		for i, entry := range entries {
+			if entry.EntryID != entryMetadata[i].EntryID {
+				t.Fatalf("Expected entry %d, got %d.", entryMetadata[i].EntryID, entry.EntryID)
+			}
+			expectPayloadEq(t, generatePayloadWithEntryID(entry.EntryID), entry.Payload)
+		}
+	}
+}
+
+func makeEntryLoggerFilePath(config *pkg.Config, ledgerID uint64) string {
+	if err := os.MkdirAll(config.EntryLogger.StoragePath, 0755); err != nil {
+		panic(err)
+	}
+	return path.Join(config.EntryLogger.StoragePath, fmt.Sprintf("ledger_%d.logger", ledgerID))
+}
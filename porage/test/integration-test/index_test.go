@@ -0,0 +1,345 @@
+package integrationtest_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"porage/internal/index"
+	"porage/internal/pkg"
+	"porage/test/utilities"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+// Test Sceanrio:
+//  1. The indexes of the ledgers in the shared index are isolated from each other in Get, Range, LastItem and Delete.
+//  2. The per-ledger indexes are migrated into the shared index when it is first opened.
+//  3. The flat index skips the holes and keeps its index values after it is reopened.
+//  4. The flat index keeps [Offset][Size] in 16-byte slots at entryID*16, so the files written before the append
+//     times were kept are read with unknown append times.
+//
+// Benchmark:
+//  1. Index.Get and Index.LastItem of the flat index and the Badger index.
+
+func TestSharedIndex(t *testing.T) {
+	utilities.Logger.Logf("TestSharedIndex: Start.")
+	// The ledgers are adjacent in the shared index, the first one with more entries than the second one.
+	const ledgerID = uint64(15)
+	const nextLedgerID = uint64(16)
+	const nEntries = 1000
+	const nNextLedgerEntries = nEntries / 2
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	config.IndexFile.Mode = pkg.IndexModeShared
+	index.Startup(&config.IndexFile)
+	defer index.Stop()
+
+	ledgerIndex, err := index.NewIndex(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to create index: %v", err)
+	defer ledgerIndex.Close()
+	nextLedgerIndex, err := index.NewIndex(nextLedgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to create index: %v", err)
+	defer nextLedgerIndex.Close()
+	putIndexValues(ledgerIndex, nEntries, 0)
+	putIndexValues(nextLedgerIndex, nNextLedgerEntries, 1)
+
+	// Check: the index values of each ledger are read back.
+	utilities.Logger.Logf("Testing get and range.")
+	expectIndexValues(t, ledgerIndex, nEntries, 0)
+	expectIndexValues(t, nextLedgerIndex, nNextLedgerEntries, 1)
+	nRangedEntries := 0
+	err = nextLedgerIndex.Range(0, nEntries, func(entryID int, value *index.IndexValue) bool {
+		nRangedEntries++
+		return true
+	})
+	utilities.Logger.FatalIfErr(err, "Failed to range index: %v", err)
+	if nRangedEntries != nNextLedgerEntries {
+		t.Fatalf("Expected %d entries in range, got %d.", nNextLedgerEntries, nRangedEntries)
+	}
+
+	// Check: the last item of each ledger is not from the other one.
+	utilities.Logger.Logf("Testing last item.")
+	expectLastIndexItem(t, ledgerIndex, nEntries-1)
+	expectLastIndexItem(t, nextLedgerIndex, nNextLedgerEntries-1)
+
+	// Check: deleting a ledger leaves the other one intact.
+	utilities.Logger.Logf("Testing delete.")
+	err = nextLedgerIndex.Delete()
+	utilities.Logger.FatalIfErr(err, "Failed to delete index: %v", err)
+	expectLastIndexItem(t, nextLedgerIndex, -1)
+	value, err := nextLedgerIndex.Get(0)
+	utilities.Logger.FatalIfErr(err, "Failed to get index: %v", err)
+	if value != nil {
+		t.Fatalf("Expected no index value after delete, got %v.", value)
+	}
+	expectIndexValues(t, ledgerIndex, nEntries, 0)
+	expectLastIndexItem(t, ledgerIndex, nEntries-1)
+
+	utilities.Logger.Logf("TestSharedIndex: %s", color.HiGreenString("PASS"))
+}
+
+func TestSharedIndexMigration(t *testing.T) {
+	utilities.Logger.Logf("TestSharedIndexMigration: Start.")
+	const ledgerID = uint64(17)
+	const nEntries = 1000
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+
+	// Write a per-ledger index.
+	config.IndexFile.Mode = pkg.IndexModePerLedger
+	index.Startup(&config.IndexFile)
+	perLedgerIndex, err := index.NewIndex(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to create index: %v", err)
+	putIndexValues(perLedgerIndex, nEntries, 0)
+	err = perLedgerIndex.Close()
+	utilities.Logger.FatalIfErr(err, "Failed to close index: %v", err)
+
+	// Check: the index values are migrated into the shared index and the per-ledger index is removed.
+	utilities.Logger.Logf("Testing migration.")
+	config.IndexFile.Mode = pkg.IndexModeShared
+	index.Startup(&config.IndexFile)
+	defer index.Stop()
+	sharedIndex, err := index.NewIndex(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to migrate index: %v", err)
+	defer sharedIndex.Close()
+	expectIndexValues(t, sharedIndex, nEntries, 0)
+	expectLastIndexItem(t, sharedIndex, nEntries-1)
+	perLedgerStoragePath := path.Join(config.IndexFile.StoragePath, fmt.Sprintf("ledger_%d", ledgerID))
+	if _, err := os.Stat(perLedgerStoragePath); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Expected per-ledger index %s to be removed, got %v.", perLedgerStoragePath, err)
+	}
+
+	utilities.Logger.Logf("TestSharedIndexMigration: %s", color.HiGreenString("PASS"))
+}
+
+func TestFlatIndex(t *testing.T) {
+	utilities.Logger.Logf("TestFlatIndex: Start.")
+	const ledgerID = uint64(18)
+	const nEntries = 100_000
+	const holeEntryID = nEntries / 2
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	config.IndexFile.Format = pkg.IndexFormatFlat
+	index.Startup(&config.IndexFile)
+
+	flatIndex, err := index.NewIndex(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to create index: %v", err)
+	expectLastIndexItem(t, flatIndex, -1)
+	putIndexValues(flatIndex, nEntries, 0)
+	// Clear a slot to leave a hole, like an entry ID skipped by the appends with client-assigned entry IDs.
+	err = flatIndex.Put(holeEntryID, &index.IndexValue{})
+	utilities.Logger.FatalIfErr(err, "Failed to put index: %v", err)
+
+	// Check: the hole is skipped by Get and Range.
+	utilities.Logger.Logf("Testing hole.")
+	value, err := flatIndex.Get(holeEntryID)
+	utilities.Logger.FatalIfErr(err, "Failed to get index: %v", err)
+	if value != nil {
+		t.Fatalf("Expected no index value in the hole, got %v.", value)
+	}
+	nRangedEntries := 0
+	err = flatIndex.Range(0, nEntries, func(entryID int, value *index.IndexValue) bool {
+		if entryID == holeEntryID {
+			t.Fatalf("Expected the hole to be skipped in range.")
+		}
+		nRangedEntries++
+		return true
+	})
+	utilities.Logger.FatalIfErr(err, "Failed to range index: %v", err)
+	if nRangedEntries != nEntries-1 {
+		t.Fatalf("Expected %d entries in range, got %d.", nEntries-1, nRangedEntries)
+	}
+	putIndexValues(flatIndex, holeEntryID+1, 0)
+
+	// Check: the index values and the last item are kept after the index is reopened.
+	utilities.Logger.Logf("Testing reopen.")
+	err = flatIndex.Sync()
+	utilities.Logger.FatalIfErr(err, "Failed to sync index: %v", err)
+	err = flatIndex.Close()
+	utilities.Logger.FatalIfErr(err, "Failed to close index: %v", err)
+	flatIndex, err = index.NewIndex(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to reopen index: %v", err)
+	expectIndexValues(t, flatIndex, nEntries, 0)
+	expectLastIndexItem(t, flatIndex, nEntries-1)
+	value, err = flatIndex.Get(nEntries)
+	utilities.Logger.FatalIfErr(err, "Failed to get index: %v", err)
+	if value != nil {
+		t.Fatalf("Expected no index value beyond the last entry, got %v.", value)
+	}
+
+	// Check: the index file is removed by delete.
+	utilities.Logger.Logf("Testing delete.")
+	err = flatIndex.Close()
+	utilities.Logger.FatalIfErr(err, "Failed to close index: %v", err)
+	err = flatIndex.Delete()
+	utilities.Logger.FatalIfErr(err, "Failed to delete index: %v", err)
+	flatIndex, err = index.NewIndex(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to recreate index: %v", err)
+	defer flatIndex.Close()
+	expectLastIndexItem(t, flatIndex, -1)
+
+	utilities.Logger.Logf("TestFlatIndex: %s", color.HiGreenString("PASS"))
+}
+
+func BenchmarkIndexGet(b *testing.B) {
+	const nEntries = 100_000
+	for _, format := range []string{pkg.IndexFormatFlat, pkg.IndexFormatBadger} {
+		b.Run(format, func(b *testing.B) {
+			benchmarkIndex := setupBenchmarkIndex(b, format, nEntries)
+			defer benchmarkIndex.Close()
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				if _, err := benchmarkIndex.Get(n % nEntries); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkIndexLastItem(b *testing.B) {
+	const nEntries = 100_000
+	for _, format := range []string{pkg.IndexFormatFlat, pkg.IndexFormatBadger} {
+		b.Run(format, func(b *testing.B) {
+			benchmarkIndex := setupBenchmarkIndex(b, format, nEntries)
+			defer benchmarkIndex.Close()
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				if _, _, err := benchmarkIndex.LastItem(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// setupBenchmarkIndex creates an index in the format with nEntries index values.
+func setupBenchmarkIndex(b *testing.B, format string, nEntries int) index.Index {
+	const ledgerID = uint64(19)
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		b.Fatal(err)
+	}
+	config.IndexFile.Format = format
+	index.Startup(&config.IndexFile)
+	benchmarkIndex, err := index.NewIndex(ledgerID)
+	if err != nil {
+		b.Fatal(err)
+	}
+	putIndexValues(benchmarkIndex, nEntries, 0)
+	return benchmarkIndex
+}
+
+// putIndexValues puts the index values of nEntries entries, whose sizes are shifted by sizeShift to tell the ledgers
+// apart. The sizes are positive like those of the entries in the entry logger.
+func TestFlatIndexLayout(t *testing.T) {
+	utilities.Logger.Logf("TestFlatIndexLayout: Start.")
+	const ledgerID = uint64(20)
+	const nEntries = 1000
+	const slotSize = 16
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	config.IndexFile.Format = pkg.IndexFormatFlat
+	index.Startup(&config.IndexFile)
+
+	// Write a flat index file by hand, as written before the append times were kept.
+	indexFilePath := path.Join(config.IndexFile.StoragePath, fmt.Sprintf("ledger_%d.index", ledgerID))
+	err = os.MkdirAll(config.IndexFile.StoragePath, 0755)
+	utilities.Logger.FatalIfErr(err, "Failed to create index directory: %v", err)
+	slots := make([]byte, nEntries*slotSize)
+	for entryID := 0; entryID < nEntries; entryID++ {
+		binary.BigEndian.PutUint64(slots[entryID*slotSize:], uint64(entryID*100))
+		binary.BigEndian.PutUint64(slots[entryID*slotSize+8:], uint64(entryID+1))
+	}
+	err = os.WriteFile(indexFilePath, slots, 0644)
+	utilities.Logger.FatalIfErr(err, "Failed to write index file: %v", err)
+
+	// Check: the index values of the existing file are read with unknown append times.
+	utilities.Logger.Logf("Testing existing index file.")
+	flatIndex, err := index.NewIndex(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to open index: %v", err)
+	expectIndexValues(t, flatIndex, nEntries, 0)
+	expectLastIndexItem(t, flatIndex, nEntries-1)
+	value, err := flatIndex.Get(0)
+	utilities.Logger.FatalIfErr(err, "Failed to get index: %v", err)
+	if value.AppendTime != 0 {
+		t.Fatalf("Expected an unknown append time, got %d.", value.AppendTime)
+	}
+
+	// Check: a new index value keeps its append time across a reopen, and its slot stays at entryID*16.
+	utilities.Logger.Logf("Testing append time.")
+	newValue := &index.IndexValue{Offset: nEntries * 100, Size: nEntries + 1, AppendTime: 1_700_000_000_000}
+	err = flatIndex.Put(nEntries, newValue)
+	utilities.Logger.FatalIfErr(err, "Failed to put index: %v", err)
+	err = flatIndex.Close()
+	utilities.Logger.FatalIfErr(err, "Failed to close index: %v", err)
+	flatIndex, err = index.NewIndex(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to reopen index: %v", err)
+	defer flatIndex.Close()
+	value, err = flatIndex.Get(nEntries)
+	utilities.Logger.FatalIfErr(err, "Failed to get index: %v", err)
+	if value == nil || *value != *newValue {
+		t.Fatalf("Expected %v, got %v.", newValue, value)
+	}
+	indexFile, err := os.ReadFile(indexFilePath)
+	utilities.Logger.FatalIfErr(err, "Failed to read index file: %v", err)
+	slot := indexFile[nEntries*slotSize : (nEntries+1)*slotSize]
+	if binary.BigEndian.Uint64(slot[:8]) != uint64(newValue.Offset) || binary.BigEndian.Uint64(slot[8:]) != uint64(newValue.Size) {
+		t.Fatalf("Expected [Offset][Size] in the slot of entry %d, got %v.", nEntries, slot)
+	}
+
+	utilities.Logger.Logf("TestFlatIndexLayout: %s", color.HiGreenString("PASS"))
+}
+
+func putIndexValues(ledgerIndex index.Index, nEntries int, sizeShift int) {
+	for entryID := 0; entryID < nEntries; entryID++ {
+		err := ledgerIndex.Put(entryID, &index.IndexValue{Offset: entryID * 100, Size: entryID + sizeShift + 1})
+		utilities.Logger.FatalIfErr(err, "Failed to put index: %v", err)
+	}
+}
+
+func expectIndexValues(t *testing.T, ledgerIndex index.Index, nEntries int, sizeShift int) {
+	for entryID := 0; entryID < nEntries; entryID++ {
+		value, err := ledgerIndex.Get(entryID)
+		utilities.Logger.FatalIfErr(err, "Failed to get index: %v", err)
+		if value == nil || value.Offset != entryID*100 || value.Size != entryID+sizeShift+1 {
+			t.Fatalf("Index value of entry %d mismatch, got %v.", entryID, value)
+		}
+	}
+}
+
+// expectLastIndexItem checks the last entryID in the index, which is -1 if the index is empty.
+func expectLastIndexItem(t *testing.T, ledgerIndex index.Index, expectedEntryID int) {
+	entryID, value, err := ledgerIndex.LastItem()
+	utilities.Logger.FatalIfErr(err, "Failed to get last item: %v", err)
+	if expectedEntryID == -1 {
+		if value != nil {
+			t.Fatalf("Expected no last item, got entry %d.", entryID)
+		}
+		return
+	}
+	if value == nil || entryID != expectedEntryID {
+		t.Fatalf("Expected last entry %d, got entry %d with %v.", expectedEntryID, entryID, value)
+	}
+}
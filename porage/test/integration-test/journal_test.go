@@ -0,0 +1,257 @@
+package integrationtest_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"porage/internal/journal"
+	"porage/internal/pkg"
+	"porage/internal/recovery"
+	"porage/test/utilities"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Test Sceanrio:
+//  1. A torn journal entry at the tail of a segment is truncated away.
+//  2. A zero-filled tail of a segment, allocated but never written before a crash, is truncated away.
+//  3. A partially written last journal entry followed only by zeros is truncated away, whether its header or its
+//     payload is partially written.
+//  4. A corrupted journal entry in the middle of a segment fails the recovery with a typed error, whether its
+//     payload or its size is corrupted.
+
+func TestJournalTornWrite(t *testing.T) {
+	utilities.Logger.Logf("TestJournalTornWrite: Start.")
+	const ledgerID = uint64(3)
+	const nEntries = 1000
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+
+	// Generate a segment whose last journal entry is only half written.
+	segmentFilePath, recordOffsets := generateJournalSegment(config, ledgerID, nEntries+1)
+	validSize := recordOffsets[nEntries]
+	fileInfo, err := os.Stat(segmentFilePath)
+	utilities.Logger.FatalIfErr(err, "Failed to stat segment file: %v", err)
+	tornSize := validSize + (fileInfo.Size()-validSize)/2
+	err = os.Truncate(segmentFilePath, tornSize)
+	utilities.Logger.FatalIfErr(err, "Failed to truncate segment file: %v", err)
+
+	setup(config)
+	defer clean()
+
+	// Check: all the complete journal entries are read and the torn one is truncated.
+	utilities.Logger.Logf("Testing read journal with a torn entry.")
+	entries, _, err := journal.ReadJournal(0)
+	utilities.Logger.FatalIfErr(err, "Failed to read journal: %v", err)
+	if len(entries) != nEntries {
+		t.Fatalf("Expected %d journal entries, got %d.", nEntries, len(entries))
+	}
+	for i, entry := range entries {
+		expectPayloadEq(t, generatePayloadWithEntryID(i), entry.Entry.Payload)
+	}
+	fileInfo, err = os.Stat(segmentFilePath)
+	utilities.Logger.FatalIfErr(err, "Failed to stat segment file: %v", err)
+	if fileInfo.Size() != validSize {
+		t.Fatalf("Expected the segment to be truncated to %d bytes, got %d.", validSize, fileInfo.Size())
+	}
+
+	utilities.Logger.Logf("TestJournalTornWrite: %s", color.HiGreenString("PASS"))
+}
+
+func TestJournalZeroFilledTail(t *testing.T) {
+	utilities.Logger.Logf("TestJournalZeroFilledTail: Start.")
+	const ledgerID = uint64(12)
+	const nEntries = 1000
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+
+	// Generate a segment followed by zeros, as left by a crash after the file size is extended but before the data
+	// is written.
+	segmentFilePath, recordOffsets := generateJournalSegment(config, ledgerID, nEntries)
+	validSize := recordOffsets[nEntries]
+	err = os.Truncate(segmentFilePath, validSize+4096)
+	utilities.Logger.FatalIfErr(err, "Failed to extend segment file: %v", err)
+
+	setup(config)
+	defer clean()
+
+	// Check: all the journal entries are read and the zeros are truncated.
+	utilities.Logger.Logf("Testing read journal with a zero-filled tail.")
+	entries, _, err := journal.ReadJournal(0)
+	utilities.Logger.FatalIfErr(err, "Failed to read journal: %v", err)
+	if len(entries) != nEntries {
+		t.Fatalf("Expected %d journal entries, got %d.", nEntries, len(entries))
+	}
+	fileInfo, err := os.Stat(segmentFilePath)
+	utilities.Logger.FatalIfErr(err, "Failed to stat segment file: %v", err)
+	if fileInfo.Size() != validSize {
+		t.Fatalf("Expected the segment to be truncated to %d bytes, got %d.", validSize, fileInfo.Size())
+	}
+
+	utilities.Logger.Logf("TestJournalZeroFilledTail: %s", color.HiGreenString("PASS"))
+}
+
+func TestJournalPartialTail(t *testing.T) {
+	utilities.Logger.Logf("TestJournalPartialTail: Start.")
+	const nEntries = 1000
+	// recordHeaderSize is the size of [Size][SequenceID][HeaderChecksum][Checksum] of a journal record.
+	const recordHeaderSize = 8 + 8 + 4 + 4
+
+	// The written bytes of the last journal entry: the size and the sequence ID of its header, or its header and the
+	// first half of its payload. The rest of the entry is zeros, followed by the zeros of the extended file.
+	tails := []struct {
+		name         string
+		ledgerID     uint64
+		writtenBytes func(recordSize int64) int64
+	}{
+		{"header", 21, func(recordSize int64) int64 { return 8 + 8 }},
+		{"payload", 22, func(recordSize int64) int64 { return recordHeaderSize + (recordSize-recordHeaderSize)/2 }},
+	}
+	for _, tail := range tails {
+		utilities.Logger.Logf("Testing read journal with a partially written %s.", tail.name)
+		testJournalPartialTail(t, tail.ledgerID, nEntries, tail.writtenBytes)
+	}
+
+	utilities.Logger.Logf("TestJournalPartialTail: %s", color.HiGreenString("PASS"))
+}
+
+// testJournalPartialTail keeps only writtenBytes of the last journal entry in a segment of nEntries+1 entries and
+// zero-fills the rest of the segment, and checks that the entry is truncated away.
+func testJournalPartialTail(t *testing.T, ledgerID uint64, nEntries int, writtenBytesOf func(recordSize int64) int64) {
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+
+	segmentFilePath, recordOffsets := generateJournalSegment(config, ledgerID, nEntries+1)
+	validSize := recordOffsets[nEntries]
+	recordSize := recordOffsets[nEntries+1] - validSize
+	writtenSize := validSize + writtenBytesOf(recordSize)
+	err = os.Truncate(segmentFilePath, writtenSize)
+	utilities.Logger.FatalIfErr(err, "Failed to truncate segment file: %v", err)
+	err = os.Truncate(segmentFilePath, validSize+recordSize+4096)
+	utilities.Logger.FatalIfErr(err, "Failed to extend segment file: %v", err)
+
+	setup(config)
+	defer clean()
+
+	// Check: all the complete journal entries are read and the partial one is truncated.
+	entries, _, err := journal.ReadJournal(0)
+	utilities.Logger.FatalIfErr(err, "Failed to read journal: %v", err)
+	if len(entries) != nEntries {
+		t.Fatalf("Expected %d journal entries, got %d.", nEntries, len(entries))
+	}
+	fileInfo, err := os.Stat(segmentFilePath)
+	utilities.Logger.FatalIfErr(err, "Failed to stat segment file: %v", err)
+	if fileInfo.Size() != validSize {
+		t.Fatalf("Expected the segment to be truncated to %d bytes, got %d.", validSize, fileInfo.Size())
+	}
+}
+
+func TestJournalCorruption(t *testing.T) {
+	utilities.Logger.Logf("TestJournalCorruption: Start.")
+	const nEntries = 1000
+	const corruptedEntryID = nEntries / 2
+
+	// The corrupted bytes of the journal entry in the middle: the last byte of its payload, and the first byte of its
+	// size, which makes the entry look longer than the rest of the segment.
+	corruptions := []struct {
+		name                string
+		ledgerID            uint64
+		corruptedByteOffset func(recordOffsets []int64) int64
+	}{
+		{"payload", 4, func(recordOffsets []int64) int64 { return recordOffsets[corruptedEntryID+1] - 1 }},
+		{"size", 16, func(recordOffsets []int64) int64 { return recordOffsets[corruptedEntryID] }},
+	}
+	for _, corruption := range corruptions {
+		utilities.Logger.Logf("Testing recovery with a corrupted %s.", corruption.name)
+		testJournalCorruption(t, corruption.ledgerID, nEntries, corruptedEntryID, corruption.corruptedByteOffset)
+	}
+
+	utilities.Logger.Logf("TestJournalCorruption: %s", color.HiGreenString("PASS"))
+}
+
+// testJournalCorruption flips a byte of the journal entry corruptedEntryID in a segment of nEntries entries, and
+// checks that the recovery fails with the segment and the offset of the entry.
+func testJournalCorruption(t *testing.T, ledgerID uint64, nEntries int, corruptedEntryID int,
+	corruptedByteOffsetOf func(recordOffsets []int64) int64) {
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+
+	segmentFilePath, recordOffsets := generateJournalSegment(config, ledgerID, nEntries)
+	file, err := os.OpenFile(segmentFilePath, os.O_RDWR, 0644)
+	utilities.Logger.FatalIfErr(err, "Failed to open segment file: %v", err)
+	corruptedByteOffset := corruptedByteOffsetOf(recordOffsets)
+	corruptedByte := make([]byte, 1)
+	_, err = file.ReadAt(corruptedByte, corruptedByteOffset)
+	utilities.Logger.FatalIfErr(err, "Failed to read segment file: %v", err)
+	corruptedByte[0] ^= 0xFF
+	_, err = file.WriteAt(corruptedByte, corruptedByteOffset)
+	utilities.Logger.FatalIfErr(err, "Failed to write segment file: %v", err)
+	file.Close()
+
+	setup(config)
+	defer clean()
+
+	// Check: the recovery fails with the segment and the offset of the corrupted entry.
+	_, err = recovery.Recover()
+	var corruptionErr *pkg.JournalCorruptionError
+	if !errors.As(err, &corruptionErr) || !errors.Is(err, pkg.ErrJournalCorrupted) {
+		t.Fatalf("Expected a journal corruption error, got %v.", err)
+	}
+	if corruptionErr.SegmentPath != segmentFilePath || corruptionErr.Offset != recordOffsets[corruptedEntryID] {
+		t.Fatalf("Expected corruption at %s:%d, got %s:%d.", segmentFilePath, recordOffsets[corruptedEntryID],
+			corruptionErr.SegmentPath, corruptionErr.Offset)
+	}
+}
+
+// generateJournalSegment writes a journal segment with nEntries entries of the ledger. The path of the segment
+// and the offsets of the entries in the segment(followed by the segment size) are returned.
+func generateJournalSegment(config *pkg.Config, ledgerID uint64, nEntries int) (string, []int64) {
+	if err := os.MkdirAll(config.Journal.StoragePath, 0755); err != nil {
+		panic(err)
+	}
+	segmentFilePath := filepath.Join(config.Journal.StoragePath, fmt.Sprintf("%v.journal", time.Now().UnixNano()))
+	segmentFile, err := os.Create(segmentFilePath)
+	if err != nil {
+		panic(err)
+	}
+	defer segmentFile.Close()
+
+	recordOffsets := make([]int64, 0, nEntries+1)
+	recordOffsets = append(recordOffsets, 0)
+	for entryID := 0; entryID < nEntries; entryID++ {
+		journalEntry := journal.NewJournalEntry(&pkg.JournalEntryPayload{
+			LedgerID: ledgerID,
+			EntryID:  entryID,
+			Payload:  generatePayloadWithEntryID(entryID),
+		})
+		if err := journalEntry.WriteTo(segmentFile); err != nil {
+			panic(err)
+		}
+		fileInfo, err := segmentFile.Stat()
+		if err != nil {
+			panic(err)
+		}
+		recordOffsets = append(recordOffsets, fileInfo.Size())
+	}
+	if err := segmentFile.Sync(); err != nil {
+		panic(err)
+	}
+	return segmentFilePath, recordOffsets
+}
@@ -2,6 +2,8 @@ package integrationtest_test
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -12,6 +14,7 @@ import (
 	"porage/internal/memtable"
 	"porage/internal/pkg"
 	"porage/internal/recovery"
+	porage "porage/pkg"
 	"porage/test/utilities"
 	"sync"
 	"testing"
@@ -24,14 +27,27 @@ import (
 // Test Sceanrio:
 //  1. Write a large number of entries to the ledger.
 //  2. Read the entries back from the ledger in a tailing read and catch up read manner.
-//  3. Recovery.
+//  3. Recovery, which keeps the append time of the entries recorded in the journal.
 //  4. Delete.
 //  5. Journal Trim.
+//  6. Fence, which survives the recovery.
+//  7. Idempotent append with client-assigned entry IDs, including holes filled after the later entries.
+//  8. Close, which keeps the ledger readable and survives the recovery.
+//  9. Range read merging the entry logger and the memtable.
+//  10. Tailing read, which ends when the ledger is closed or deleted.
+//  11. Batched appends, which are assigned consecutive entry IDs and survive the recovery.
+//  12. Appends whose context is done before or after the entry is accepted.
+//  13. Trim, which makes the earlier entries unreadable and survives the recovery.
+//  14. Retention policies by entries, bytes and age, which survive the recovery.
+//  15. Ledger metadata with custom properties, which survives the recovery, including the legacy empty ledger files.
 
 var (
 	dataDir = "./_data"
 )
 
+// waitTimeout bounds the waits for the background workers, such as the flushes of the entry logger.
+const waitTimeout = 30 * time.Second
+
 func TestNewLedger(t *testing.T) {
 	utilities.Logger.Logf("TestNewLedger: Start.")
 	const ledgerID = uint64(1)
@@ -65,7 +81,7 @@ func TestNewLedger(t *testing.T) {
 		expectedEntryPayload := generatePayloadWithEntryID(entryID)
 
 		go func() {
-			entryID, err := ledger.PutEntry(expectedEntryPayload)
+			entryID, err := ledger.PutEntry(context.Background(), expectedEntryPayload)
 			expectedDb.Store(entryID, expectedEntryPayload)
 			utilities.Logger.FatalIfErr(err, "Failed to put entry: %v", err)
 			writeWaitGroup.Done()
@@ -117,10 +133,10 @@ func TestNewLedger(t *testing.T) {
 	}
 	utilities.Logger.Logf("All journal segment files are trimmed.")
 
-	// Check: Close ledger
-	utilities.Logger.Logf("Testing close ledger.")
-	err = ledger.Close()
-	utilities.Logger.FatalIfErr(err, "Failed to close ledger: %v", err)
+	// Check: Delete ledger
+	utilities.Logger.Logf("Testing delete ledger.")
+	err = ledger.Delete()
+	utilities.Logger.FatalIfErr(err, "Failed to delete ledger: %v", err)
 
 	utilities.Logger.Logf("TestLedger: %s | Write Time: %v, Read Time: %v, Number of Iterations: %v(%s)", color.HiGreenString("PASS"), writeConsumedTime,
 		readConsumedTime, nIterations, nIterationsWord)
@@ -131,6 +147,8 @@ func TestRecovery(t *testing.T) {
 	const ledgerID = uint64(2)
 	const nIterations = 500_000
 	const nJournalSegments = 2
+	// The entries are appended an hour before the recovery, which must keep their append time.
+	appendTime := time.Now().Add(-time.Hour).UnixMilli()
 
 	setCleanEnvironment()
 	config, err := pkg.ParseConfigFile("./config.toml")
@@ -167,9 +185,10 @@ func TestRecovery(t *testing.T) {
 			entryID := i + nIterations*segmentID
 			entryPayload := generatePayloadWithEntryID(entryID)
 			journalEntry := journal.NewJournalEntry(&pkg.JournalEntryPayload{
-				LedgerID: ledgerID,
-				EntryID:  entryID,
-				Payload:  entryPayload,
+				LedgerID:   ledgerID,
+				EntryID:    entryID,
+				AppendTime: appendTime,
+				Payload:    entryPayload,
 			})
 			err := journalEntry.WriteTo(journalFile)
 			if err != nil {
@@ -207,9 +226,959 @@ func TestRecovery(t *testing.T) {
 		expectPayloadEq(t, entryPayload, entry.Payload)
 	}
 
+	// Check the append time of the recovered entries once they are flushed.
+	waitForIndexedEntries(ledger, nIterations*nJournalSegments)
+	info, err := ledger.Info()
+	utilities.Logger.FatalIfErr(err, "Failed to get ledger info: %v", err)
+	if info.LastAppendTime.UnixMilli() != appendTime {
+		t.Fatalf("Expected last append time %d from the journal, got %d", appendTime, info.LastAppendTime.UnixMilli())
+	}
+
 	utilities.Logger.Logf("TestRecovery: %s", color.HiGreenString("PASS"))
 }
 
+func TestFenceLedger(t *testing.T) {
+	utilities.Logger.Logf("TestFenceLedger: Start.")
+	const ledgerID = uint64(7)
+	const nEntries = 1000
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	setup(config)
+
+	thisLedger, err := ledger.NewLedger(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to create new ledger: %v", err)
+	for entryID := 0; entryID < nEntries; entryID++ {
+		_, err := thisLedger.PutEntry(context.Background(), generatePayloadWithEntryID(entryID))
+		utilities.Logger.FatalIfErr(err, "Failed to put entry: %v", err)
+	}
+
+	// Check: fence returns the last entry ID and rejects the following appends.
+	utilities.Logger.Logf("Testing fence ledger.")
+	lastEntryID, err := thisLedger.Fence()
+	utilities.Logger.FatalIfErr(err, "Failed to fence ledger: %v", err)
+	if lastEntryID != nEntries-1 {
+		t.Fatalf("Expected last entry ID %d, got %d.", nEntries-1, lastEntryID)
+	}
+	if _, err := thisLedger.PutEntry(context.Background(), generatePayloadWithEntryID(nEntries)); !errors.Is(err, porage.ErrLedgerFenced) {
+		t.Fatalf("Expected %v, got %v.", porage.ErrLedgerFenced, err)
+	}
+	lastEntryID, err = thisLedger.Fence()
+	utilities.Logger.FatalIfErr(err, "Failed to fence ledger again: %v", err)
+	if lastEntryID != nEntries-1 {
+		t.Fatalf("Expected last entry ID %d after fencing again, got %d.", nEntries-1, lastEntryID)
+	}
+
+	// Check: the fenced state survives the recovery.
+	utilities.Logger.Logf("Testing fenced ledger recovery.")
+	clean()
+	setup(config)
+	defer clean()
+	ledgers, err := recovery.Recover()
+	utilities.Logger.FatalIfErr(err, "Failed to recover ledgers: %v", err)
+	if len(ledgers) != 1 {
+		t.Fatalf("Expected 1 recovered ledger, got %d.", len(ledgers))
+	}
+	thisLedger = ledgers[0]
+	if thisLedger.State() != ledger.LedgerStateFenced {
+		t.Fatalf("Expected the recovered ledger to be %v, got %v.", ledger.LedgerStateFenced, thisLedger.State())
+	}
+	if _, err := thisLedger.PutEntry(context.Background(), generatePayloadWithEntryID(nEntries)); !errors.Is(err, porage.ErrLedgerFenced) {
+		t.Fatalf("Expected %v after recovery, got %v.", porage.ErrLedgerFenced, err)
+	}
+	for entryID := 0; entryID < nEntries; entryID++ {
+		entry, err := thisLedger.GetEntry(entryID)
+		utilities.Logger.FatalIfErr(err, "Failed to get entry: %v", err)
+		if entry == nil {
+			t.Fatalf("Entry %d not found.", entryID)
+		}
+		expectPayloadEq(t, generatePayloadWithEntryID(entryID), entry.Payload)
+	}
+
+	utilities.Logger.Logf("TestFenceLedger: %s", color.HiGreenString("PASS"))
+}
+
+func TestAppendEntryWithID(t *testing.T) {
+	utilities.Logger.Logf("TestAppendEntryWithID: Start.")
+	const ledgerID = uint64(8)
+	const nEntries = 10000
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	setup(config)
+
+	thisLedger, err := ledger.NewLedger(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to create new ledger: %v", err)
+
+	// Check: entries with even IDs are appended concurrently, leaving holes at odd IDs.
+	utilities.Logger.Logf("Testing append entries with ID.")
+	writeWaitGroup := sync.WaitGroup{}
+	for entryID := 0; entryID < nEntries; entryID += 2 {
+		writeWaitGroup.Add(1)
+		go func() {
+			defer writeWaitGroup.Done()
+			err := thisLedger.PutEntryWithID(context.Background(), entryID, generatePayloadWithEntryID(entryID))
+			utilities.Logger.FatalIfErr(err, "Failed to put entry %d: %v", entryID, err)
+		}()
+	}
+	writeWaitGroup.Wait()
+	for entryID := 0; entryID < nEntries; entryID++ {
+		entry, err := thisLedger.GetEntry(entryID)
+		utilities.Logger.FatalIfErr(err, "Failed to get entry: %v", err)
+		if entryID%2 == 1 && entry != nil {
+			t.Fatalf("Entry %d should not exist.", entryID)
+		}
+		if entryID%2 == 0 && entry == nil {
+			t.Fatalf("Entry %d not found.", entryID)
+		}
+		if entryID%2 == 0 {
+			expectPayloadEq(t, generatePayloadWithEntryID(entryID), entry.Payload)
+		}
+	}
+
+	// Check: appending the same entry again is a no-op and appending a different payload fails.
+	utilities.Logger.Logf("Testing duplicated and conflicting appends.")
+	err = thisLedger.PutEntryWithID(context.Background(), 0, generatePayloadWithEntryID(0))
+	utilities.Logger.FatalIfErr(err, "Failed to put duplicated entry: %v", err)
+	if err := thisLedger.PutEntryWithID(context.Background(), 0, generatePayloadWithEntryID(1)); !errors.Is(err, porage.ErrEntryIDConflict) {
+		t.Fatalf("Expected %v, got %v.", porage.ErrEntryIDConflict, err)
+	}
+	if err := thisLedger.PutEntryWithID(context.Background(), -1, generatePayloadWithEntryID(0)); !errors.Is(err, porage.ErrInvalidEntryID) {
+		t.Fatalf("Expected %v, got %v.", porage.ErrInvalidEntryID, err)
+	}
+	entryID, err := thisLedger.PutEntry(context.Background(), generatePayloadWithEntryID(nEntries-1))
+	utilities.Logger.FatalIfErr(err, "Failed to put entry: %v", err)
+	if entryID != nEntries-1 {
+		t.Fatalf("Expected the server-assigned entry ID %d, got %d.", nEntries-1, entryID)
+	}
+
+	// Check: the holes are filled after the even entries are flushed, and duplicated again after being flushed.
+	utilities.Logger.Logf("Testing fill holes.")
+	waitForIndexedEntries(thisLedger, nEntries/2+1)
+	for entryID := 1; entryID < nEntries; entryID += 2 {
+		err := thisLedger.PutEntryWithID(context.Background(), entryID, generatePayloadWithEntryID(entryID))
+		utilities.Logger.FatalIfErr(err, "Failed to fill entry %d: %v", entryID, err)
+	}
+	waitForIndexedEntries(thisLedger, nEntries)
+	err = thisLedger.PutEntryWithID(context.Background(), 1, generatePayloadWithEntryID(1))
+	utilities.Logger.FatalIfErr(err, "Failed to put duplicated entry: %v", err)
+	if err := thisLedger.PutEntryWithID(context.Background(), 1, generatePayloadWithEntryID(0)); !errors.Is(err, porage.ErrEntryIDConflict) {
+		t.Fatalf("Expected %v after flush, got %v.", porage.ErrEntryIDConflict, err)
+	}
+
+	// Check: all the entries survive the recovery.
+	utilities.Logger.Logf("Testing recovery of entries with ID.")
+	clean()
+	setup(config)
+	defer clean()
+	ledgers, err := recovery.Recover()
+	utilities.Logger.FatalIfErr(err, "Failed to recover ledgers: %v", err)
+	thisLedger = ledgers[0]
+	for entryID := 0; entryID < nEntries; entryID++ {
+		entry, err := thisLedger.GetEntry(entryID)
+		utilities.Logger.FatalIfErr(err, "Failed to get entry: %v", err)
+		if entry == nil {
+			t.Fatalf("Entry %d not found after recovery.", entryID)
+		}
+		expectPayloadEq(t, generatePayloadWithEntryID(entryID), entry.Payload)
+	}
+
+	utilities.Logger.Logf("TestAppendEntryWithID: %s", color.HiGreenString("PASS"))
+}
+
+func TestCloseLedger(t *testing.T) {
+	utilities.Logger.Logf("TestCloseLedger: Start.")
+	const ledgerID = uint64(9)
+	const nEntries = 1000
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	setup(config)
+
+	thisLedger, err := ledger.NewLedger(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to create new ledger: %v", err)
+	for entryID := 0; entryID < nEntries; entryID++ {
+		_, err := thisLedger.PutEntry(context.Background(), generatePayloadWithEntryID(entryID))
+		utilities.Logger.FatalIfErr(err, "Failed to put entry: %v", err)
+	}
+
+	// Check: close returns the last entry ID, rejects the following appends and can not be undone by fencing.
+	utilities.Logger.Logf("Testing close ledger.")
+	lastEntryID, err := thisLedger.Close()
+	utilities.Logger.FatalIfErr(err, "Failed to close ledger: %v", err)
+	if lastEntryID != nEntries-1 {
+		t.Fatalf("Expected last entry ID %d, got %d.", nEntries-1, lastEntryID)
+	}
+	expectClosedLedger(t, thisLedger, nEntries)
+	_, err = thisLedger.Fence()
+	utilities.Logger.FatalIfErr(err, "Failed to fence closed ledger: %v", err)
+	if thisLedger.State() != ledger.LedgerStateClosed {
+		t.Fatalf("Expected the ledger to stay %v after fencing, got %v.", ledger.LedgerStateClosed, thisLedger.State())
+	}
+
+	// Check: the closed state survives the recovery.
+	utilities.Logger.Logf("Testing closed ledger recovery.")
+	clean()
+	setup(config)
+	defer clean()
+	ledgers, err := recovery.Recover()
+	utilities.Logger.FatalIfErr(err, "Failed to recover ledgers: %v", err)
+	thisLedger = ledgers[0]
+	if thisLedger.State() != ledger.LedgerStateClosed {
+		t.Fatalf("Expected the recovered ledger to be %v, got %v.", ledger.LedgerStateClosed, thisLedger.State())
+	}
+	expectClosedLedger(t, thisLedger, nEntries)
+
+	utilities.Logger.Logf("TestCloseLedger: %s", color.HiGreenString("PASS"))
+}
+
+func TestReadEntries(t *testing.T) {
+	utilities.Logger.Logf("TestReadEntries: Start.")
+	const ledgerID = uint64(10)
+	const nEntries = 10000
+	isMissing := func(entryID int) bool { return entryID%10 == 9 }
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	setup(config)
+
+	// Write the first half of the entries, with a hole in every ten entries, and restart so that they are only in
+	// the entry logger. Then write the second half into the memtable.
+	thisLedger, err := ledger.NewLedger(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to create new ledger: %v", err)
+	putEntries := func(fromEntryID int, toEntryID int) {
+		writeWaitGroup := sync.WaitGroup{}
+		for entryID := fromEntryID; entryID < toEntryID; entryID++ {
+			if isMissing(entryID) {
+				continue
+			}
+			writeWaitGroup.Add(1)
+			go func() {
+				defer writeWaitGroup.Done()
+				err := thisLedger.PutEntryWithID(context.Background(), entryID, generatePayloadWithEntryID(entryID))
+				utilities.Logger.FatalIfErr(err, "Failed to put entry: %v", err)
+			}()
+		}
+		writeWaitGroup.Wait()
+	}
+	putEntries(0, nEntries/2)
+	nPutEntries := 0
+	for entryID := 0; entryID < nEntries/2; entryID++ {
+		if !isMissing(entryID) {
+			nPutEntries++
+		}
+	}
+	waitForIndexedEntries(thisLedger, nPutEntries)
+	clean()
+	setup(config)
+	defer clean()
+	ledgers, err := recovery.Recover()
+	utilities.Logger.FatalIfErr(err, "Failed to recover ledgers: %v", err)
+	thisLedger = ledgers[0]
+	putEntries(nEntries/2, nEntries)
+
+	expectEntries := func(entries []*pkg.LedgerEntry, fromEntryID int, toEntryID int) {
+		expectedEntryID := fromEntryID
+		for _, entry := range entries {
+			for isMissing(expectedEntryID) {
+				expectedEntryID++
+			}
+			if entry.EntryID != expectedEntryID {
+				t.Fatalf("Expected entry %d, got %d.", expectedEntryID, entry.EntryID)
+			}
+			expectPayloadEq(t, generatePayloadWithEntryID(entry.EntryID), entry.Payload)
+			expectedEntryID++
+		}
+		for isMissing(expectedEntryID) {
+			expectedEntryID++
+		}
+		if expectedEntryID <= toEntryID {
+			t.Fatalf("Expected entries until %d, got until %d.", toEntryID, expectedEntryID-1)
+		}
+	}
+
+	// Check: read all the entries, a range across the entry logger and the memtable, and an empty range.
+	utilities.Logger.Logf("Testing read entries.")
+	entries, err := thisLedger.ReadEntries(0, nEntries*2, 0)
+	utilities.Logger.FatalIfErr(err, "Failed to read entries: %v", err)
+	expectEntries(entries, 0, nEntries-1)
+	entries, err = thisLedger.ReadEntries(nEntries/2-55, nEntries/2+55, 0)
+	utilities.Logger.FatalIfErr(err, "Failed to read entries: %v", err)
+	expectEntries(entries, nEntries/2-55, nEntries/2+55)
+	entries, err = thisLedger.ReadEntries(nEntries, nEntries*2, 0)
+	utilities.Logger.FatalIfErr(err, "Failed to read entries: %v", err)
+	if len(entries) != 0 {
+		t.Fatalf("Expected no entry beyond the last entry, got %d.", len(entries))
+	}
+
+	// Check: read all the entries page by page with maxBytes.
+	utilities.Logger.Logf("Testing read entries with max bytes.")
+	maxBytes := 100 * len(generatePayloadWithEntryID(0))
+	allEntries := make([]*pkg.LedgerEntry, 0)
+	for fromEntryID := 0; ; {
+		entries, err := thisLedger.ReadEntries(fromEntryID, nEntries, maxBytes)
+		utilities.Logger.FatalIfErr(err, "Failed to read entries: %v", err)
+		if len(entries) == 0 {
+			break
+		}
+		nBytes := 0
+		for _, entry := range entries {
+			nBytes += len(entry.Payload)
+		}
+		if nBytes > maxBytes {
+			t.Fatalf("Expected entries of at most %d bytes, got %d entries of %d bytes.", maxBytes, len(entries), nBytes)
+		}
+		allEntries = append(allEntries, entries...)
+		fromEntryID = entries[len(entries)-1].EntryID + 1
+	}
+	expectEntries(allEntries, 0, nEntries-1)
+	entries, err = thisLedger.ReadEntries(0, nEntries, 1)
+	utilities.Logger.FatalIfErr(err, "Failed to read entries: %v", err)
+	if len(entries) != 1 {
+		t.Fatalf("Expected the first entry to be returned even if it exceeds max bytes, got %d entries.", len(entries))
+	}
+
+	utilities.Logger.Logf("TestReadEntries: %s", color.HiGreenString("PASS"))
+}
+
+func TestTailLedger(t *testing.T) {
+	utilities.Logger.Logf("TestTailLedger: Start.")
+	const ledgerID = uint64(11)
+	const deletedLedgerID = uint64(12)
+	const nEntries = 10000
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	setup(config)
+	defer clean()
+
+	thisLedger, err := ledger.NewLedger(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to create new ledger: %v", err)
+
+	// Check: the tailing reader receives all the entries in order while they are appended, and stops after the
+	// ledger is closed.
+	utilities.Logger.Logf("Testing tail ledger.")
+	tailedEntries := make([]*pkg.LedgerEntry, 0, nEntries)
+	tailResult := make(chan error, 1)
+	go func() {
+		fromEntryID := 0
+		for {
+			entries, err := thisLedger.WaitForEntries(context.Background(), fromEntryID, 0)
+			if err != nil {
+				tailResult <- err
+				return
+			}
+			tailedEntries = append(tailedEntries, entries...)
+			fromEntryID = entries[len(entries)-1].EntryID + 1
+		}
+	}()
+	writeWaitGroup := sync.WaitGroup{}
+	writeWaitGroup.Add(nEntries)
+	for i := 0; i < nEntries; i++ {
+		go func() {
+			defer writeWaitGroup.Done()
+			_, err := thisLedger.PutEntry(context.Background(), generatePayloadWithEntryID(i))
+			utilities.Logger.FatalIfErr(err, "Failed to put entry: %v", err)
+		}()
+	}
+	writeWaitGroup.Wait()
+	_, err = thisLedger.Close()
+	utilities.Logger.FatalIfErr(err, "Failed to close ledger: %v", err)
+	if err := <-tailResult; !errors.Is(err, porage.ErrLedgerClosed) {
+		t.Fatalf("Expected the tailing read to end with %v, got %v.", porage.ErrLedgerClosed, err)
+	}
+	if len(tailedEntries) != nEntries {
+		t.Fatalf("Expected %d tailed entries, got %d.", nEntries, len(tailedEntries))
+	}
+	for i, entry := range tailedEntries {
+		if entry.EntryID != i {
+			t.Fatalf("Expected tailed entry %d, got %d.", i, entry.EntryID)
+		}
+	}
+
+	// Check: the tailing reader gives up when the context is done.
+	utilities.Logger.Logf("Testing tail ledger with canceled context.")
+	deletedLedger, err := ledger.NewLedger(deletedLedgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to create new ledger: %v", err)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := deletedLedger.WaitForEntries(ctx, 0, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected %v, got %v.", context.DeadlineExceeded, err)
+	}
+
+	// Check: the tailing reader stops when the ledger is deleted.
+	utilities.Logger.Logf("Testing tail deleted ledger.")
+	go func() {
+		_, err := deletedLedger.WaitForEntries(context.Background(), 0, 0)
+		tailResult <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	err = deletedLedger.Delete()
+	utilities.Logger.FatalIfErr(err, "Failed to delete ledger: %v", err)
+	if err := <-tailResult; !errors.Is(err, porage.ErrLedgerNotFound) {
+		t.Fatalf("Expected the tailing read to end with %v, got %v.", porage.ErrLedgerNotFound, err)
+	}
+
+	utilities.Logger.Logf("TestTailLedger: %s", color.HiGreenString("PASS"))
+}
+
+func TestAppendEntries(t *testing.T) {
+	utilities.Logger.Logf("TestAppendEntries: Start.")
+	const ledgerID = uint64(13)
+	const nBatches = 100
+	const batchSize = 100
+	const nEntries = nBatches * batchSize
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	setup(config)
+
+	thisLedger, err := ledger.NewLedger(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to create new ledger: %v", err)
+
+	// Check: the entries of each concurrent batch are assigned consecutive entry IDs in the order of the payloads.
+	utilities.Logger.Logf("Testing put entries concurrently.")
+	expectedPayloads := make([][]byte, nEntries)
+	expectedPayloadsLock := sync.Mutex{}
+	writeWaitGroup := sync.WaitGroup{}
+	writeWaitGroup.Add(nBatches)
+	for batch := 0; batch < nBatches; batch++ {
+		go func() {
+			defer writeWaitGroup.Done()
+			payloads := make([][]byte, 0, batchSize)
+			for i := 0; i < batchSize; i++ {
+				payloads = append(payloads, []byte(fmt.Sprintf("batch-%d-%d", batch, i)))
+			}
+			entryIDs, err := thisLedger.PutEntries(context.Background(), payloads)
+			utilities.Logger.FatalIfErr(err, "Failed to put entries: %v", err)
+			if len(entryIDs) != batchSize {
+				t.Errorf("Expected %d entry IDs, got %d.", batchSize, len(entryIDs))
+				return
+			}
+			expectedPayloadsLock.Lock()
+			defer expectedPayloadsLock.Unlock()
+			for i, entryID := range entryIDs {
+				if entryID != entryIDs[0]+i || expectedPayloads[entryID] != nil {
+					t.Errorf("Unexpected entry ID %d at %d of batch %v.", entryID, i, entryIDs)
+					return
+				}
+				expectedPayloads[entryID] = payloads[i]
+			}
+		}()
+	}
+	writeWaitGroup.Wait()
+	if t.Failed() {
+		t.FailNow()
+	}
+
+	// Check: the pipelined batches are assigned entry IDs in the order they are appended.
+	utilities.Logger.Logf("Testing pipelined append entries.")
+	pendingAppends := make([]*ledger.PendingAppend, 0, nBatches)
+	for batch := 0; batch < nBatches; batch++ {
+		payloads := make([][]byte, 0, batchSize)
+		for i := 0; i < batchSize; i++ {
+			payloads = append(payloads, generatePayloadWithEntryID(nEntries+batch*batchSize+i))
+		}
+		pendingAppend, err := thisLedger.AppendEntries(context.Background(), payloads)
+		utilities.Logger.FatalIfErr(err, "Failed to append entries: %v", err)
+		pendingAppends = append(pendingAppends, pendingAppend)
+	}
+	for batch, pendingAppend := range pendingAppends {
+		err := pendingAppend.Wait(context.Background())
+		utilities.Logger.FatalIfErr(err, "Failed to wait for appended entries: %v", err)
+		for i, entryID := range pendingAppend.EntryIDs() {
+			if entryID != nEntries+batch*batchSize+i {
+				t.Fatalf("Expected entry ID %d, got %d.", nEntries+batch*batchSize+i, entryID)
+			}
+		}
+	}
+	for entryID := nEntries; entryID < 2*nEntries; entryID++ {
+		expectedPayloads = append(expectedPayloads, generatePayloadWithEntryID(entryID))
+	}
+
+	// Check: an empty batch has no effect and a fenced ledger rejects batches.
+	utilities.Logger.Logf("Testing append empty batch and fenced ledger.")
+	entryIDs, err := thisLedger.PutEntries(context.Background(), nil)
+	utilities.Logger.FatalIfErr(err, "Failed to put empty batch: %v", err)
+	if len(entryIDs) != 0 {
+		t.Fatalf("Expected no entry ID for an empty batch, got %v.", entryIDs)
+	}
+	_, err = thisLedger.Fence()
+	utilities.Logger.FatalIfErr(err, "Failed to fence ledger: %v", err)
+	if _, err := thisLedger.PutEntries(context.Background(), [][]byte{generatePayloadWithEntryID(2 * nEntries)}); !errors.Is(err, porage.ErrLedgerFenced) {
+		t.Fatalf("Expected %v, got %v.", porage.ErrLedgerFenced, err)
+	}
+
+	// Check: the batched entries survive the recovery.
+	utilities.Logger.Logf("Testing batched entries recovery.")
+	clean()
+	setup(config)
+	defer clean()
+	ledgers, err := recovery.Recover()
+	utilities.Logger.FatalIfErr(err, "Failed to recover ledgers: %v", err)
+	if len(ledgers) != 1 {
+		t.Fatalf("Expected 1 recovered ledger, got %d.", len(ledgers))
+	}
+	entries, err := ledgers[0].ReadEntries(0, 2*nEntries-1, 0)
+	utilities.Logger.FatalIfErr(err, "Failed to read entries: %v", err)
+	if len(entries) != 2*nEntries {
+		t.Fatalf("Expected %d entries, got %d.", 2*nEntries, len(entries))
+	}
+	for entryID, entry := range entries {
+		if entry.EntryID != entryID {
+			t.Fatalf("Expected entry %d, got %d.", entryID, entry.EntryID)
+		}
+		expectPayloadEq(t, expectedPayloads[entryID], entry.Payload)
+	}
+
+	utilities.Logger.Logf("TestAppendEntries: %s", color.HiGreenString("PASS"))
+}
+
+func TestAppendEntryContext(t *testing.T) {
+	utilities.Logger.Logf("TestAppendEntryContext: Start.")
+	const ledgerID = uint64(14)
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	// Hold the group commit long enough for the deadline of an append to be exceeded while waiting for it.
+	config.Journal.GroupCommitInterval = 3000
+	setup(config)
+	defer clean()
+
+	thisLedger, err := ledger.NewLedger(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to create new ledger: %v", err)
+
+	// Check: an append with a canceled context is rejected without being appended.
+	utilities.Logger.Logf("Testing append with canceled context.")
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := thisLedger.PutEntry(canceledCtx, generatePayloadWithEntryID(0)); !errors.Is(err, context.Canceled) || errors.Is(err, porage.ErrDurabilityUnknown) {
+		t.Fatalf("Expected %v, got %v.", context.Canceled, err)
+	}
+
+	// Check: an append whose deadline is exceeded while waiting for the journal commit reports the entry whose
+	// durability is unknown, and the entry is persisted once the commit completes.
+	utilities.Logger.Logf("Testing append with exceeded deadline.")
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = thisLedger.PutEntry(ctx, generatePayloadWithEntryID(0))
+	var durabilityUnknownErr *porage.DurabilityUnknownError
+	if !errors.As(err, &durabilityUnknownErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected a durability unknown error, got %v.", err)
+	}
+	if durabilityUnknownErr.FirstEntryID != 0 || durabilityUnknownErr.LastEntryID != 0 {
+		t.Fatalf("Expected the durability of entry 0 to be unknown, got [%d, %d].",
+			durabilityUnknownErr.FirstEntryID, durabilityUnknownErr.LastEntryID)
+	}
+
+	// Check: a retry of the entry while its journal commit is held is not reported as persisted, and succeeds once
+	// the commit completes.
+	utilities.Logger.Logf("Testing retry of the entry whose durability is unknown.")
+	retryCtx, retryCancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer retryCancel()
+	err = thisLedger.PutEntryWithID(retryCtx, 0, generatePayloadWithEntryID(0))
+	if !errors.As(err, &durabilityUnknownErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected a durability unknown error while the journal commit is held, got %v.", err)
+	}
+	err = thisLedger.PutEntryWithID(context.Background(), 0, generatePayloadWithEntryID(0))
+	utilities.Logger.FatalIfErr(err, "Failed to retry entry: %v", err)
+	confirmedCtx, confirmedCancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer confirmedCancel()
+	entries, err := thisLedger.WaitForEntries(confirmedCtx, 0, 0)
+	utilities.Logger.FatalIfErr(err, "Failed to wait for the retried entry: %v", err)
+	if len(entries) != 1 {
+		t.Fatalf("Expected the retried entry to be confirmed, got %d entries.", len(entries))
+	}
+
+	lastEntryID, err := thisLedger.Fence()
+	utilities.Logger.FatalIfErr(err, "Failed to fence ledger: %v", err)
+	if lastEntryID != 0 {
+		t.Fatalf("Expected last entry ID 0, got %d.", lastEntryID)
+	}
+	entry, err := thisLedger.GetEntry(0)
+	utilities.Logger.FatalIfErr(err, "Failed to get entry: %v", err)
+	if entry == nil {
+		t.Fatalf("Entry 0 not found.")
+	}
+	expectPayloadEq(t, generatePayloadWithEntryID(0), entry.Payload)
+
+	utilities.Logger.Logf("TestAppendEntryContext: %s", color.HiGreenString("PASS"))
+}
+
+// expectClosedLedger checks that the appends to the ledger are rejected while its nEntries entries can be read.
+func TestTrimLedger(t *testing.T) {
+	utilities.Logger.Logf("TestTrimLedger: Start.")
+	const ledgerID = uint64(25)
+	const nEntries = 1000
+	const untilEntryID = 499
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	setup(config)
+
+	thisLedger, err := ledger.NewLedger(ledgerID)
+	utilities.Logger.FatalIfErr(err, "Failed to create new ledger: %v", err)
+	for entryID := 0; entryID < nEntries; entryID++ {
+		_, err := thisLedger.PutEntry(context.Background(), generatePayloadWithEntryID(entryID))
+		utilities.Logger.FatalIfErr(err, "Failed to put entry: %v", err)
+	}
+
+	// Check: the trimmed entries can not be read, while the later ones can.
+	utilities.Logger.Logf("Testing trim ledger.")
+	err = thisLedger.Trim(untilEntryID)
+	utilities.Logger.FatalIfErr(err, "Failed to trim ledger: %v", err)
+	expectTrimmedLedger(t, thisLedger, untilEntryID, nEntries)
+	if err := thisLedger.PutEntryWithID(context.Background(), 10, generatePayloadWithEntryID(10)); !errors.Is(err, porage.ErrEntryTrimmed) {
+		t.Fatalf("Expected %v when putting a trimmed entry, got %v.", porage.ErrEntryTrimmed, err)
+	}
+
+	// Check: trimming beyond the ledger fails, and trimming the trimmed entries has no effect.
+	if err := thisLedger.Trim(2 * nEntries); !errors.Is(err, porage.ErrInvalidEntryID) {
+		t.Fatalf("Expected %v, got %v.", porage.ErrInvalidEntryID, err)
+	}
+	err = thisLedger.Trim(100)
+	utilities.Logger.FatalIfErr(err, "Failed to trim ledger again: %v", err)
+	if thisLedger.LowWaterMark() != untilEntryID+1 {
+		t.Fatalf("Expected low-water mark %d, got %d.", untilEntryID+1, thisLedger.LowWaterMark())
+	}
+
+	// Check: the trim survives the recovery, after the entries left are flushed.
+	utilities.Logger.Logf("Testing trimmed ledger recovery.")
+	waitForIndexedEntries(thisLedger, nEntries-untilEntryID-1)
+	length, err := thisLedger.Length()
+	utilities.Logger.FatalIfErr(err, "Failed to get ledger length: %v", err)
+	if length != nEntries {
+		t.Fatalf("Expected length %d, got %d.", nEntries, length)
+	}
+	clean()
+	setup(config)
+	ledgers, err := recovery.Recover()
+	utilities.Logger.FatalIfErr(err, "Failed to recover ledgers: %v", err)
+	thisLedger = ledgers[0]
+	expectTrimmedLedger(t, thisLedger, untilEntryID, nEntries)
+
+	// Check: the entries are appended after the trimmed ones even if all the entries are trimmed.
+	utilities.Logger.Logf("Testing fully trimmed ledger recovery.")
+	err = thisLedger.Trim(nEntries - 1)
+	utilities.Logger.FatalIfErr(err, "Failed to trim all the entries: %v", err)
+	clean()
+	setup(config)
+	defer clean()
+	ledgers, err = recovery.Recover()
+	utilities.Logger.FatalIfErr(err, "Failed to recover ledgers: %v", err)
+	thisLedger = ledgers[0]
+	length, err = thisLedger.Length()
+	utilities.Logger.FatalIfErr(err, "Failed to get ledger length: %v", err)
+	if length != nEntries {
+		t.Fatalf("Expected length %d, got %d.", nEntries, length)
+	}
+	entryID, err := thisLedger.PutEntry(context.Background(), generatePayloadWithEntryID(nEntries))
+	utilities.Logger.FatalIfErr(err, "Failed to put entry: %v", err)
+	if entryID != nEntries {
+		t.Fatalf("Expected entry ID %d, got %d.", nEntries, entryID)
+	}
+	entry, err := thisLedger.GetEntry(entryID)
+	utilities.Logger.FatalIfErr(err, "Failed to get entry: %v", err)
+	expectPayloadEq(t, generatePayloadWithEntryID(nEntries), entry.Payload)
+
+	utilities.Logger.Logf("TestTrimLedger: %s", color.HiGreenString("PASS"))
+}
+
+func TestRetentionPolicy(t *testing.T) {
+	utilities.Logger.Logf("TestRetentionPolicy: Start.")
+	const entriesLedgerID = uint64(26)
+	const bytesLedgerID = uint64(27)
+	const ageLedgerID = uint64(28)
+	const nEntries = 1000
+	const maxEntries = 100
+	const maxBytes = 16 << 10
+	const maxAge = 5 * time.Second
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	setup(config)
+	ledger.EnableRetentionWorker()
+
+	// Check: a negative limit is rejected.
+	if _, err := ledger.NewLedgerWithOptions(entriesLedgerID, ledger.CreateOptions{RetentionPolicy: ledger.RetentionPolicy{MaxEntries: -1}}); !errors.Is(err, porage.ErrInvalidRetentionPolicy) {
+		t.Fatalf("Expected %v, got %v.", porage.ErrInvalidRetentionPolicy, err)
+	}
+
+	retentionPolicies := map[uint64]ledger.RetentionPolicy{
+		entriesLedgerID: {MaxEntries: maxEntries},
+		bytesLedgerID:   {MaxBytes: maxBytes},
+		ageLedgerID:     {MaxAge: maxAge},
+	}
+	ledgers := make(map[uint64]*ledger.Ledger)
+	// The ledger retained by age is written last, so that its entries do not expire before the first check.
+	for _, ledgerID := range []uint64{entriesLedgerID, bytesLedgerID, ageLedgerID} {
+		thisLedger, err := ledger.NewLedgerWithOptions(ledgerID, ledger.CreateOptions{RetentionPolicy: retentionPolicies[ledgerID]})
+		utilities.Logger.FatalIfErr(err, "Failed to create new ledger: %v", err)
+		for entryID := 0; entryID < nEntries; entryID++ {
+			_, err := thisLedger.PutEntry(context.Background(), generatePayloadWithEntryID(entryID))
+			utilities.Logger.FatalIfErr(err, "Failed to put entry: %v", err)
+		}
+		ledgers[ledgerID] = thisLedger
+	}
+	_, err = ledgers[ageLedgerID].Close()
+	utilities.Logger.FatalIfErr(err, "Failed to close ledger: %v", err)
+
+	// Check: the oldest entries beyond the limits are trimmed after they are flushed.
+	utilities.Logger.Logf("Testing retention by entries and bytes.")
+	waitForRetention := func(ledgerID uint64, isWithinLimits func(info *ledger.Info) bool) {
+		thisLedger := ledgers[ledgerID]
+		utilities.WaitFor(waitTimeout, fmt.Sprintf("the retention policy of ledger %d", ledgerID), func() (bool, error) {
+			info, err := thisLedger.Info()
+			if err != nil {
+				return false, err
+			}
+			isFlushed := thisLedger.LowWaterMark()+info.EntryCount == nEntries
+			return isFlushed && isWithinLimits(info), nil
+		})
+	}
+	waitForRetention(entriesLedgerID, func(info *ledger.Info) bool { return info.EntryCount <= maxEntries })
+	waitForRetention(bytesLedgerID, func(info *ledger.Info) bool { return info.ByteSize <= maxBytes })
+	if lowWaterMark := ledgers[entriesLedgerID].LowWaterMark(); lowWaterMark != nEntries-maxEntries {
+		t.Fatalf("Expected low-water mark %d, got %d.", nEntries-maxEntries, lowWaterMark)
+	}
+	if _, err := ledgers[entriesLedgerID].GetEntry(nEntries - maxEntries - 1); !errors.Is(err, porage.ErrEntryTrimmed) {
+		t.Fatalf("Expected %v, got %v.", porage.ErrEntryTrimmed, err)
+	}
+	entries, err := ledgers[entriesLedgerID].ReadEntries(nEntries-maxEntries, nEntries-1, 0)
+	utilities.Logger.FatalIfErr(err, "Failed to read entries: %v", err)
+	if len(entries) != maxEntries {
+		t.Fatalf("Expected %d entries, got %d.", maxEntries, len(entries))
+	}
+	if lowWaterMark := ledgers[bytesLedgerID].LowWaterMark(); lowWaterMark <= 0 || lowWaterMark >= nEntries {
+		t.Fatalf("Expected some of the entries to be trimmed by bytes, got low-water mark %d.", lowWaterMark)
+	}
+	// The policy is enforced on the payload size reported by Info, so the entries are kept up to exactly maxBytes.
+	info, err := ledgers[bytesLedgerID].Info()
+	utilities.Logger.FatalIfErr(err, "Failed to get ledger info: %v", err)
+	if lowWaterMark := ledgers[bytesLedgerID].LowWaterMark(); info.ByteSize > maxBytes ||
+		info.ByteSize+len(generatePayloadWithEntryID(lowWaterMark-1)) <= maxBytes {
+		t.Fatalf("Expected at most %d bytes kept without room for entry %d, got %d bytes.", maxBytes, lowWaterMark-1, info.ByteSize)
+	}
+	if ledgers[ageLedgerID].IsDeleted() {
+		t.Fatalf("Expected ledger %d to be kept before its entries expire.", ageLedgerID)
+	}
+
+	// Check: the closed ledger is deleted once all its entries expire.
+	utilities.Logger.Logf("Testing retention by age.")
+	utilities.WaitFor(maxAge+waitTimeout, fmt.Sprintf("ledger %d to be deleted", ageLedgerID), func() (bool, error) {
+		return ledgers[ageLedgerID].IsDeleted(), nil
+	})
+	if ledgers[entriesLedgerID].LowWaterMark() != nEntries-maxEntries {
+		t.Fatalf("Expected ledger %d to keep %d entries, got low-water mark %d.", entriesLedgerID, maxEntries, ledgers[entriesLedgerID].LowWaterMark())
+	}
+
+	// Check: the retention policies survive the recovery.
+	utilities.Logger.Logf("Testing retention policy recovery.")
+	clean()
+	setup(config)
+	defer clean()
+	recoveredLedgers, err := recovery.Recover()
+	utilities.Logger.FatalIfErr(err, "Failed to recover ledgers: %v", err)
+	if len(recoveredLedgers) != 2 {
+		t.Fatalf("Expected 2 recovered ledgers, got %d.", len(recoveredLedgers))
+	}
+	for _, thisLedger := range recoveredLedgers {
+		if thisLedger.RetentionPolicy() != retentionPolicies[thisLedger.LedgerID()] {
+			t.Fatalf("Expected retention policy %+v of ledger %d, got %+v.", retentionPolicies[thisLedger.LedgerID()], thisLedger.LedgerID(), thisLedger.RetentionPolicy())
+		}
+	}
+
+	utilities.Logger.Logf("TestRetentionPolicy: %s", color.HiGreenString("PASS"))
+}
+
+func TestLedgerInfo(t *testing.T) {
+	utilities.Logger.Logf("TestLedgerInfo: Start.")
+	const ledgerID = uint64(29)
+	const legacyLedgerID = uint64(30)
+	const nEntries = 1000
+	const untilEntryID = 99
+
+	setCleanEnvironment()
+	config, err := pkg.ParseConfigFile("./config.toml")
+	if err != nil {
+		panic(err)
+	}
+	setup(config)
+
+	properties := map[string]string{"owner": "integration-test", "purpose": "ledger-info"}
+	beforeCreation := time.Now().Truncate(time.Millisecond)
+	thisLedger, err := ledger.NewLedgerWithOptions(ledgerID, ledger.CreateOptions{Properties: properties})
+	utilities.Logger.FatalIfErr(err, "Failed to create new ledger: %v", err)
+	for entryID := 0; entryID < nEntries; entryID++ {
+		_, err := thisLedger.PutEntry(context.Background(), generatePayloadWithEntryID(entryID))
+		utilities.Logger.FatalIfErr(err, "Failed to put entry: %v", err)
+	}
+	afterAppend := time.Now()
+
+	// Check: the metadata of a new ledger, whose entries are counted after they are flushed.
+	utilities.Logger.Logf("Testing ledger info.")
+	waitForIndexedEntries(thisLedger, nEntries)
+	info, err := thisLedger.Info()
+	utilities.Logger.FatalIfErr(err, "Failed to get ledger info: %v", err)
+	if info.CreationTime.Before(beforeCreation) || info.CreationTime.After(afterAppend) {
+		t.Fatalf("Expected creation time between %v and %v, got %v.", beforeCreation, afterAppend, info.CreationTime)
+	}
+	if info.State != ledger.LedgerStateOpen || info.FirstEntryID != 0 || info.EntryCount != nEntries {
+		t.Fatalf("Expected an open ledger with entries [0, %d), got %+v.", nEntries, info)
+	}
+	if info.ByteSize != payloadBytes(0, nEntries) {
+		t.Fatalf("Expected %d bytes, got %d.", payloadBytes(0, nEntries), info.ByteSize)
+	}
+	if info.LastAppendTime.Before(info.CreationTime.Truncate(time.Millisecond)) || info.LastAppendTime.After(afterAppend) {
+		t.Fatalf("Expected last append time between %v and %v, got %v.", info.CreationTime, afterAppend, info.LastAppendTime)
+	}
+	expectPropertiesEq(t, properties, info.Properties)
+
+	// Check: the trimmed entries are not counted, and the metadata survives the recovery.
+	utilities.Logger.Logf("Testing ledger info recovery.")
+	err = thisLedger.Trim(untilEntryID)
+	utilities.Logger.FatalIfErr(err, "Failed to trim ledger: %v", err)
+	info, err = thisLedger.Info()
+	utilities.Logger.FatalIfErr(err, "Failed to get ledger info: %v", err)
+	if info.FirstEntryID != untilEntryID+1 || info.EntryCount != nEntries-untilEntryID-1 ||
+		info.ByteSize != payloadBytes(untilEntryID+1, nEntries) {
+		t.Fatalf("Expected entries [%d, %d) right after the trim, got %+v.", untilEntryID+1, nEntries, info)
+	}
+	_, err = thisLedger.Close()
+	utilities.Logger.FatalIfErr(err, "Failed to close ledger: %v", err)
+	creationTime := info.CreationTime.Truncate(time.Millisecond)
+	clean()
+
+	// A ledger file created before the metadata is empty.
+	legacyLedgerFilePath := path.Join(config.Ledger.StoragePath, fmt.Sprintf("ledger_%d", legacyLedgerID))
+	if _, err := os.Create(legacyLedgerFilePath); err != nil {
+		panic(err)
+	}
+	setup(config)
+	defer clean()
+	ledgers, err := recovery.Recover()
+	utilities.Logger.FatalIfErr(err, "Failed to recover ledgers: %v", err)
+	if len(ledgers) != 2 {
+		t.Fatalf("Expected 2 recovered ledgers, got %d.", len(ledgers))
+	}
+	for _, recoveredLedger := range ledgers {
+		info, err := recoveredLedger.Info()
+		utilities.Logger.FatalIfErr(err, "Failed to get ledger info: %v", err)
+		switch recoveredLedger.LedgerID() {
+		case ledgerID:
+			if !info.CreationTime.Equal(creationTime) {
+				t.Fatalf("Expected creation time %v, got %v.", creationTime, info.CreationTime)
+			}
+			if info.State != ledger.LedgerStateClosed || info.FirstEntryID != untilEntryID+1 || info.EntryCount != nEntries-untilEntryID-1 ||
+				info.ByteSize != payloadBytes(untilEntryID+1, nEntries) {
+				t.Fatalf("Expected a closed ledger with entries [%d, %d), got %+v.", untilEntryID+1, nEntries, info)
+			}
+			expectPropertiesEq(t, properties, info.Properties)
+		case legacyLedgerID:
+			if info.CreationTime.IsZero() || len(info.Properties) != 0 || info.FirstEntryID != -1 || info.EntryCount != 0 {
+				t.Fatalf("Expected an empty legacy ledger with a creation time, got %+v.", info)
+			}
+		}
+	}
+
+	utilities.Logger.Logf("TestLedgerInfo: %s", color.HiGreenString("PASS"))
+}
+
+// payloadBytes returns the total payload size of the entries in [fromEntryID, toEntryID).
+func payloadBytes(fromEntryID int, toEntryID int) int {
+	size := 0
+	for entryID := fromEntryID; entryID < toEntryID; entryID++ {
+		size += len(generatePayloadWithEntryID(entryID))
+	}
+	return size
+}
+
+func expectPropertiesEq(t *testing.T, expected, actual map[string]string) {
+	if len(expected) != len(actual) {
+		t.Fatalf("Expected properties %v, got %v.", expected, actual)
+	}
+	for key, value := range expected {
+		if actual[key] != value {
+			t.Fatalf("Expected properties %v, got %v.", expected, actual)
+		}
+	}
+}
+
+func expectTrimmedLedger(t *testing.T, thisLedger *ledger.Ledger, untilEntryID int, nEntries int) {
+	for _, entryID := range []int{0, untilEntryID} {
+		if _, err := thisLedger.GetEntry(entryID); !errors.Is(err, porage.ErrEntryTrimmed) {
+			t.Fatalf("Expected %v for entry %d, got %v.", porage.ErrEntryTrimmed, entryID, err)
+		}
+	}
+	if _, err := thisLedger.ReadEntries(0, nEntries-1, 0); !errors.Is(err, porage.ErrEntryTrimmed) {
+		t.Fatalf("Expected %v for the range read, got %v.", porage.ErrEntryTrimmed, err)
+	}
+	entries, err := thisLedger.ReadEntries(untilEntryID+1, nEntries-1, 0)
+	utilities.Logger.FatalIfErr(err, "Failed to read entries: %v", err)
+	if len(entries) != nEntries-untilEntryID-1 {
+		t.Fatalf("Expected %d entries, got %d.", nEntries-untilEntryID-1, len(entries))
+	}
+	for _, entry := range entries {
+		expectPayloadEq(t, generatePayloadWithEntryID(entry.EntryID), entry.Payload)
+	}
+}
+
+func expectClosedLedger(t *testing.T, thisLedger *ledger.Ledger, nEntries int) {
+	if _, err := thisLedger.PutEntry(context.Background(), generatePayloadWithEntryID(nEntries)); !errors.Is(err, porage.ErrLedgerClosed) {
+		t.Fatalf("Expected %v, got %v.", porage.ErrLedgerClosed, err)
+	}
+	if err := thisLedger.PutEntryWithID(context.Background(), nEntries, generatePayloadWithEntryID(nEntries)); !errors.Is(err, porage.ErrLedgerClosed) {
+		t.Fatalf("Expected %v, got %v.", porage.ErrLedgerClosed, err)
+	}
+	for entryID := 0; entryID < nEntries; entryID++ {
+		entry, err := thisLedger.GetEntry(entryID)
+		utilities.Logger.FatalIfErr(err, "Failed to get entry: %v", err)
+		if entry == nil {
+			t.Fatalf("Entry %d not found.", entryID)
+		}
+		expectPayloadEq(t, generatePayloadWithEntryID(entryID), entry.Payload)
+	}
+}
+
+// waitForIndexedEntries waits until nEntries entries of the ledger are indexed, i.e. flushed by the entry logger.
+func waitForIndexedEntries(thisLedger *ledger.Ledger, nEntries int) {
+	description := fmt.Sprintf("%d entries of ledger %d to be indexed", nEntries, thisLedger.LedgerID())
+	utilities.WaitFor(waitTimeout, description, func() (bool, error) {
+		info, err := thisLedger.Info()
+		if err != nil {
+			return false, err
+		}
+		return info.EntryCount >= nEntries, nil
+	})
+}
+
 func generatePayloadWithEntryID(entryID int) []byte {
 	return []byte(fmt.Sprintf("%24s%d", "xxxxxxxxxxxxxxxxxxxxxxxx", entryID))
 }
@@ -235,4 +1204,6 @@ func setup(config *pkg.Config) {
 func clean() {
 	journal.Stop()
 	ledger.Stop()
+	entrylogger.Stop()
+	index.Stop()
 }
@@ -0,0 +1,270 @@
+package metadatatest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"porage/pkg/metadata"
+	"porage/test/utilities"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"go.etcd.io/etcd/server/v3/embed"
+)
+
+// Test Scenario:
+//  1. Get, List, Put and Delete with the versions of the keys.
+//  2. CompareAndSwap creating and updating the keys only at the expected versions.
+//  3. Watch receiving the changes of the keys with a prefix in order.
+//  4. Leases deleting their keys when they are revoked or expire without being renewed.
+//  5. The above on the LocalStore in memory, the LocalStore persisted to a file, and the EtcdStore on an embedded etcd.
+//  6. The LocalStore reopened from its file, which keeps the keys without a lease.
+
+var (
+	dataDir = "./_data"
+)
+
+const (
+	// leaseTTL is the TTL of the leases in the tests, which is above the minimum TTL of etcd.
+	leaseTTL = 2 * time.Second
+	// eventTimeout bounds the wait for an event of a watch.
+	eventTimeout = 5 * leaseTTL
+)
+
+func TestLocalStore(t *testing.T) {
+	utilities.Logger.Logf("TestLocalStore: Start.")
+	store := metadata.NewLocalStore()
+	defer store.Close()
+	testMetadataStore(t, store)
+	utilities.Logger.Logf("TestLocalStore: %s", color.HiGreenString("PASS"))
+}
+
+func TestPersistedLocalStore(t *testing.T) {
+	utilities.Logger.Logf("TestPersistedLocalStore: Start.")
+	setCleanEnvironment()
+	filePath := path.Join(dataDir, "metadata.json")
+	store, err := metadata.OpenLocalStore(filePath)
+	utilities.Logger.FatalIfErr(err, "Failed to open local store: %v", err)
+	testMetadataStore(t, store)
+
+	// Check: the keys without a lease are kept after reopening, while the keys with a lease are not.
+	utilities.Logger.Logf("Testing reopening.")
+	ctx := context.Background()
+	_, err = store.Put(ctx, "/reopen/kept", []byte("Kept"), metadata.NoLease)
+	utilities.Logger.FatalIfErr(err, "Failed to put key: %v", err)
+	leaseID, err := store.GrantLease(ctx, leaseTTL)
+	utilities.Logger.FatalIfErr(err, "Failed to grant lease: %v", err)
+	_, err = store.Put(ctx, "/reopen/leased", []byte("Leased"), leaseID)
+	utilities.Logger.FatalIfErr(err, "Failed to put key: %v", err)
+	store.Close()
+	if _, err := store.Get(ctx, "/reopen/kept"); !errors.Is(err, metadata.ErrStoreClosed) {
+		t.Fatalf("Expected %v, got %v.", metadata.ErrStoreClosed, err)
+	}
+
+	store, err = metadata.OpenLocalStore(filePath)
+	utilities.Logger.FatalIfErr(err, "Failed to reopen local store: %v", err)
+	defer store.Close()
+	keyValues, err := store.List(ctx, "/reopen/")
+	utilities.Logger.FatalIfErr(err, "Failed to list keys: %v", err)
+	if len(keyValues) != 1 || keyValues[0].Key != "/reopen/kept" || string(keyValues[0].Value) != "Kept" || keyValues[0].Version != 1 {
+		t.Fatalf("Expected only /reopen/kept after reopening, got %v.", keyValues)
+	}
+	keyValues, err = store.List(ctx, "/kv/")
+	utilities.Logger.FatalIfErr(err, "Failed to list keys: %v", err)
+	if len(keyValues) == 0 {
+		t.Fatalf("Expected the keys of the test kept after reopening.")
+	}
+
+	utilities.Logger.Logf("TestPersistedLocalStore: %s", color.HiGreenString("PASS"))
+}
+
+func TestEtcdStore(t *testing.T) {
+	utilities.Logger.Logf("TestEtcdStore: Start.")
+	setCleanEnvironment()
+	config := embed.NewConfig()
+	config.Dir = path.Join(dataDir, "etcd")
+	config.LogLevel = "error"
+	clientURL, peerURL := localURL(t), localURL(t)
+	config.ListenClientUrls, config.AdvertiseClientUrls = []url.URL{clientURL}, []url.URL{clientURL}
+	config.ListenPeerUrls, config.AdvertisePeerUrls = []url.URL{peerURL}, []url.URL{peerURL}
+	config.InitialCluster = config.InitialClusterFromName(config.Name)
+	etcd, err := embed.StartEtcd(config)
+	utilities.Logger.FatalIfErr(err, "Failed to start etcd: %v", err)
+	defer etcd.Close()
+	select {
+	case <-etcd.Server.ReadyNotify():
+	case <-time.After(time.Minute):
+		t.Fatalf("Timed out starting etcd.")
+	}
+
+	store, err := metadata.NewEtcdStore([]string{etcd.Clients[0].Addr().String()}, 5*time.Second)
+	utilities.Logger.FatalIfErr(err, "Failed to connect to etcd: %v", err)
+	defer store.Close()
+	testMetadataStore(t, store)
+
+	utilities.Logger.Logf("TestEtcdStore: %s", color.HiGreenString("PASS"))
+}
+
+// testMetadataStore checks the behaviors common to all the MetadataStores.
+func testMetadataStore(t *testing.T, store metadata.MetadataStore) {
+	ctx := context.Background()
+
+	// Check: the version of a key starts at 1 and is increased by each put.
+	utilities.Logger.Logf("Testing get, list, put and delete.")
+	if _, err := store.Get(ctx, "/kv/a"); !errors.Is(err, metadata.ErrKeyNotFound) {
+		t.Fatalf("Expected %v, got %v.", metadata.ErrKeyNotFound, err)
+	}
+	for i := 1; i <= 3; i++ {
+		version, err := store.Put(ctx, "/kv/a", []byte(fmt.Sprintf("Value %d", i)), metadata.NoLease)
+		utilities.Logger.FatalIfErr(err, "Failed to put key: %v", err)
+		if version != int64(i) {
+			t.Fatalf("Expected version %d, got %d.", i, version)
+		}
+	}
+	expectKeyValue(t, store, "/kv/a", "Value 3", 3)
+	for _, key := range []string{"/kv/c", "/kv/b", "/kvx", "/other"} {
+		_, err := store.Put(ctx, key, []byte(key), metadata.NoLease)
+		utilities.Logger.FatalIfErr(err, "Failed to put key: %v", err)
+	}
+	expectKeys(t, store, "/kv/", "/kv/a", "/kv/b", "/kv/c")
+	utilities.Logger.FatalIfErr(store.Delete(ctx, "/kv/b"), "Failed to delete key")
+	utilities.Logger.FatalIfErr(store.Delete(ctx, "/kv/b"), "Failed to delete missing key")
+	expectKeys(t, store, "/kv/", "/kv/a", "/kv/c")
+
+	// Check: CompareAndSwap only succeeds at the expected version, where 0 is a key which does not exist.
+	utilities.Logger.Logf("Testing compare-and-swap.")
+	if _, err := store.CompareAndSwap(ctx, "/kv/a", []byte("Stale"), 2, metadata.NoLease); !errors.Is(err, metadata.ErrVersionConflict) {
+		t.Fatalf("Expected %v, got %v.", metadata.ErrVersionConflict, err)
+	}
+	version, err := store.CompareAndSwap(ctx, "/kv/a", []byte("Swapped"), 3, metadata.NoLease)
+	utilities.Logger.FatalIfErr(err, "Failed to compare and swap: %v", err)
+	if version != 4 {
+		t.Fatalf("Expected version 4, got %d.", version)
+	}
+	expectKeyValue(t, store, "/kv/a", "Swapped", 4)
+	if _, err := store.CompareAndSwap(ctx, "/kv/a", []byte("Created"), 0, metadata.NoLease); !errors.Is(err, metadata.ErrVersionConflict) {
+		t.Fatalf("Expected %v when creating an existing key, got %v.", metadata.ErrVersionConflict, err)
+	}
+	version, err = store.CompareAndSwap(ctx, "/kv/d", []byte("Created"), 0, metadata.NoLease)
+	utilities.Logger.FatalIfErr(err, "Failed to compare and swap: %v", err)
+	if version != 1 {
+		t.Fatalf("Expected version 1, got %d.", version)
+	}
+	expectKeyValue(t, store, "/kv/d", "Created", 1)
+
+	// Check: a watch receives the changes of the keys with its prefix in order.
+	utilities.Logger.Logf("Testing watch.")
+	watchCtx, cancelWatch := context.WithCancel(ctx)
+	eventCh, err := store.Watch(watchCtx, "/watch/")
+	utilities.Logger.FatalIfErr(err, "Failed to watch: %v", err)
+	_, err = store.Put(ctx, "/watch/a", []byte("1"), metadata.NoLease)
+	utilities.Logger.FatalIfErr(err, "Failed to put key: %v", err)
+	_, err = store.Put(ctx, "/unwatched", []byte("1"), metadata.NoLease)
+	utilities.Logger.FatalIfErr(err, "Failed to put key: %v", err)
+	_, err = store.Put(ctx, "/watch/a", []byte("2"), metadata.NoLease)
+	utilities.Logger.FatalIfErr(err, "Failed to put key: %v", err)
+	utilities.Logger.FatalIfErr(store.Delete(ctx, "/watch/a"), "Failed to delete key")
+	expectEvent(t, eventCh, metadata.EventPut, "/watch/a", "1", 1)
+	expectEvent(t, eventCh, metadata.EventPut, "/watch/a", "2", 2)
+	expectEvent(t, eventCh, metadata.EventDelete, "/watch/a", "", 0)
+	cancelWatch()
+	for range eventCh {
+	}
+
+	// Check: the keys of a lease are deleted when it is revoked, or when it expires without being renewed.
+	utilities.Logger.Logf("Testing leases.")
+	if _, err := store.Put(ctx, "/lease/invalid", []byte("Invalid"), metadata.LeaseID(1<<40)); !errors.Is(err, metadata.ErrLeaseNotFound) {
+		t.Fatalf("Expected %v, got %v.", metadata.ErrLeaseNotFound, err)
+	}
+	eventCh, err = store.Watch(ctx, "/lease/")
+	utilities.Logger.FatalIfErr(err, "Failed to watch: %v", err)
+	revokedLeaseID, err := store.GrantLease(ctx, leaseTTL)
+	utilities.Logger.FatalIfErr(err, "Failed to grant lease: %v", err)
+	_, err = store.Put(ctx, "/lease/revoked", []byte("Revoked"), revokedLeaseID)
+	utilities.Logger.FatalIfErr(err, "Failed to put key: %v", err)
+	expectEvent(t, eventCh, metadata.EventPut, "/lease/revoked", "Revoked", 1)
+	if keyValue, err := store.Get(ctx, "/lease/revoked"); err != nil || keyValue.LeaseID != revokedLeaseID {
+		t.Fatalf("Expected the key attached to lease %d, got %v and %v.", revokedLeaseID, keyValue, err)
+	}
+	utilities.Logger.FatalIfErr(store.RevokeLease(ctx, revokedLeaseID), "Failed to revoke lease")
+	expectEvent(t, eventCh, metadata.EventDelete, "/lease/revoked", "", 0)
+	if err := store.KeepAlive(ctx, revokedLeaseID); !errors.Is(err, metadata.ErrLeaseNotFound) {
+		t.Fatalf("Expected %v after revoking, got %v.", metadata.ErrLeaseNotFound, err)
+	}
+
+	renewedLeaseID, err := store.GrantLease(ctx, leaseTTL)
+	utilities.Logger.FatalIfErr(err, "Failed to grant lease: %v", err)
+	_, err = store.Put(ctx, "/lease/renewed", []byte("Renewed"), renewedLeaseID)
+	utilities.Logger.FatalIfErr(err, "Failed to put key: %v", err)
+	expectEvent(t, eventCh, metadata.EventPut, "/lease/renewed", "Renewed", 1)
+	for i := 0; i < 4; i++ {
+		time.Sleep(leaseTTL / 2)
+		utilities.Logger.FatalIfErr(store.KeepAlive(ctx, renewedLeaseID), "Failed to renew lease")
+	}
+	expectKeyValue(t, store, "/lease/renewed", "Renewed", 1)
+	expectEvent(t, eventCh, metadata.EventDelete, "/lease/renewed", "", 0)
+	if _, err := store.Get(ctx, "/lease/renewed"); !errors.Is(err, metadata.ErrKeyNotFound) {
+		t.Fatalf("Expected %v after the lease expired, got %v.", metadata.ErrKeyNotFound, err)
+	}
+	if _, err := store.GrantLease(ctx, 0); !errors.Is(err, metadata.ErrInvalidLeaseTTL) {
+		t.Fatalf("Expected %v, got %v.", metadata.ErrInvalidLeaseTTL, err)
+	}
+}
+
+// expectKeyValue checks the value and the version of a key.
+func expectKeyValue(t *testing.T, store metadata.MetadataStore, key string, value string, version int64) {
+	keyValue, err := store.Get(context.Background(), key)
+	utilities.Logger.FatalIfErr(err, "Failed to get key %s: %v", key, err)
+	if keyValue.Key != key || string(keyValue.Value) != value || keyValue.Version != version {
+		t.Fatalf("Expected %s=%s at version %d, got %s=%s at version %d.", key, value, version, keyValue.Key, keyValue.Value, keyValue.Version)
+	}
+}
+
+// expectKeys checks the keys with prefix in order.
+func expectKeys(t *testing.T, store metadata.MetadataStore, prefix string, keys ...string) {
+	keyValues, err := store.List(context.Background(), prefix)
+	utilities.Logger.FatalIfErr(err, "Failed to list keys: %v", err)
+	if len(keyValues) != len(keys) {
+		t.Fatalf("Expected %d keys with prefix %s, got %d.", len(keys), prefix, len(keyValues))
+	}
+	for i, keyValue := range keyValues {
+		if keyValue.Key != keys[i] {
+			t.Fatalf("Expected key %s, got %s.", keys[i], keyValue.Key)
+		}
+	}
+}
+
+// expectEvent checks the next event of a watch.
+func expectEvent(t *testing.T, eventCh <-chan metadata.Event, eventType metadata.EventType, key string, value string, version int64) {
+	select {
+	case event, ok := <-eventCh:
+		if !ok {
+			t.Fatalf("Expected %s %s, got the watch closed.", eventType, key)
+		}
+		if event.Type != eventType || event.Key != key || string(event.Value) != value || event.Version != version {
+			t.Fatalf("Expected %s %s=%s at version %d, got %s %s=%s at version %d.", eventType, key, value, version, event.Type, event.Key, event.Value, event.Version)
+		}
+	case <-time.After(eventTimeout):
+		t.Fatalf("Timed out waiting for %s %s.", eventType, key)
+	}
+}
+
+// localURL returns the URL of a free local port for etcd.
+func localURL(t *testing.T) url.URL {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to find a free port: %v", err)
+	}
+	defer listener.Close()
+	return url.URL{Scheme: "http", Host: listener.Addr().String()}
+}
+
+func setCleanEnvironment() {
+	os.RemoveAll(dataDir)
+	os.MkdirAll(dataDir, 0755)
+}
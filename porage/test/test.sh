@@ -18,6 +18,19 @@ cd ./integration-test
 PORAGE_LOG_LEVEL=warn go test -coverpkg=../../internal/... -coverprofile=./coverage/coverage.out
 cd ..
 
+# Run tests in distributed-test directory
+echo "Running tests in distributed-test..."
+cd ./distributed-test
+go test
+cd ..
+
+# Run tests in metadata-test directory, which is a module of its own so that the embedded etcd server it tests
+# against is not a dependency of porage
+echo "Running tests in metadata-test..."
+cd ./metadata-test
+go test
+cd ..
+
 # Merge coverage profiles
 echo "Merging coverage profiles..."
 gocovmerge ./e2e-test/coverage/coverage.out  ./integration-test/coverage/coverage.out  > ./coverage/coverage.out
@@ -0,0 +1,25 @@
+package utilities
+
+import (
+	"fmt"
+	"time"
+)
+
+const waitPollInterval = 50 * time.Millisecond
+
+// WaitFor polls condition until it returns true, and panics with the description if it does not before the timeout.
+// An error returned by condition is fatal.
+func WaitFor(timeout time.Duration, description string, condition func() (bool, error)) {
+	deadline := time.Now().Add(timeout)
+	for {
+		ok, err := condition()
+		Logger.FatalIfErr(err, "Failed while waiting for %s: %v", description, err)
+		if ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			panic(fmt.Sprintf("Timed out after %v waiting for %s.", timeout, description))
+		}
+		time.Sleep(waitPollInterval)
+	}
+}